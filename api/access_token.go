@@ -0,0 +1,220 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/sangketkit01/personal-financial/apierror"
+	"github.com/sangketkit01/personal-financial/apitypes"
+	db "github.com/sangketkit01/personal-financial/db/sqlc"
+	"github.com/sangketkit01/personal-financial/token"
+	"github.com/sangketkit01/personal-financial/util"
+)
+
+const (
+	personalAccessTokenPrefix = "pf_"
+	tokenScopesKey            = "token_scopes"
+
+	scopeReadFinancial  = "read:financial"
+	scopeWriteFinancial = "write:financial"
+	scopeReadBudget     = "read:budget"
+	scopeWriteBudget    = "write:budget"
+	scopeAdmin          = "admin"
+
+	// personalAccessTokenTouchInterval is how old last_used_at gets before a request updates it.
+	personalAccessTokenTouchInterval = time.Minute
+)
+
+var errRevokedToken = errors.New("token has been revoked")
+
+// hashToken is how secret tokens are stored: personal access tokens, unlock tokens and household invitations.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// verifyPersonalAccessToken looks up a personal access token and records that it was used.
+// A token that cannot be used fails with token.ErrInvalidToken, token.ErrExpiredToken or errRevokedToken,
+// anything else is a failure of the store.
+func (server *Server) verifyPersonalAccessToken(ctx *gin.Context, accessToken string) (db.PersonalAccessToken, error) {
+	pat, err := server.store.GetPersonalAccessTokenByHash(ctx, hashToken(accessToken))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return pat, token.ErrInvalidToken
+		}
+		return pat, apierror.Internal(err, "cannot get personal access token.")
+	}
+
+	if pat.RevokedAt.Valid {
+		return pat, errRevokedToken
+	}
+
+	if pat.ExpiredAt.Valid && time.Now().After(pat.ExpiredAt.Time) {
+		return pat, token.ErrExpiredToken
+	}
+
+	// last_used_at is only as exact as the interval, so busy tokens don't write on every request
+	if pat.LastUsedAt.Valid && time.Since(pat.LastUsedAt.Time) < personalAccessTokenTouchInterval {
+		return pat, nil
+	}
+
+	if err := server.store.TouchPersonalAccessToken(ctx, pat.ID); err != nil {
+		return pat, apierror.Internal(err, "cannot update personal access token.")
+	}
+
+	return pat, nil
+}
+
+// scopeMiddleware must run after authMiddleware.
+// Requests made with a personal access token need readScope for GET and writeScope for everything else,
+// a write scope also allows reading. Login sessions are not limited by scopes.
+func (server *Server) scopeMiddleware(readScope string, writeScope string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		value, exists := ctx.Get(tokenScopesKey)
+		if !exists {
+			ctx.Next()
+			return
+		}
+
+		scopes := value.([]string)
+		allowed := slices.Contains(scopes, writeScope)
+		required := writeScope
+		if ctx.Request.Method == http.MethodGet {
+			allowed = allowed || slices.Contains(scopes, readScope)
+			required = readScope
+		}
+
+		if !allowed {
+			respondError(ctx, apierror.New(http.StatusForbidden, fmt.Sprintf("token is missing the %s scope", required)))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// sessionOnlyMiddleware keeps personal access tokens away from account management.
+func (server *Server) sessionOnlyMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if _, exists := ctx.Get(tokenScopesKey); exists {
+			respondError(ctx, apierror.New(http.StatusForbidden, "personal access tokens cannot access this endpoint"))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+func newPersonalAccessTokenResponse(pat db.PersonalAccessToken) apitypes.PersonalAccessTokenResponse {
+	return apitypes.PersonalAccessTokenResponse{
+		ID:         pat.ID,
+		Name:       pat.Name,
+		Prefix:     pat.Prefix,
+		Scopes:     pat.Scopes,
+		ExpiredAt:  timePtr(pat.ExpiredAt),
+		LastUsedAt: timePtr(pat.LastUsedAt),
+		RevokedAt:  timePtr(pat.RevokedAt),
+		CreatedAt:  pat.CreatedAt,
+	}
+}
+
+// CreatePersonalAccessToken issues a long-lived token for scripts.
+// The token itself is only shown in this response, the server keeps its hash.
+func (server *Server) CreatePersonalAccessToken(ctx *gin.Context) {
+	user := ctx.MustGet("user").(db.User)
+
+	var req apitypes.CreatePersonalAccessTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	if slices.Contains(req.Scopes, scopeAdmin) && user.Role != roleAdmin {
+		respondError(ctx, apierror.New(http.StatusForbidden, "only admins can create tokens with the admin scope"))
+		return
+	}
+
+	secret, err := util.RandomToken(32)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot create token"))
+		return
+	}
+	token := personalAccessTokenPrefix + secret
+
+	var expiredAt pgtype.Timestamptz
+	if req.ExpiresInDays > 0 {
+		expiredAt = pgtype.Timestamptz{Time: time.Now().AddDate(0, 0, req.ExpiresInDays), Valid: true}
+	}
+
+	slices.Sort(req.Scopes)
+	pat, err := server.store.CreatePersonalAccessToken(ctx, db.CreatePersonalAccessTokenParams{
+		Username:  user.Username,
+		Name:      strings.TrimSpace(req.Name),
+		TokenHash: hashToken(token),
+		Prefix:    token[:len(personalAccessTokenPrefix)+6],
+		Scopes:    slices.Compact(req.Scopes),
+		ExpiredAt: expiredAt,
+	})
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot create token"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, apitypes.CreatePersonalAccessTokenResponse{
+		Token:   token,
+		Details: newPersonalAccessTokenResponse(pat),
+	})
+}
+
+func (server *Server) ListPersonalAccessTokens(ctx *gin.Context) {
+	user := ctx.MustGet("user").(db.User)
+
+	pats, err := server.store.ListPersonalAccessTokens(ctx, user.Username)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get tokens"))
+		return
+	}
+
+	response := make([]apitypes.PersonalAccessTokenResponse, len(pats))
+	for i, pat := range pats {
+		response[i] = newPersonalAccessTokenResponse(pat)
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (server *Server) RevokePersonalAccessToken(ctx *gin.Context) {
+	user := ctx.MustGet("user").(db.User)
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || id <= 0 {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "invalid token id"))
+		return
+	}
+
+	pat, err := server.store.RevokePersonalAccessToken(ctx, db.RevokePersonalAccessTokenParams{
+		ID:       int64(id),
+		Username: user.Username,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			respondError(ctx, apierror.New(http.StatusNotFound, "token not found"))
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, "cannot revoke token"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPersonalAccessTokenResponse(pat))
+}
@@ -0,0 +1,368 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/sangketkit01/personal-financial/apierror"
+	"github.com/sangketkit01/personal-financial/apitypes"
+	db "github.com/sangketkit01/personal-financial/db/sqlc"
+)
+
+const (
+	roleUser  = "user"
+	roleAdmin = "admin"
+)
+
+// adminAudit describes the admin action of the request, it is recorded in the transaction of the action.
+func adminAudit(ctx *gin.Context, action string, target string) db.AdminAuditInfo {
+	admin := ctx.MustGet("user").(db.User)
+
+	return db.AdminAuditInfo{
+		Admin:    admin.Username,
+		Action:   action,
+		Target:   target,
+		ClientIP: ctx.ClientIP(),
+	}
+}
+
+// containsPattern is an ILIKE pattern matching values that contain term,
+// the wildcards and the escape character in term match themselves.
+func containsPattern(term string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(term)
+	return "%" + escaped + "%"
+}
+
+func newAdminUserResponse(user db.User) apitypes.AdminUserResponse {
+	response := apitypes.AdminUserResponse{
+		ProfileResponse: newProfileResponse(user),
+		Role:            user.Role,
+		Disabled:        user.DisabledAt.Valid,
+	}
+
+	if user.TokensValidAfter.Valid {
+		response.RevokedAt = &user.TokensValidAfter.Time
+	}
+
+	return response
+}
+
+func (server *Server) AdminListFinancialTypes(ctx *gin.Context) {
+	types, err := server.store.ListFinancialTypes(ctx)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get financial types."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convertAll(types, newFinancialType))
+}
+
+func (server *Server) AdminCreateFinancialType(ctx *gin.Context) {
+	var req apitypes.FinancialTypeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	name := strings.TrimSpace(req.Type)
+
+	var financialType db.FinancialType
+	err := server.store.AdminTx(ctx, adminAudit(ctx, "financial_type.create", name), func(q *db.Queries) (any, error) {
+		var err error
+		financialType, err = q.CreateFinancialType(ctx, name)
+		return financialType, err
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			respondError(ctx, apierror.New(http.StatusConflict, "financial type already exists."))
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, "cannot create financial type."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newFinancialType(financialType))
+}
+
+func (server *Server) AdminUpdateFinancialType(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || id <= 0 {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "invalid financial type id."))
+		return
+	}
+
+	var req apitypes.FinancialTypeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	financialType, err := server.store.UpdateFinancialTypeTx(ctx, db.UpdateFinancialTypeParams{
+		Type: strings.TrimSpace(req.Type),
+		ID:   int64(id),
+	}, adminAudit(ctx, "financial_type.update", strconv.Itoa(id)))
+	if err != nil {
+		if errors.Is(err, db.ErrFallbackFinancialType) {
+			respondError(ctx, err)
+			return
+		}
+
+		if err == pgx.ErrNoRows {
+			respondError(ctx, apierror.New(http.StatusNotFound, "financial type not found."))
+			return
+		}
+
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			respondError(ctx, apierror.New(http.StatusConflict, "financial type already exists."))
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, "cannot update financial type."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newFinancialType(financialType))
+}
+
+func (server *Server) AdminDeleteFinancialType(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || id <= 0 {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "invalid financial type id."))
+		return
+	}
+
+	financialType, err := server.store.DeleteFinancialTypeTx(ctx, int64(id), adminAudit(ctx, "financial_type.delete", strconv.Itoa(id)))
+	if err != nil {
+		if errors.Is(err, db.ErrFallbackFinancialType) {
+			respondError(ctx, err)
+			return
+		}
+
+		if err == pgx.ErrNoRows {
+			respondError(ctx, apierror.New(http.StatusNotFound, "financial type not found."))
+			return
+		}
+
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			respondError(ctx, apierror.New(http.StatusConflict, "financial type is still in use."))
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, "cannot delete financial type."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newFinancialType(financialType))
+}
+
+func (server *Server) AdminSearchUsers(ctx *gin.Context) {
+	var req apitypes.SearchUsersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+
+	var users []db.User
+	err := server.store.AdminTx(ctx, adminAudit(ctx, "user.search", req.Query), func(q *db.Queries) (any, error) {
+		var err error
+		users, err = q.SearchUsers(ctx, db.SearchUsersParams{
+			Pattern:    containsPattern(req.Query),
+			PageLimit:  req.Limit,
+			PageOffset: req.Offset,
+		})
+		return req, err
+	})
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot search users."))
+		return
+	}
+
+	response := make([]apitypes.AdminUserResponse, len(users))
+	for i, user := range users {
+		response[i] = newAdminUserResponse(user)
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (server *Server) AdminGetUser(ctx *gin.Context) {
+	username := ctx.Param("username")
+
+	var user db.User
+	err := server.store.AdminTx(ctx, adminAudit(ctx, "user.view", username), func(q *db.Queries) (any, error) {
+		var err error
+		user, err = q.GetUser(ctx, username)
+		return gin.H{}, err
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			respondError(ctx, apierror.New(http.StatusNotFound, "user not found."))
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, "cannot get user."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAdminUserResponse(user))
+}
+
+func (server *Server) AdminSetUserRole(ctx *gin.Context) {
+	admin := ctx.MustGet("user").(db.User)
+
+	var req apitypes.SetUserRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	username := ctx.Param("username")
+	if username == admin.Username {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "you cannot change your own role."))
+		return
+	}
+
+	var user db.User
+	err := server.store.AdminTx(ctx, adminAudit(ctx, "user.set_role", username), func(q *db.Queries) (any, error) {
+		var err error
+		user, err = q.SetUserRole(ctx, db.SetUserRoleParams{
+			Role:     req.Role,
+			Username: username,
+		})
+		return req, err
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			respondError(ctx, apierror.New(http.StatusNotFound, "user not found."))
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, "cannot update role."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAdminUserResponse(user))
+}
+
+func (server *Server) AdminDisableUser(ctx *gin.Context) {
+	server.setUserDisabled(ctx, true)
+}
+
+func (server *Server) AdminEnableUser(ctx *gin.Context) {
+	server.setUserDisabled(ctx, false)
+}
+
+func (server *Server) setUserDisabled(ctx *gin.Context, disabled bool) {
+	admin := ctx.MustGet("user").(db.User)
+
+	username := ctx.Param("username")
+	if username == admin.Username {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "you cannot disable yourself."))
+		return
+	}
+
+	action := "user.enable"
+	if disabled {
+		action = "user.disable"
+	}
+
+	var user db.User
+	err := server.store.AdminTx(ctx, adminAudit(ctx, action, username), func(q *db.Queries) (any, error) {
+		var err error
+		user, err = q.SetUserDisabled(ctx, db.SetUserDisabledParams{
+			Disabled: disabled,
+			Username: username,
+		})
+		return gin.H{}, err
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			respondError(ctx, apierror.New(http.StatusNotFound, "user not found."))
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, "cannot update user."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAdminUserResponse(user))
+}
+
+// AdminRevokeSessions invalidates every token the user got before now.
+func (server *Server) AdminRevokeSessions(ctx *gin.Context) {
+	username := ctx.Param("username")
+
+	var user db.User
+	err := server.store.AdminTx(ctx, adminAudit(ctx, "user.revoke_sessions", username), func(q *db.Queries) (any, error) {
+		var err error
+		user, err = q.RevokeUserTokens(ctx, username)
+		return gin.H{}, err
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			respondError(ctx, apierror.New(http.StatusNotFound, "user not found."))
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, "cannot revoke sessions."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAdminUserResponse(user))
+}
+
+func (server *Server) AdminStats(ctx *gin.Context) {
+	var stats db.GetSystemStatsRow
+	err := server.store.AdminTx(ctx, adminAudit(ctx, "system.stats", "system"), func(q *db.Queries) (any, error) {
+		var err error
+		stats, err = q.GetSystemStats(ctx)
+		return gin.H{}, err
+	})
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get system stats."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newSystemStats(stats))
+}
+
+func (server *Server) AdminListAudits(ctx *gin.Context) {
+	var req apitypes.ListAdminAuditsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	if req.Limit == 0 {
+		req.Limit = 50
+	}
+
+	var audits []db.AdminAudit
+	err := server.store.AdminTx(ctx, adminAudit(ctx, "audit.list", "system"), func(q *db.Queries) (any, error) {
+		var err error
+		audits, err = q.ListAdminAudits(ctx, db.ListAdminAuditsParams{
+			Limit:  req.Limit,
+			Offset: req.Offset,
+		})
+		return req, err
+	})
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get admin audits."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convertAll(audits, newAdminAudit))
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainsPattern(t *testing.T) {
+	testCases := []struct {
+		term    string
+		pattern string
+	}{
+		{"alice", `%alice%`},
+		{"", `%%`},
+		{"100%", `%100\%%`},
+		{"first_name", `%first\_name%`},
+		{`a\b`, `%a\\b%`},
+		{`\%_`, `%\\\%\_%`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.term, func(t *testing.T) {
+			require.Equal(t, tc.pattern, containsPattern(tc.term))
+		})
+	}
+}
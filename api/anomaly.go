@@ -0,0 +1,217 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/sangketkit01/personal-financial/apierror"
+	db "github.com/sangketkit01/personal-financial/db/sqlc"
+	"github.com/sangketkit01/personal-financial/util"
+)
+
+const (
+	anomalyOutlier       = "outlier"
+	anomalyDuplicate     = "duplicate"
+	anomalyNewCategory   = "new_category"
+	anomalyCategorySpike = "category_spike"
+
+	// a record needs this many earlier records of its category before it can be an outlier
+	anomalyMinHistory = 5
+	anomalyZScore     = 3.0
+	// a month is a spike when its category total is this many times the average of the months before
+	anomalySpikeFactor       = 2.0
+	anomalySpikeMonths       = 6
+	anomalySpikeMinMonths    = 3
+	anomalyNewCategoryPctile = 90
+)
+
+// detectAnomalies checks a record against the user's history and stores what it finds.
+// It only returns the anomalies that were not stored before, so it is safe to run more than once.
+// Amounts in the reasons are written in the locale of the user.
+func (server *Server) detectAnomalies(ctx context.Context, financial db.Financial) ([]db.Anomaly, error) {
+	user, err := server.store.GetUser(ctx, financial.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := server.store.ListTypeFinancialsSince(ctx, db.ListTypeFinancialsSinceParams{
+		UserID:    financial.UserID,
+		TypeID:    financial.TypeID,
+		CreatedAt: financial.CreatedAt.AddDate(-1, 0, 0),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var earlier []db.Financial
+	var samples []int64
+	for _, other := range history {
+		if other.ID == financial.ID || other.CreatedAt.After(financial.CreatedAt) {
+			continue
+		}
+
+		earlier = append(earlier, other)
+		if other.Direction == financial.Direction {
+			samples = append(samples, util.Abs(other.Amount))
+		}
+	}
+
+	amount := util.Abs(financial.Amount)
+	var found []db.CreateAnomalyParams
+	flag := func(kind string, reason string) {
+		found = append(found, db.CreateAnomalyParams{
+			UserID:      financial.UserID,
+			FinancialID: pgtype.Int8{Int64: financial.ID, Valid: true},
+			TypeID:      pgtype.Int8{Int64: financial.TypeID, Valid: true},
+			Kind:        kind,
+			Reason:      reason,
+			DedupeKey:   fmt.Sprintf("%s:%d", kind, financial.ID),
+		})
+	}
+
+	for _, other := range earlier {
+		if other.Amount == financial.Amount && financial.CreatedAt.Sub(other.CreatedAt) <= server.config.AnomalyDuplicateWindow {
+			flag(anomalyDuplicate, fmt.Sprintf("same amount %s in the same category as record %d, %s earlier", util.FormatAmount(user.Locale, financial.Amount), other.ID, financial.CreatedAt.Sub(other.CreatedAt).Round(time.Minute)))
+			break
+		}
+	}
+
+	if len(samples) >= anomalyMinHistory {
+		mean, stddev := util.MeanStdDev(samples)
+		if stddev > 0 && (float64(amount)-mean)/stddev > anomalyZScore {
+			flag(anomalyOutlier, fmt.Sprintf("amount %s is far above the usual %s ± %s of this category", util.FormatAmount(user.Locale, amount), util.FormatAmount(user.Locale, int64(math.Round(mean))), util.FormatAmount(user.Locale, int64(math.Round(stddev)))))
+		}
+	}
+
+	if len(earlier) == 0 {
+		amounts, err := server.store.UserAmountsSince(ctx, db.UserAmountsSinceParams{
+			UserID:    financial.UserID,
+			Direction: financial.Direction,
+			CreatedAt: financial.CreatedAt.AddDate(-1, 0, 0),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range amounts {
+			amounts[i] = util.Abs(amounts[i])
+		}
+
+		if len(amounts) > anomalyMinHistory {
+			if large := util.Percentile(amounts, anomalyNewCategoryPctile); amount > large {
+				flag(anomalyNewCategory, fmt.Sprintf("first record of this category in a year and larger than %d%% of your records (%s)", anomalyNewCategoryPctile, util.FormatAmount(user.Locale, large)))
+			}
+		}
+	}
+
+	spike, err := server.categorySpike(ctx, user, financial)
+	if err != nil {
+		return nil, err
+	}
+	if spike != nil {
+		found = append(found, *spike)
+	}
+
+	stored := []db.Anomaly{}
+	for _, arg := range found {
+		anomaly, err := server.store.CreateAnomaly(ctx, arg)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				// already flagged
+				continue
+			}
+			return stored, err
+		}
+
+		stored = append(stored, anomaly)
+	}
+
+	return stored, nil
+}
+
+// categorySpike compares the month total of the record's category with the months before it.
+// Months are the calendar months in the time zone of the user.
+func (server *Server) categorySpike(ctx context.Context, user db.User, financial db.Financial) (*db.CreateAnomalyParams, error) {
+	month := util.MonthStart(financial.CreatedAt.In(userLocation(user)))
+
+	totals, err := server.store.TypeMonthlyTotals(ctx, db.TypeMonthlyTotalsParams{
+		UserID:    financial.UserID,
+		TypeID:    financial.TypeID,
+		CreatedAt: month.AddDate(0, -anomalySpikeMonths, 0),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var current, previous int64
+	var previousMonths int
+	for _, total := range totals {
+		switch {
+		case total.Month.Equal(month):
+			current = util.Abs(total.Total)
+		case total.Month.Before(month):
+			previous += util.Abs(total.Total)
+			previousMonths++
+		}
+	}
+
+	if previousMonths < anomalySpikeMinMonths {
+		return nil, nil
+	}
+
+	// months without records count as zero
+	average := float64(previous) / anomalySpikeMonths
+	if average == 0 || float64(current) <= anomalySpikeFactor*average {
+		return nil, nil
+	}
+
+	return &db.CreateAnomalyParams{
+		UserID:    financial.UserID,
+		TypeID:    pgtype.Int8{Int64: financial.TypeID, Valid: true},
+		Kind:      anomalyCategorySpike,
+		Reason:    fmt.Sprintf("category total %s in %s is %.1f times the monthly average of %s", util.FormatAmount(user.Locale, current), month.Format("2006-01"), float64(current)/average, util.FormatAmount(user.Locale, int64(math.Round(average)))),
+		DedupeKey: fmt.Sprintf("%s:%s:%d:%s", anomalyCategorySpike, financial.UserID, financial.TypeID, month.Format("2006-01")),
+	}, nil
+}
+
+// scanAnomalies is the batch run of detectAnomalies over the records added since the last run.
+func (server *Server) scanAnomalies(ctx context.Context) {
+	financials, err := server.store.ListFinancialsSince(ctx, time.Now().Add(-server.config.AnomalyScanInterval))
+	if err != nil {
+		slog.ErrorContext(ctx, "cannot list financials to scan", "err", err)
+		return
+	}
+
+	var flagged int
+	for _, financial := range financials {
+		anomalies, err := server.detectAnomalies(ctx, financial)
+		if err != nil {
+			slog.ErrorContext(ctx, "cannot check financial for anomalies", "financial_id", financial.ID, "err", err)
+			continue
+		}
+		flagged += len(anomalies)
+	}
+
+	if flagged > 0 {
+		slog.InfoContext(ctx, "flagged anomalies", "count", flagged)
+	}
+}
+
+func (server *Server) ListAnomalies(ctx *gin.Context) {
+	user := ctx.MustGet("user").(db.User)
+
+	anomalies, err := server.store.ListAnomalies(ctx, user.Username)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get anomalies."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convertAll(anomalies, newAnomalyEntry))
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sangketkit01/personal-financial/apierror"
+	"github.com/sangketkit01/personal-financial/apitypes"
+	db "github.com/sangketkit01/personal-financial/db/sqlc"
+)
+
+func auditInfo(ctx *gin.Context, user db.User) db.AuditInfo {
+	return db.AuditInfo{
+		Actor:     user.Username,
+		IP:        ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	}
+}
+
+func newAuditEventResponse(event db.AuditEvent) apitypes.AuditEventResponse {
+	response := apitypes.AuditEventResponse{
+		ID:        event.ID,
+		Actor:     event.Actor,
+		Action:    event.Action,
+		IP:        event.Ip,
+		UserAgent: event.UserAgent,
+		CreatedAt: event.CreatedAt,
+	}
+
+	// keep a missing side as null instead of an empty value
+	if event.Before != nil {
+		response.Before = event.Before
+	}
+	if event.After != nil {
+		response.After = event.After
+	}
+
+	return response
+}
+
+// FinancialHistory lists every recorded change of a financial, oldest first.
+func (server *Server) FinancialHistory(ctx *gin.Context) {
+	financialId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || financialId <= 0 {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "invalid financial id."))
+		return
+	}
+
+	events, err := server.store.ListAuditEvents(ctx, db.ListAuditEventsParams{
+		EntityType: db.AuditEntityFinancial,
+		EntityID:   int64(financialId),
+	})
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get financial history."))
+		return
+	}
+
+	response := make([]apitypes.AuditEventResponse, len(events))
+	for i, event := range events {
+		response[i] = newAuditEventResponse(event)
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
@@ -1,45 +1,42 @@
 package api
 
 import (
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"math"
 	"math/big"
 	"net/http"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/sangketkit01/personal-financial/apierror"
+	"github.com/sangketkit01/personal-financial/apitypes"
 	db "github.com/sangketkit01/personal-financial/db/sqlc"
 )
 
-type BudgetRequest struct {
-	Amount int64 `json:"amount" binding:"required,min=1"`
-}
-
 // AddNewBudget adds budget for the current month - year
 // Can't be add budget for the specified month - year
 func (server *Server) AddNewBudget(ctx *gin.Context) {
 	u, exist := ctx.Get("user")
 	if !exist {
-		ctx.JSON(http.StatusUnauthorized, newErrorResponse("unauthorized."))
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "unauthorized."))
 		return
 	}
 
 	user, ok := u.(db.User)
 	if !ok {
-		ctx.JSON(http.StatusUnauthorized, newErrorResponse("invalid user type."))
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "invalid user type."))
 		return
 	}
 
-	month := time.Now().Month()
-	year := time.Now().Year()
+	year, month := currentPeriod(user)
 
-	var req BudgetRequest
+	var req apitypes.BudgetRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, badRequest(err))
 		return
 	}
 
@@ -49,25 +46,30 @@ func (server *Server) AddNewBudget(ctx *gin.Context) {
 		Exp:   0,
 	}
 
-	budget, err := server.store.AddNewBudget(ctx, db.AddNewBudgetParams{
+	budget, err := server.store.AddBudgetTx(ctx, db.AddNewBudgetParams{
 		UserID: user.Username,
 		Month:  int32(month),
 		Year:   int32(year),
 		Amount: amount,
-	})
+	}, auditInfo(ctx, user))
 
 	if err != nil {
+		if errors.Is(err, db.ErrPeriodClosed) {
+			respondError(ctx, err)
+			return
+		}
+
 		pgErr, ok := err.(*pgconn.PgError)
 		if ok && pgErr.Code == "23505" {
-			ctx.JSON(http.StatusBadRequest, newErrorResponse("you already have budget in the current month."))
+			respondError(ctx, apierror.New(http.StatusBadRequest, "you already have budget in the current month."))
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, budget)
+	ctx.JSON(http.StatusOK, newBudget(budget))
 }
 
 // AddNewUpdateBudgetBudget updates budget for the current month - year
@@ -75,18 +77,17 @@ func (server *Server) AddNewBudget(ctx *gin.Context) {
 func (server *Server) UpdateBudget(ctx *gin.Context) {
 	u, exist := ctx.Get("user")
 	if !exist {
-		ctx.JSON(http.StatusUnauthorized, newErrorResponse("unauthorized."))
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "unauthorized."))
 		return
 	}
 
 	user, ok := u.(db.User)
 	if !ok {
-		ctx.JSON(http.StatusUnauthorized, newErrorResponse("invalid user type."))
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "invalid user type."))
 		return
 	}
 
-	month := time.Now().Month()
-	year := time.Now().Year()
+	year, month := currentPeriod(user)
 
 	// check existence of the budget
 	budget, err := server.store.GetBudget(ctx, db.GetBudgetParams{
@@ -97,17 +98,17 @@ func (server *Server) UpdateBudget(ctx *gin.Context) {
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, newErrorResponse("budget not found."))
+			respondError(ctx, apierror.New(http.StatusNotFound, "budget not found."))
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, err)
 		return
 	}
 
-	var req BudgetRequest
+	var req apitypes.BudgetRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, badRequest(err))
 		return
 	}
 
@@ -117,34 +118,38 @@ func (server *Server) UpdateBudget(ctx *gin.Context) {
 		Exp:   0,
 	}
 
-	updatedBudget, err := server.store.UpdateBudget(ctx, db.UpdateBudgetParams{
+	updatedBudget, err := server.store.UpdateBudgetTx(ctx, db.UpdateBudgetParams{
 		Amount: amount,
 		ID:     budget.ID,
-	})
+	}, auditInfo(ctx, user))
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		if errors.Is(err, db.ErrPeriodClosed) {
+			respondError(ctx, err)
+			return
+		}
+
+		respondError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, updatedBudget)
+	ctx.JSON(http.StatusOK, newBudget(updatedBudget))
 }
 
 func (server *Server) GetCurrentBudget(ctx *gin.Context) {
 	u, exist := ctx.Get("user")
 	if !exist {
-		ctx.JSON(http.StatusUnauthorized, newErrorResponse("unauthorized."))
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "unauthorized."))
 		return
 	}
 
 	user, ok := u.(db.User)
 	if !ok {
-		ctx.JSON(http.StatusUnauthorized, newErrorResponse("invalid user type."))
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "invalid user type."))
 		return
 	}
 
-	month := time.Now().Month()
-	year := time.Now().Year()
+	year, month := currentPeriod(user)
 
 	// check existence of the budget
 	budget, err := server.store.GetBudget(ctx, db.GetBudgetParams{
@@ -155,69 +160,93 @@ func (server *Server) GetCurrentBudget(ctx *gin.Context) {
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, newErrorResponse("budget not found."))
+			respondError(ctx, apierror.New(http.StatusNotFound, "budget not found."))
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, budget)
+	ctx.JSON(http.StatusOK, newBudget(budget))
+}
+
+// GetBudgetOfMonth returns the budget of the month in the path.
+func (server *Server) GetBudgetOfMonth(ctx *gin.Context) {
+	user := ctx.MustGet("user").(db.User)
+
+	var req apitypes.PeriodRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	budget, err := server.store.GetBudget(ctx, db.GetBudgetParams{
+		Month:  req.Month,
+		Year:   req.Year,
+		UserID: user.Username,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			respondError(ctx, apierror.New(http.StatusNotFound, "budget not found."))
+			return
+		}
+
+		respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newBudget(budget))
 }
 
 func (server *Server) GetHistoryBudget(ctx *gin.Context) {
 	u, exist := ctx.Get("user")
 	if !exist {
-		ctx.JSON(http.StatusUnauthorized, newErrorResponse("unauthorized."))
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "unauthorized."))
 		return
 	}
 
 	user, ok := u.(db.User)
 	if !ok {
-		ctx.JSON(http.StatusUnauthorized, newErrorResponse("invalid user type."))
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "invalid user type."))
 		return
 	}
 
 	budgets, err := server.store.GetBudgetHistory(ctx, user.Username)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, newErrorResponse("cannot get budget history."))
+		respondError(ctx, apierror.Internal(err, "cannot get budget history."))
 		return
 	}
 
 	if len(budgets) == 0 {
-		ctx.JSON(http.StatusNotFound, newErrorResponse("budget not found."))
+		respondError(ctx, apierror.New(http.StatusNotFound, "budget not found."))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, budgets)
-}
-
-type BudgetHistoryRequest struct {
-	Year int `json:"year" binding:"required,min=2000"`
+	ctx.JSON(http.StatusOK, convertAll(budgets, newBudget))
 }
 
 func (server *Server) GetBudgetHistoryByYear(ctx *gin.Context) {
 	u, exist := ctx.Get("user")
 	if !exist {
-		ctx.JSON(http.StatusUnauthorized, newErrorResponse("unauthorized."))
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "unauthorized."))
 		return
 	}
 
 	user, ok := u.(db.User)
 	if !ok {
-		ctx.JSON(http.StatusUnauthorized, newErrorResponse("invalid user type."))
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "invalid user type."))
 		return
 	}
 
-	var req BudgetHistoryRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+	var req apitypes.BudgetHistoryRequest
+	if err := bindPeriod(ctx, &req); err != nil {
+		respondError(ctx, badRequest(err))
 		return
 	}
 
-	if req.Year > time.Now().Year() {
-		ctx.JSON(http.StatusBadRequest, newErrorResponse("invalid year."))
+	if year, _ := currentPeriod(user); req.Year > year {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "invalid year."))
 		return
 	}
 
@@ -228,45 +257,41 @@ func (server *Server) GetBudgetHistoryByYear(ctx *gin.Context) {
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, newErrorResponse("budget not found."))
+			respondError(ctx, apierror.New(http.StatusNotFound, "budget not found."))
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, budget)
-}
-
-type CheckBudgetUsageResponse struct {
-	Budget       float64 `json:"budget"`
-	Spent        int     `json:"spent"`
-	UsagePercent string  `json:"usage"`
+	ctx.JSON(http.StatusOK, newBudget(budget))
 }
 
 func (server *Server) CheckBudgetUsage(ctx *gin.Context) {
 	u, exist := ctx.Get("user")
 	if !exist {
-		ctx.JSON(http.StatusUnauthorized, newErrorResponse("unauthorized"))
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "unauthorized"))
 		return
 	}
 
 	user, ok := u.(db.User)
 	if !ok {
-		ctx.JSON(http.StatusUnauthorized, newErrorResponse("invalid user type"))
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "invalid user type"))
 		return
 	}
 
-	response := CheckBudgetUsageResponse{
+	response := apitypes.CheckBudgetUsageResponse{
 		Budget:       0,
 		Spent:        0,
 		UsagePercent: "0%",
 	}
 
+	year, month := currentPeriod(user)
+
 	budget, err := server.store.GetBudget(ctx, db.GetBudgetParams{
-		Month:  int32(time.Now().Month()),
-		Year:   int32(time.Now().Year()),
+		Month:  int32(month),
+		Year:   int32(year),
 		UserID: user.Username,
 	})
 
@@ -275,18 +300,18 @@ func (server *Server) CheckBudgetUsage(ctx *gin.Context) {
 			ctx.JSON(http.StatusOK, response)
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, newErrorResponse("failed to get budget data"))
+		respondError(ctx, apierror.Internal(err, "failed to get budget data"))
 		return
 	}
 
 	summary, err := server.store.SummaryFinancialByMonth(ctx, db.SummaryFinancialByMonthParams{
 		UserID: user.Username,
-		Month:  int32(time.Now().Month()),
-		Year:   int32(time.Now().Year()),
+		Month:  int32(month),
+		Year:   int32(year),
 	})
 
 	if err != nil && err != pgx.ErrNoRows {
-		ctx.JSON(http.StatusInternalServerError, newErrorResponse("failed to get financial summary"))
+		respondError(ctx, apierror.Internal(err, "failed to get financial summary"))
 		return
 	}
 
@@ -294,7 +319,7 @@ func (server *Server) CheckBudgetUsage(ctx *gin.Context) {
 	if err == nil {
 		response.Budget = budgetAmount.Float64
 	} else {
-		log.Printf("cannot parse budget amount: %v", err)
+		slog.WarnContext(ctx, "cannot parse budget amount", "budget_id", budget.ID, "err", err)
 	}
 
 	if err != pgx.ErrNoRows {
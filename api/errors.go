@@ -0,0 +1,162 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/sangketkit01/personal-financial/apierror"
+	db "github.com/sangketkit01/personal-financial/db/sqlc"
+	"github.com/sangketkit01/personal-financial/token"
+)
+
+// domainErrors are the errors of the lower layers with the response they always get.
+var domainErrors = []struct {
+	err    error
+	status int
+	code   apierror.Code
+	detail string
+}{
+	{pgx.ErrNoRows, http.StatusNotFound, apierror.CodeNotFound, "resource not found."},
+	{db.ErrPeriodClosed, http.StatusConflict, apierror.CodePeriodClosed, "this month is closed, reopen it first."},
+	{db.ErrFinancialReconciled, http.StatusConflict, apierror.CodeReconciled, "this financial is reconciled, pass override=true to change it anyway."},
+	{db.ErrReconciliationLocked, http.StatusConflict, apierror.CodeReconcileLocked, "this reconciliation is already finished or canceled."},
+	{db.ErrReconciliationUnbalanced, http.StatusConflict, apierror.CodeReconcileUnbalanced, "the cleared records do not match the statement balance."},
+	{db.ErrFallbackFinancialType, http.StatusConflict, apierror.CodeConflict, "the fallback financial type \"Other\" cannot be renamed or deleted."},
+	{db.ErrTooManyVerifications, http.StatusTooManyRequests, apierror.CodeTooManyRequests, "too many verification codes requested, try again later."},
+	{db.ErrUnknownTimeZone, http.StatusBadRequest, apierror.CodeBadRequest, "unknown time zone."},
+	{db.ErrSettlementNotPending, http.StatusConflict, apierror.CodeSettlementAnswered, "this settlement was already confirmed or rejected."},
+	{token.ErrExpiredToken, http.StatusUnauthorized, apierror.CodeTokenExpired, "token has expired."},
+	{token.ErrInvalidToken, http.StatusUnauthorized, apierror.CodeInvalidToken, "token is invalid."},
+	{errRevokedToken, http.StatusUnauthorized, apierror.CodeInvalidToken, "token has been revoked."},
+	{context.DeadlineExceeded, http.StatusServiceUnavailable, apierror.CodeUnavailable, "the request took too long, try again."},
+}
+
+// toAPIError turns any error into an apierror.Error. Errors it does not know become a 500 that hides the cause.
+func toAPIError(err error) *apierror.Error {
+	var apiErr *apierror.Error
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	for _, known := range domainErrors {
+		if errors.Is(err, known.err) {
+			return &apierror.Error{Status: known.status, Code: known.code, Detail: known.detail, Err: err}
+		}
+	}
+
+	if invalid := invalidRequest(err); invalid != nil {
+		return invalid
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			return &apierror.Error{Status: http.StatusConflict, Code: apierror.CodeConflict, Detail: "it already exists.", Err: err}
+		case "23503":
+			return &apierror.Error{Status: http.StatusConflict, Code: apierror.CodeConflict, Detail: "it refers to something that does not exist or is still in use.", Err: err}
+		}
+	}
+
+	return apierror.Internal(err, "internal server error.")
+}
+
+// invalidRequest reports a request that cannot be bound: per field for failed validation rules,
+// otherwise what is wrong with the body. It is nil for errors that are not about the request.
+func invalidRequest(err error) *apierror.Error {
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		fields := make([]apierror.FieldError, len(validationErrors))
+		for i, fieldErr := range validationErrors {
+			fields[i] = apierror.FieldError{
+				Field:   fieldErr.Field(),
+				Rule:    fieldErr.Tag(),
+				Message: ruleMessage(fieldErr),
+			}
+		}
+
+		return &apierror.Error{
+			Status: http.StatusBadRequest,
+			Code:   apierror.CodeValidation,
+			Detail: "some fields are invalid.",
+			Fields: fields,
+			Err:    err,
+		}
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return &apierror.Error{Status: http.StatusBadRequest, Code: apierror.CodeInvalidBody, Detail: "request body is not valid JSON.", Err: err}
+	}
+
+	if errors.Is(err, io.EOF) {
+		return &apierror.Error{Status: http.StatusBadRequest, Code: apierror.CodeInvalidBody, Detail: "request body is empty.", Err: err}
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return &apierror.Error{
+			Status: http.StatusBadRequest,
+			Code:   apierror.CodeValidation,
+			Detail: "some fields are invalid.",
+			Fields: []apierror.FieldError{{
+				Field:   typeErr.Field,
+				Rule:    "type",
+				Message: fmt.Sprintf("must be a %s", typeErr.Type),
+			}},
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+// badRequest reports a request that cannot be bound, like invalidRequest, and any other error as a plain 400 with its text.
+// Use it for binding errors and the validation errors of this package only, the text is sent to the client.
+// Errors of the store go through respondError or apierror.Internal instead.
+func badRequest(err error) *apierror.Error {
+	if invalid := invalidRequest(err); invalid != nil {
+		return invalid
+	}
+	return &apierror.Error{Status: http.StatusBadRequest, Code: apierror.CodeBadRequest, Detail: err.Error(), Err: err}
+}
+
+func ruleMessage(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "min", "gte":
+		return "must be at least " + fieldErr.Param()
+	case "max", "lte":
+		return "must be at most " + fieldErr.Param()
+	case "gt":
+		return "must be greater than " + fieldErr.Param()
+	case "lt":
+		return "must be less than " + fieldErr.Param()
+	case "len":
+		return "must have length " + fieldErr.Param()
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(fieldErr.Param(), " ", ", ")
+	case "email":
+		return "must be an email address"
+	case "alpha":
+		return "must contain letters only"
+	case "alphanum":
+		return "must contain letters and digits only"
+	case "numeric", "number":
+		return "must be a number"
+	case "e164":
+		return "must be a phone number in E.164 format"
+	case "url":
+		return "must be a URL"
+	}
+	return "must satisfy " + fieldErr.Tag()
+}
@@ -0,0 +1,303 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	db "github.com/sangketkit01/personal-financial/db/sqlc"
+)
+
+// fakeStore is a db.Store for handler tests. It keeps the few tables the tests touch in memory,
+// any other method panics through the nil embedded Store, so a test notices when a handler reaches further.
+type fakeStore struct {
+	db.Store
+
+	mu         sync.Mutex
+	users      map[string]db.User
+	states     map[string]db.OauthState
+	identities map[[2]string]db.UserIdentity
+	pats       map[string]db.PersonalAccessToken
+	budgets    map[[3]int64]db.Budget
+	financials map[int64]db.GetFinancialAccessRow
+	members    map[db.GetHouseholdMemberParams]db.HouseholdMember
+	// createUserErr fails CreateUser
+	createUserErr error
+	patTouches    int
+	// patErr fails the personal access token queries, like a database that went away
+	patErr error
+	// profileUpdates and verifications record the profile changes and codes asked for
+	profileUpdates []db.UpdateProfileTxParams
+	verifications  []db.CreateContactVerificationParams
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		users:      map[string]db.User{},
+		states:     map[string]db.OauthState{},
+		identities: map[[2]string]db.UserIdentity{},
+		pats:       map[string]db.PersonalAccessToken{},
+		budgets:    map[[3]int64]db.Budget{},
+		financials: map[int64]db.GetFinancialAccessRow{},
+		members:    map[db.GetHouseholdMemberParams]db.HouseholdMember{},
+	}
+}
+
+func (store *fakeStore) addUser(user db.User) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.users[user.Username] = user
+}
+
+func (store *fakeStore) GetUser(_ context.Context, username string) (db.User, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	user, ok := store.users[username]
+	if !ok {
+		return db.User{}, pgx.ErrNoRows
+	}
+	return user, nil
+}
+
+func (store *fakeStore) CreateUser(_ context.Context, arg db.CreateUserParams) (db.User, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if store.createUserErr != nil {
+		return db.User{}, store.createUserErr
+	}
+
+	user := db.User{Username: arg.Username, Name: arg.Name, Email: arg.Email, Phone: arg.Phone, Password: arg.Password}
+	store.users[user.Username] = user
+	return user, nil
+}
+
+func (store *fakeStore) GetUserByEmail(_ context.Context, email string) (db.User, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for _, user := range store.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return db.User{}, pgx.ErrNoRows
+}
+
+func (store *fakeStore) CancelUserDeletion(_ context.Context, username string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	user := store.users[username]
+	user.DeletedAt.Valid = false
+	store.users[username] = user
+	return nil
+}
+
+func (store *fakeStore) CreateOAuthState(_ context.Context, arg db.CreateOAuthStateParams) (db.OauthState, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	state := db.OauthState{
+		State:        arg.State,
+		Nonce:        arg.Nonce,
+		CodeVerifier: arg.CodeVerifier,
+		ExpiredAt:    arg.ExpiredAt,
+		CreatedAt:    time.Now(),
+	}
+	store.states[arg.State] = state
+	return state, nil
+}
+
+func (store *fakeStore) ConsumeOAuthState(_ context.Context, key string) (db.OauthState, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	state, ok := store.states[key]
+	if !ok {
+		return db.OauthState{}, pgx.ErrNoRows
+	}
+	delete(store.states, key)
+	return state, nil
+}
+
+// updateState changes a stored state in place, e.g. to expire it.
+func (store *fakeStore) updateState(key string, update func(state *db.OauthState)) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	state := store.states[key]
+	update(&state)
+	store.states[key] = state
+}
+
+func (store *fakeStore) GetUserIdentity(_ context.Context, arg db.GetUserIdentityParams) (db.UserIdentity, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	identity, ok := store.identities[[2]string{arg.Issuer, arg.Subject}]
+	if !ok {
+		return db.UserIdentity{}, pgx.ErrNoRows
+	}
+	return identity, nil
+}
+
+func (store *fakeStore) CreateUserIdentity(_ context.Context, arg db.CreateUserIdentityParams) (db.UserIdentity, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	identity := db.UserIdentity{
+		Issuer:    arg.Issuer,
+		Subject:   arg.Subject,
+		Username:  arg.Username,
+		Email:     arg.Email,
+		CreatedAt: time.Now(),
+	}
+	store.identities[[2]string{arg.Issuer, arg.Subject}] = identity
+	return identity, nil
+}
+
+func (store *fakeStore) addPersonalAccessToken(pat db.PersonalAccessToken) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.pats[pat.TokenHash] = pat
+}
+
+func (store *fakeStore) GetPersonalAccessTokenByHash(_ context.Context, tokenHash string) (db.PersonalAccessToken, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if store.patErr != nil {
+		return db.PersonalAccessToken{}, store.patErr
+	}
+
+	pat, ok := store.pats[tokenHash]
+	if !ok {
+		return db.PersonalAccessToken{}, pgx.ErrNoRows
+	}
+	return pat, nil
+}
+
+func (store *fakeStore) TouchPersonalAccessToken(_ context.Context, id int64) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for hash, pat := range store.pats {
+		if pat.ID == id {
+			pat.LastUsedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+			store.pats[hash] = pat
+		}
+	}
+	store.patTouches++
+	return nil
+}
+
+// addHouseholdBudget keeps a budget for its household, month and year.
+func (store *fakeStore) addHouseholdBudget(budget db.Budget) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.budgets[[3]int64{budget.HouseholdID.Int64, int64(budget.Month), int64(budget.Year)}] = budget
+}
+
+func (store *fakeStore) GetHouseholdBudget(_ context.Context, arg db.GetHouseholdBudgetParams) (db.Budget, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	budget, ok := store.budgets[[3]int64{arg.HouseholdID.Int64, int64(arg.Month), int64(arg.Year)}]
+	if !ok {
+		return db.Budget{}, pgx.ErrNoRows
+	}
+	return budget, nil
+}
+
+// addFinancial keeps who owns the financial record id and the household it is shared with.
+func (store *fakeStore) addFinancial(id int64, access db.GetFinancialAccessRow) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.financials[id] = access
+}
+
+func (store *fakeStore) GetFinancialAccess(_ context.Context, id int64) (db.GetFinancialAccessRow, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	access, ok := store.financials[id]
+	if !ok {
+		return db.GetFinancialAccessRow{}, pgx.ErrNoRows
+	}
+	return access, nil
+}
+
+func (store *fakeStore) addHouseholdMember(member db.HouseholdMember) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.members[db.GetHouseholdMemberParams{HouseholdID: member.HouseholdID, Username: member.Username}] = member
+}
+
+func (store *fakeStore) GetHouseholdMember(_ context.Context, arg db.GetHouseholdMemberParams) (db.HouseholdMember, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	member, ok := store.members[arg]
+	if !ok {
+		return db.HouseholdMember{}, pgx.ErrNoRows
+	}
+	return member, nil
+}
+
+func (store *fakeStore) UpdateProfileTx(_ context.Context, arg db.UpdateProfileTxParams) (db.User, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	user, ok := store.users[arg.Username]
+	if !ok {
+		return db.User{}, pgx.ErrNoRows
+	}
+
+	store.profileUpdates = append(store.profileUpdates, arg)
+	if arg.Name != "" {
+		user.Name = arg.Name
+	}
+	if arg.Preferences != nil {
+		user.TimeZone = arg.Preferences.TimeZone
+		user.Locale = arg.Preferences.Locale
+		user.PeriodStartDay = arg.Preferences.PeriodStartDay
+		user.FiscalYearStartMonth = arg.Preferences.FiscalYearStartMonth
+	}
+
+	store.users[arg.Username] = user
+	return user, nil
+}
+
+// CreateContactVerificationTx counts every code the store created as sent within the window.
+func (store *fakeStore) CreateContactVerificationTx(_ context.Context, arg db.CreateContactVerificationTxParams) (db.ContactVerification, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if int64(len(store.verifications)) >= arg.MaxSends {
+		return db.ContactVerification{}, db.ErrTooManyVerifications
+	}
+
+	store.verifications = append(store.verifications, arg.Verification)
+	return db.ContactVerification{
+		ID:        int64(len(store.verifications)),
+		Username:  arg.Verification.Username,
+		Field:     arg.Verification.Field,
+		NewValue:  arg.Verification.NewValue,
+		Code:      arg.Verification.Code,
+		ExpiredAt: arg.Verification.ExpiredAt,
+	}, nil
+}
+
+func (store *fakeStore) PoolStat() *pgxpool.Stat {
+	return nil
+}
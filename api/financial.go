@@ -1,14 +1,18 @@
 package api
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
 	"math"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/sangketkit01/personal-financial/apierror"
+	"github.com/sangketkit01/personal-financial/apitypes"
 	db "github.com/sangketkit01/personal-financial/db/sqlc"
 	"github.com/sangketkit01/personal-financial/util"
 )
@@ -16,97 +20,120 @@ import (
 func (server *Server) GetFinancialById(ctx *gin.Context) {
 	u, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, newErrorResponse("unauthorized"))
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "unauthorized"))
 		return
 	}
 	_, ok := u.(db.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, newErrorResponse("invalid user type"))
+		respondError(ctx, apierror.Internal(nil, "invalid user type"))
 		return
 	}
 
 	financialId, err := strconv.Atoi(ctx.Param("id"))
 	if err != nil || financialId <= 0 {
-		ctx.JSON(http.StatusBadRequest, newErrorResponse("invalid financial id."))
+		respondError(ctx, apierror.New(http.StatusBadRequest, "invalid financial id."))
 		return
 	}
 
 	financialData, err := server.store.GetFinancialById(ctx, int64(financialId))
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, newErrorResponse("no financial found."))
+			respondError(ctx, apierror.New(http.StatusNotFound, "no financial found."))
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, newErrorResponse("cannot get financial data."))
+		respondError(ctx, apierror.Internal(err, "cannot get financial data."))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, financialData)
+	ctx.JSON(http.StatusOK, newFinancialEntry(db.MyFinancialRow(financialData)))
 }
 
 func (server *Server) MyFinancial(ctx *gin.Context) {
 	u, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, newErrorResponse("unauthorized"))
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "unauthorized"))
 		return
 	}
 	user, ok := u.(db.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, newErrorResponse("invalid user type"))
+		respondError(ctx, apierror.Internal(nil, "invalid user type"))
 		return
 	}
 
 	myFinancial, err := server.store.MyFinancial(ctx, user.Username)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, newErrorResponse("cannot get financial data."))
+		respondError(ctx, apierror.Internal(err, "cannot get financial data."))
 		return
 	} else if len(myFinancial) == 0 {
-		ctx.JSON(http.StatusNotFound, newErrorResponse("no financial found."))
+		respondError(ctx, apierror.New(http.StatusNotFound, "no financial found."))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, myFinancial)
+	ctx.JSON(http.StatusOK, convertAll(myFinancial, newFinancialEntry))
 }
 
-type NewFinancialRequest struct {
-	Amount int64  `json:"amount" binding:"required"`
-	Type   string `json:"type" binding:"required,alpha"`
+// financialType looks up a financial type by name, unknown names get the fallback type.
+func (server *Server) financialType(ctx *gin.Context, name string) (db.FinancialType, error) {
+	financialType, err := server.store.GetFinancialByName(ctx, name)
+	if err == pgx.ErrNoRows {
+		return server.store.GetFinancialByName(ctx, db.FallbackFinancialType)
+	}
+
+	return financialType, err
 }
 
 func (server *Server) AddNewFinancial(ctx *gin.Context) {
 	u, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, newErrorResponse("unauthorized"))
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "unauthorized"))
 		return
 	}
 	user, ok := u.(db.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, newErrorResponse("invalid user type"))
+		respondError(ctx, apierror.Internal(nil, "invalid user type"))
 		return
 	}
 
-	var req NewFinancialRequest
+	var req apitypes.NewFinancialRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, newErrorResponse("invalid request."))
+		respondError(ctx, badRequest(err))
 		return
 	}
 
 	if req.Amount == 0 {
-		ctx.JSON(http.StatusBadRequest, newErrorResponse("amount cannot be zero"))
+		respondError(ctx, apierror.New(http.StatusBadRequest, "amount cannot be zero"))
 		return
 	}
 
-	financialTypeId, err := server.store.GetFinancialByName(ctx, util.CapitalizeWord(req.Type))
-	if err != nil {
-		if err == pgx.ErrNoRows {
-			// other financial type
-			financialTypeId.ID = 10
-		} else {
-			fmt.Printf("error: %v\n", err)
-			ctx.JSON(http.StatusInternalServerError, newErrorResponse("cannot get financial type."))
+	var householdId pgtype.Int8
+	if req.HouseholdID != 0 {
+		member, err := server.store.GetHouseholdMember(ctx, db.GetHouseholdMemberParams{
+			HouseholdID: req.HouseholdID,
+			Username:    user.Username,
+		})
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				respondError(ctx, apierror.New(http.StatusForbidden, "you are not a member of this household."))
+				return
+			}
+
+			respondError(ctx, apierror.Internal(err, "cannot get household member."))
+			return
+		}
+
+		if !hasHouseholdRole(member.Role, householdRoleEditor) {
+			respondError(ctx, apierror.New(http.StatusForbidden, "viewers cannot add household financials."))
 			return
 		}
+
+		householdId = pgtype.Int8{Int64: req.HouseholdID, Valid: true}
+	}
+
+	financialTypeId, err := server.financialType(ctx, util.CapitalizeWord(req.Type))
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get financial type."))
+		return
 	}
 
 	direction := "in"
@@ -115,113 +142,105 @@ func (server *Server) AddNewFinancial(ctx *gin.Context) {
 	}
 
 	arg := db.InsertNewFinancialParams{
-		UserID:    user.Username,
-		Amount:    req.Amount,
-		Direction: direction,
-		TypeID:    financialTypeId.ID,
+		UserID:      user.Username,
+		Amount:      req.Amount,
+		Direction:   direction,
+		TypeID:      financialTypeId.ID,
+		HouseholdID: householdId,
 	}
 
-	financial, err := server.store.InsertNewFinancial(ctx, arg)
+	year, month := currentPeriod(user)
+
+	result, err := server.store.InsertFinancialWithUsageTx(ctx, db.InsertFinancialWithUsageTxParams{
+		Financial: arg,
+		Month:     int32(month),
+		Year:      int32(year),
+		Audit:     auditInfo(ctx, user),
+	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, newErrorResponse("failed to save your financial."))
+		if errors.Is(err, db.ErrPeriodClosed) {
+			respondError(ctx, err)
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, "failed to save your financial."))
 		return
 	}
+	financial := result.Financial
+	server.metrics.financialsCreated.Inc()
+
+	anomalies, err := server.detectAnomalies(ctx, financial)
+	if err != nil {
+		slog.ErrorContext(ctx, "cannot check financial for anomalies", "financial_id", financial.ID, "err", err)
+	}
+	for _, anomaly := range anomalies {
+		server.metrics.anomaliesFound.Inc(anomaly.Kind)
+	}
 
 	// after insert the new financial, check how much we've spent compared to our budget
 	usageMessage := "you have no budget now. Please visit /insert-budget to add your budget"
 
-	month := time.Now().Month()
-	year := time.Now().Year()
-
-	budget, err := server.store.GetBudget(ctx, db.GetBudgetParams{
-		UserID: user.Username,
-		Month:  int32(month),
-		Year:   int32(year),
-	})
-	if err != nil {
-		if err == pgx.ErrNoRows {
-			fmt.Printf("user: %s have no budget\n", user.Username)
-		} else {
-			fmt.Printf("cannot get budget of the current user: %v", err)
-		}
+	if !result.HasBudget {
+		slog.DebugContext(ctx, "user has no budget", "username", user.Username)
+	} else if result.Budget.Amount.Int == nil || result.Budget.Amount.Int.Int64() == 0 {
+		usageMessage = "Your budget is set to 0. Please update it to track usage."
 	} else {
-		var usage float32 = 1.0
+		budgetFloat, _ := result.Budget.Amount.Float64Value()
+		budgetAmount := float32(budgetFloat.Float64)
+		usage := float32(math.Abs(float64(result.Summary.TotalExpense))) / budgetAmount
 
-		currenMonthUsage, err := server.store.SummaryFinancialByMonth(ctx, db.SummaryFinancialByMonthParams{
-			UserID: user.Username,
-			Month:  int32(time.Now().Month()),
-			Year:   int32(time.Now().Year()),
-		})
+		usagePercent := fmt.Sprintf("%.2f", usage*100.0)
+		usageMessage = fmt.Sprintf("You've used %s%% of the budget you've set", usagePercent)
 
-		if err != nil {
-			if err == pgx.ErrNoRows {
-				usage = 0.0
-			}
-		}
-
-		if budget.Amount.Int.Int64() == 0 {
-			usageMessage = "Your budget is set to 0. Please update it to track usage."
-		} else {
-			budgetFloat, _ := budget.Amount.Float64Value()
-			budgetAmount := float32(budgetFloat.Float64)
-			usage = float32(math.Abs(float64(currenMonthUsage.TotalExpense))) / budgetAmount
-
-			usagePercent := fmt.Sprintf("%.2f", usage*100.0)
-			usageMessage = fmt.Sprintf("You've used %s%% of the budget you've set", usagePercent)
+		// alert once, when this record takes the month over the budget
+		spent := math.Abs(float64(result.Summary.TotalExpense))
+		if financial.Direction == "out" && spent >= float64(budgetAmount) && spent-math.Abs(float64(financial.Amount)) < float64(budgetAmount) {
+			server.metrics.budgetAlerts.Inc()
 		}
 	}
 	// -----------------------------------------------------------------
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"message":   "saved financial successfully.",
-		"financial": financial,
-		"usage":     usageMessage,
+	ctx.JSON(http.StatusOK, apitypes.NewFinancialResponse{
+		Message:   "saved financial successfully.",
+		Financial: newFinancial(financial),
+		Usage:     usageMessage,
+		Anomalies: convertAll(anomalies, newAnomaly),
 	})
 }
 
-type UpdateFinancialRequest struct {
-	Amount int64  `json:"amount" binding:"required"`
-	Type   string `json:"type" binding:"required,alpha"`
-}
-
 func (server *Server) UpdateFinancial(ctx *gin.Context) {
 	u, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, newErrorResponse("unauthorized"))
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "unauthorized"))
 		return
 	}
-	_, ok := u.(db.User)
+	user, ok := u.(db.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, newErrorResponse("invalid user type"))
+		respondError(ctx, apierror.Internal(nil, "invalid user type"))
 		return
 	}
 
 	financialId, err := strconv.Atoi(ctx.Param("id"))
 	if err != nil || financialId <= 0 {
-		ctx.JSON(http.StatusBadRequest, newErrorResponse("invalid financial id."))
+		respondError(ctx, apierror.New(http.StatusBadRequest, "invalid financial id."))
 		return
 	}
 
-	var req UpdateFinancialRequest
+	var req apitypes.UpdateFinancialRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, newErrorResponse("invalid request."))
+		respondError(ctx, badRequest(err))
 		return
 	}
 
 	if req.Amount == 0 {
-		ctx.JSON(http.StatusBadRequest, newErrorResponse("amount cannot be zero"))
+		respondError(ctx, apierror.New(http.StatusBadRequest, "amount cannot be zero"))
 		return
 	}
 
-	financialTypeId, err := server.store.GetFinancialByName(ctx, util.CapitalizeWord(req.Type))
+	financialTypeId, err := server.financialType(ctx, util.CapitalizeWord(req.Type))
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			// other financial type
-			financialTypeId.ID = 10
-		} else {
-			ctx.JSON(http.StatusInternalServerError, newErrorResponse("cannot get financial type."))
-			return
-		}
+		respondError(ctx, apierror.Internal(err, "cannot get financial type."))
+		return
 	}
 
 	direction := "in"
@@ -236,139 +255,163 @@ func (server *Server) UpdateFinancial(ctx *gin.Context) {
 		ID:        int64(financialId),
 	}
 
-	updatedFinancial, err := server.store.UpdateFinancial(ctx, arg)
+	updatedFinancial, err := server.store.UpdateFinancialTx(ctx, db.UpdateFinancialTxParams{
+		Financial:       arg,
+		AllowReconciled: ctx.Query("override") == "true",
+		Audit:           auditInfo(ctx, user),
+	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, "cannot update financial.")
+		if errors.Is(err, db.ErrPeriodClosed) || errors.Is(err, db.ErrFinancialReconciled) {
+			respondError(ctx, err)
+			return
+		}
+
+		if err == pgx.ErrNoRows {
+			respondError(ctx, apierror.New(http.StatusNotFound, "financial not found."))
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, "cannot update financial."))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"message":           "update financial successfully.",
-		"updated_financial": updatedFinancial,
+	ctx.JSON(http.StatusOK, apitypes.UpdateFinancialResponse{
+		Message:          "update financial successfully.",
+		UpdatedFinancial: newFinancial(updatedFinancial),
 	})
 }
 
 func (server *Server) DeleteFinancial(ctx *gin.Context) {
 	u, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, newErrorResponse("unauthorized"))
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "unauthorized"))
 		return
 	}
-	_, ok := u.(db.User)
+	user, ok := u.(db.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, newErrorResponse("invalid user type"))
+		respondError(ctx, apierror.Internal(nil, "invalid user type"))
 		return
 	}
 
 	financialId, err := strconv.Atoi(ctx.Param("id"))
 	if err != nil || financialId <= 0 {
-		ctx.JSON(http.StatusBadRequest, newErrorResponse("invalid financial id."))
+		respondError(ctx, apierror.New(http.StatusBadRequest, "invalid financial id."))
 		return
 	}
 
-	deleteFinancial, err := server.store.DeleteFinancial(ctx, int64(financialId))
+	deleteFinancial, err := server.store.DeleteFinancialTx(ctx, db.DeleteFinancialTxParams{
+		ID:    int64(financialId),
+		Audit: auditInfo(ctx, user),
+	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, "failed to delete financial")
+		if errors.Is(err, db.ErrFinancialReconciled) {
+			respondError(ctx, apierror.New(http.StatusConflict, "a reconciled financial cannot be deleted.").WithCode(apierror.CodeReconciled))
+			return
+		}
+
+		if errors.Is(err, db.ErrPeriodClosed) {
+			respondError(ctx, err)
+			return
+		}
+
+		if err == pgx.ErrNoRows {
+			respondError(ctx, apierror.New(http.StatusNotFound, "financial not found."))
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, "failed to delete financial"))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"message":           "delete financial successfully.",
-		"deleted_financial": deleteFinancial,
+	ctx.JSON(http.StatusOK, apitypes.DeleteFinancialResponse{
+		Message:          "moved financial to the trash.",
+		DeletedFinancial: newFinancial(deleteFinancial),
 	})
 }
 
 func (server *Server) SummaryCurrentMonth(ctx *gin.Context) {
 	u, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, newErrorResponse("unauthorized"))
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "unauthorized"))
 		return
 	}
 	user, ok := u.(db.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, newErrorResponse("invalid user type"))
+		respondError(ctx, apierror.Internal(nil, "invalid user type"))
 		return
 	}
 
+	year, month := currentPeriod(user)
 	arg := db.SummaryFinancialByMonthParams{
 		UserID: user.Username,
-		Month:  int32(time.Now().Month()),
-		Year:   int32(time.Now().Year()),
+		Month:  int32(month),
+		Year:   int32(year),
 	}
 
 	summary, err := server.store.SummaryFinancialByMonth(ctx, arg)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, newErrorResponse("you have no financial yet."))
+			respondError(ctx, apierror.New(http.StatusNotFound, "you have no financial yet."))
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, newErrorResponse(err.Error()))
+		respondError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, summary)
+	ctx.JSON(http.StatusOK, newFinancialSummary(summary))
 }
 
 func (server *Server) SummaryCurrentYear(ctx *gin.Context) {
 	u, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, newErrorResponse("unauthorized"))
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "unauthorized"))
 		return
 	}
 	user, ok := u.(db.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, newErrorResponse("invalid user type"))
+		respondError(ctx, apierror.Internal(nil, "invalid user type"))
 		return
 	}
 
 	arg := db.SummaryFinancialByYearParams{
 		UserID: user.Username,
-		Year:   int32(time.Now().Year()),
+		Year:   int32(currentFiscalYear(user)),
 	}
 
 	summary, err := server.store.SummaryFinancialByYear(ctx, arg)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, newErrorResponse("you have no financial yet."))
+			respondError(ctx, apierror.New(http.StatusNotFound, "you have no financial yet."))
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, newErrorResponse(err.Error()))
+		respondError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, summary)
-}
-
-type YearMonthRequest struct {
-	Year  int `json:"year" binding:"required,min=2020"`
-	Month int `json:"month" binding:"required,min=1,max=12"`
-}
-
-type YearRequest struct {
-	Year int `json:"year" binding:"required,min=2020"`
+	ctx.JSON(http.StatusOK, newFinancialSummary(db.SummaryFinancialByMonthRow(summary)))
 }
 
 func (server *Server) SummaryByMonthYear(ctx *gin.Context) {
 	u, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, newErrorResponse("unauthorized"))
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "unauthorized"))
 		return
 	}
 	user, ok := u.(db.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, newErrorResponse("invalid user type"))
+		respondError(ctx, apierror.Internal(nil, "invalid user type"))
 		return
 	}
-	var req YearMonthRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, newErrorResponse("invalid request body."))
+	var req apitypes.YearMonthRequest
+	if err := bindPeriod(ctx, &req); err != nil {
+		respondError(ctx, badRequest(err))
 		return
 	}
 
-	if req.Year > time.Now().Year() {
-		ctx.JSON(http.StatusBadRequest, newErrorResponse("invalid year."))
+	if year, _ := currentPeriod(user); req.Year > year {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "invalid year."))
 		return
 	}
 
@@ -380,37 +423,37 @@ func (server *Server) SummaryByMonthYear(ctx *gin.Context) {
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, newErrorResponse("you have no financial yet."))
+			respondError(ctx, apierror.New(http.StatusNotFound, "you have no financial yet."))
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, newErrorResponse(err.Error()))
+		respondError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, summary)
+	ctx.JSON(http.StatusOK, newFinancialSummary(summary))
 }
 
 func (server *Server) SummaryByYear(ctx *gin.Context) {
 	u, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, newErrorResponse("unauthorized"))
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "unauthorized"))
 		return
 	}
 	user, ok := u.(db.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, newErrorResponse("invalid user type"))
+		respondError(ctx, apierror.Internal(nil, "invalid user type"))
 		return
 	}
 
-	var req YearRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, newErrorResponse("invalid request body."))
+	var req apitypes.YearRequest
+	if err := bindPeriod(ctx, &req); err != nil {
+		respondError(ctx, badRequest(err))
 		return
 	}
 
-	if req.Year > time.Now().Year() {
-		ctx.JSON(http.StatusBadRequest, newErrorResponse("invalid year."))
+	if req.Year > currentFiscalYear(user) {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "invalid year."))
 		return
 	}
 
@@ -421,62 +464,64 @@ func (server *Server) SummaryByYear(ctx *gin.Context) {
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, newErrorResponse("you have no financial yet."))
+			respondError(ctx, apierror.New(http.StatusNotFound, "you have no financial yet."))
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, newErrorResponse(err.Error()))
+		respondError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, summary)
+	ctx.JSON(http.StatusOK, newFinancialSummary(db.SummaryFinancialByMonthRow(summary)))
 }
 
 func (server *Server) SummaryEachYear(ctx *gin.Context) {
 	u, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, newErrorResponse("unauthorized"))
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "unauthorized"))
 		return
 	}
 	user, ok := u.(db.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, newErrorResponse("invalid user type"))
+		respondError(ctx, apierror.Internal(nil, "invalid user type"))
 		return
 	}
 
 	summary, err := server.store.SummaryFinancialEachYear(ctx, user.Username)
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, newErrorResponse(err.Error()))
+		respondError(ctx, err)
 		return
 	} else if len(summary) == 0 {
-		ctx.JSON(http.StatusNotFound, newErrorResponse("no financial found."))
+		respondError(ctx, apierror.New(http.StatusNotFound, "no financial found."))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, summary)
+	ctx.JSON(http.StatusOK, convertAll(summary, newYearSummary))
 }
 
 func (server *Server) SummaryTypeByMonthYear(ctx *gin.Context) {
 	u, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, newErrorResponse("unauthorized"))
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "unauthorized"))
 		return
 	}
 	user, ok := u.(db.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, newErrorResponse("invalid user type"))
+		respondError(ctx, apierror.Internal(nil, "invalid user type"))
 		return
 	}
 
-	var req YearMonthRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		req.Month = int(time.Now().Month())
-		req.Year = time.Now().Year()
+	var req apitypes.YearMonthRequest
+	if err := bindPeriod(ctx, &req); err != nil {
+		year, month := currentPeriod(user)
+		req.Month = int(month)
+		req.Year = year
 	}
 
-	if req.Year > time.Now().Year() {
-		ctx.JSON(http.StatusBadRequest, newErrorResponse("invalid year."))
+	if year, _ := currentPeriod(user); req.Year > year {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "invalid year."))
+		return
 	}
 
 	summary, err := server.store.SummaryByTypeMonth(ctx, db.SummaryByTypeMonthParams{
@@ -486,35 +531,36 @@ func (server *Server) SummaryTypeByMonthYear(ctx *gin.Context) {
 	})
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, newErrorResponse(err.Error()))
+		respondError(ctx, err)
 		return
 	} else if len(summary) == 0 {
-		ctx.JSON(http.StatusNotFound, newErrorResponse("you have no financial yet."))
+		respondError(ctx, apierror.New(http.StatusNotFound, "you have no financial yet."))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, summary)
+	ctx.JSON(http.StatusOK, convertAll(summary, newTypeSummary))
 }
 
 func (server *Server) SummaryTypeByYear(ctx *gin.Context) {
 	u, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, newErrorResponse("unauthorized"))
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "unauthorized"))
 		return
 	}
 	user, ok := u.(db.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, newErrorResponse("invalid user type"))
+		respondError(ctx, apierror.Internal(nil, "invalid user type"))
 		return
 	}
 
-	var req YearRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		req.Year = time.Now().Year()
+	var req apitypes.YearRequest
+	if err := bindPeriod(ctx, &req); err != nil {
+		req.Year = currentFiscalYear(user)
 	}
 
-	if req.Year > time.Now().Year() {
-		ctx.JSON(http.StatusBadRequest, newErrorResponse("invalid year."))
+	if req.Year > currentFiscalYear(user) {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "invalid year."))
+		return
 	}
 
 	summary, err := server.store.SummaryByTypeYear(ctx, db.SummaryByTypeYearParams{
@@ -523,12 +569,14 @@ func (server *Server) SummaryTypeByYear(ctx *gin.Context) {
 	})
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, newErrorResponse(err.Error()))
+		respondError(ctx, err)
 		return
 	} else if len(summary) == 0 {
-		ctx.JSON(http.StatusNotFound, newErrorResponse("you have no financial yet."))
+		respondError(ctx, apierror.New(http.StatusNotFound, "you have no financial yet."))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, summary)
+	ctx.JSON(http.StatusOK, convertAll(summary, func(row db.SummaryByTypeYearRow) apitypes.TypeSummary {
+		return newTypeSummary(db.SummaryByTypeMonthRow(row))
+	}))
 }
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sangketkit01/personal-financial/apierror"
+	db "github.com/sangketkit01/personal-financial/db/sqlc"
+	"github.com/sangketkit01/personal-financial/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFinancialValidationErrors(t *testing.T) {
+	server := newTestServer(t, newFakeStore(), util.Config{})
+	user := db.User{Username: "alice"}
+
+	testCases := []struct {
+		name    string
+		handler gin.HandlerFunc
+		method  string
+		target  string
+	}{
+		{"add", server.AddNewFinancial, http.MethodPost, "/transactions"},
+		{"update", func(ctx *gin.Context) {
+			ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+			server.UpdateFinancial(ctx)
+		}, http.MethodPut, "/transactions/1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveJSON(tc.handler, &user, tc.method, tc.target, `{"type":"food 1"}`)
+			require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+			var problem apierror.Problem
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+			require.Equal(t, apierror.CodeValidation, problem.Code)
+			require.NotEmpty(t, problem.Errors)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sangketkit01/personal-financial/apierror"
+	"github.com/sangketkit01/personal-financial/apitypes"
+	db "github.com/sangketkit01/personal-financial/db/sqlc"
+	"github.com/sangketkit01/personal-financial/util"
+)
+
+// Forecast projects the balance for the coming months from a per category baseline,
+// the average monthly income and expense of each category over the last full months.
+// The ledger has no recurring items or loan schedules yet, so the baseline is the only input.
+func (server *Server) Forecast(ctx *gin.Context) {
+	user := ctx.MustGet("user").(db.User)
+
+	req := apitypes.ForecastRequest{Months: 6, History: 12, Granularity: "monthly"}
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	balance, err := server.store.LedgerBalance(ctx, user.Username)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get balance."))
+		return
+	}
+
+	firstAt, err := server.store.FirstFinancialAt(ctx, user.Username)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get financial history."))
+		return
+	}
+
+	// only full months count, a short history averages over the months it has
+	historyEnd := util.MonthStart(userNow(user))
+	historyStart := historyEnd.AddDate(0, -req.History, 0)
+	historyMonths := req.History
+	if first := util.MonthStart(firstAt.In(userLocation(user))); first.After(historyStart) {
+		historyStart = first
+		historyMonths = (historyEnd.Year()-first.Year())*12 + int(historyEnd.Month()-first.Month())
+	}
+
+	categories := []apitypes.CategoryBaseline{}
+	var income, expense int64
+	if historyMonths > 0 {
+		rows, err := server.store.SummaryByTypeBetween(ctx, db.SummaryByTypeBetweenParams{
+			UserID:   user.Username,
+			FromTime: historyStart,
+			ToTime:   historyEnd,
+		})
+		if err != nil {
+			respondError(ctx, apierror.Internal(err, "cannot get financial history."))
+			return
+		}
+
+		for _, row := range rows {
+			category := apitypes.CategoryBaseline{
+				Type:    row.Type,
+				Income:  row.TotalIncome / int64(historyMonths),
+				Expense: row.TotalExpense / int64(historyMonths),
+			}
+			income += category.Income
+			expense += category.Expense
+			categories = append(categories, category)
+		}
+	}
+
+	points := util.Forecast(balance, historyEnd.AddDate(0, 1, 0), req.Months, income, expense, req.Granularity == "daily")
+
+	negative := []time.Time{}
+	for _, point := range points {
+		if point.Negative {
+			negative = append(negative, point.Start)
+		}
+	}
+
+	ctx.JSON(http.StatusOK, apitypes.ForecastResponse{
+		OpeningBalance:  balance,
+		Granularity:     req.Granularity,
+		HistoryMonths:   historyMonths,
+		Baseline:        categories,
+		Points:          convertAll(points, newForecastPoint),
+		NegativePeriods: negative,
+	})
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sangketkit01/personal-financial/apitypes"
+	db "github.com/sangketkit01/personal-financial/db/sqlc"
+)
+
+const readinessTimeout = 2 * time.Second
+
+// Healthz tells that the process is up, it does not touch the database.
+func (server *Server) Healthz(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz tells whether the server can take traffic: the database answers and is migrated at least to the schema the code expects.
+func (server *Server) Readyz(ctx *gin.Context) {
+	response := apitypes.ReadyResponse{
+		Status:          "ready",
+		Database:        "ok",
+		ExpectedVersion: db.SchemaVersion,
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+
+	if err := server.store.Ping(checkCtx); err != nil {
+		slog.WarnContext(ctx, "readiness: cannot ping the database", "err", err)
+		response.Status = "not ready"
+		response.Database = "unreachable"
+		ctx.JSON(http.StatusServiceUnavailable, response)
+		return
+	}
+
+	version, dirty, err := server.store.MigrationVersion(checkCtx)
+	if err != nil {
+		slog.WarnContext(ctx, "readiness: cannot read the migration version", "err", err)
+		response.Status = "not ready"
+		response.Database = "not migrated"
+		ctx.JSON(http.StatusServiceUnavailable, response)
+		return
+	}
+
+	response.MigrationVersion = version
+	response.Dirty = dirty
+	if dirty || version < db.SchemaVersion {
+		response.Status = "not ready"
+		ctx.JSON(http.StatusServiceUnavailable, response)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
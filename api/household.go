@@ -0,0 +1,489 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/sangketkit01/personal-financial/apierror"
+	"github.com/sangketkit01/personal-financial/apitypes"
+	db "github.com/sangketkit01/personal-financial/db/sqlc"
+	"github.com/sangketkit01/personal-financial/util"
+)
+
+const (
+	householdRoleOwner  = "owner"
+	householdRoleEditor = "editor"
+	householdRoleViewer = "viewer"
+
+	householdMemberKey = "household_member"
+)
+
+var householdRoleRank = map[string]int{
+	householdRoleViewer: 1,
+	householdRoleEditor: 2,
+	householdRoleOwner:  3,
+}
+
+// hasHouseholdRole reports whether role is at least as strong as minRole.
+func hasHouseholdRole(role string, minRole string) bool {
+	return householdRoleRank[role] >= householdRoleRank[minRole]
+}
+
+// HouseholdMiddleware only lets members of the household in the :household_id param through.
+func (server *Server) HouseholdMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user := ctx.MustGet("user").(db.User)
+
+		householdId, err := strconv.Atoi(ctx.Param("household_id"))
+		if err != nil || householdId <= 0 {
+			respondError(ctx, apierror.New(http.StatusBadRequest, "invalid household id."))
+			return
+		}
+
+		member, err := server.store.GetHouseholdMember(ctx, db.GetHouseholdMemberParams{
+			HouseholdID: int64(householdId),
+			Username:    user.Username,
+		})
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				respondError(ctx, apierror.New(http.StatusForbidden, "you are not a member of this household."))
+				return
+			}
+
+			respondError(ctx, apierror.Internal(err, "cannot get household member."))
+			return
+		}
+
+		ctx.Set(householdMemberKey, member)
+		ctx.Next()
+	}
+}
+
+// requireHouseholdRole must run after HouseholdMiddleware.
+func (server *Server) requireHouseholdRole(minRole string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		member := ctx.MustGet(householdMemberKey).(db.HouseholdMember)
+
+		if !hasHouseholdRole(member.Role, minRole) {
+			respondError(ctx, apierror.New(http.StatusForbidden, fmt.Sprintf("this action needs the %s role.", minRole)))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+func (server *Server) CreateHousehold(ctx *gin.Context) {
+	u, exists := ctx.Get("user")
+	if !exists {
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "unauthorized"))
+		return
+	}
+	user, ok := u.(db.User)
+	if !ok {
+		respondError(ctx, apierror.Internal(nil, "invalid user type"))
+		return
+	}
+
+	var req apitypes.CreateHouseholdRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	household, err := server.store.CreateHouseholdTx(ctx, db.CreateHouseholdParams{
+		Name:  strings.TrimSpace(req.Name),
+		Owner: user.Username,
+	})
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot create household."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newHousehold(household))
+}
+
+func (server *Server) MyHouseholds(ctx *gin.Context) {
+	u, exists := ctx.Get("user")
+	if !exists {
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "unauthorized"))
+		return
+	}
+	user, ok := u.(db.User)
+	if !ok {
+		respondError(ctx, apierror.Internal(nil, "invalid user type"))
+		return
+	}
+
+	households, err := server.store.ListMyHouseholds(ctx, user.Username)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get households."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convertAll(households, newMyHousehold))
+}
+
+func (server *Server) GetHousehold(ctx *gin.Context) {
+	member := ctx.MustGet(householdMemberKey).(db.HouseholdMember)
+
+	household, err := server.store.GetHousehold(ctx, member.HouseholdID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			respondError(ctx, apierror.New(http.StatusNotFound, "household not found."))
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, "cannot get household."))
+		return
+	}
+
+	members, err := server.store.ListHouseholdMembers(ctx, member.HouseholdID)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get household members."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, apitypes.HouseholdResponse{
+		Household: newHousehold(household),
+		Members:   convertAll(members, newHouseholdMemberEntry),
+	})
+}
+
+func (server *Server) InviteHouseholdMember(ctx *gin.Context) {
+	user := ctx.MustGet("user").(db.User)
+	member := ctx.MustGet(householdMemberKey).(db.HouseholdMember)
+
+	var req apitypes.InviteHouseholdMemberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	household, err := server.store.GetHousehold(ctx, member.HouseholdID)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get household."))
+		return
+	}
+
+	token, err := util.RandomToken(32)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot create invitation."))
+		return
+	}
+
+	invitation, err := server.store.CreateHouseholdInvitation(ctx, db.CreateHouseholdInvitationParams{
+		HouseholdID: member.HouseholdID,
+		Email:       req.Email,
+		Role:        req.Role,
+		TokenHash:   hashToken(token),
+		InvitedBy:   user.Username,
+		ExpiredAt:   time.Now().Add(server.config.HouseholdInvitationDuration),
+	})
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot create invitation."))
+		return
+	}
+
+	content := fmt.Sprintf("%s invited you to join the household %q as %s.\nUse this token to join: %s\nIt expires on %s.", user.Name, household.Name, req.Role, token, util.FormatDate(user.Locale, invitation.ExpiredAt.In(userLocation(user))))
+	if err := server.mailer.SendEmail(req.Email, "Household invitation", content); err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot send invitation."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, apitypes.InvitationResponse{
+		Message:   "invitation sent.",
+		Email:     invitation.Email,
+		Role:      invitation.Role,
+		ExpiredAt: invitation.ExpiredAt,
+	})
+}
+
+func (server *Server) JoinHousehold(ctx *gin.Context) {
+	u, exists := ctx.Get("user")
+	if !exists {
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "unauthorized"))
+		return
+	}
+	user, ok := u.(db.User)
+	if !ok {
+		respondError(ctx, apierror.Internal(nil, "invalid user type"))
+		return
+	}
+
+	var req apitypes.JoinHouseholdRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	invitation, err := server.store.GetHouseholdInvitation(ctx, hashToken(req.Token))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			respondError(ctx, apierror.New(http.StatusBadRequest, "invalid invitation token."))
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, "cannot get invitation."))
+		return
+	}
+
+	if invitation.AcceptedAt.Valid || time.Now().After(invitation.ExpiredAt) {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "invitation has expired."))
+		return
+	}
+
+	if !strings.EqualFold(invitation.Email, user.Email) {
+		respondError(ctx, apierror.New(http.StatusForbidden, "this invitation was sent to another email."))
+		return
+	}
+
+	// anyone can sign up with the invited address, only its owner has verified it
+	if !user.EmailVerifiedAt.Valid {
+		respondError(ctx, apierror.New(http.StatusForbidden, "verify your email before joining a household."))
+		return
+	}
+
+	member, err := server.store.AcceptHouseholdInvitationTx(ctx, invitation, user.Username)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			respondError(ctx, apierror.New(http.StatusBadRequest, "you are already a member of this household."))
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, "cannot join household."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newHouseholdMember(member))
+}
+
+func (server *Server) UpdateHouseholdMember(ctx *gin.Context) {
+	member := ctx.MustGet(householdMemberKey).(db.HouseholdMember)
+
+	var req apitypes.UpdateHouseholdMemberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	username := ctx.Param("username")
+	if username == member.Username {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "the owner role cannot be changed."))
+		return
+	}
+
+	updatedMember, err := server.store.UpdateHouseholdMemberRole(ctx, db.UpdateHouseholdMemberRoleParams{
+		Role:        req.Role,
+		HouseholdID: member.HouseholdID,
+		Username:    username,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			respondError(ctx, apierror.New(http.StatusNotFound, "member not found."))
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, "cannot update member."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newHouseholdMember(updatedMember))
+}
+
+// RemoveHouseholdMember lets the owner remove anyone but themselves, and any other member leave.
+func (server *Server) RemoveHouseholdMember(ctx *gin.Context) {
+	member := ctx.MustGet(householdMemberKey).(db.HouseholdMember)
+	username := ctx.Param("username")
+
+	if member.Role == householdRoleOwner && username == member.Username {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "the owner cannot leave the household."))
+		return
+	}
+
+	if member.Role != householdRoleOwner && username != member.Username {
+		respondError(ctx, apierror.New(http.StatusForbidden, "only the owner can remove other members."))
+		return
+	}
+
+	err := server.store.RemoveHouseholdMember(ctx, db.RemoveHouseholdMemberParams{
+		HouseholdID: member.HouseholdID,
+		Username:    username,
+	})
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot remove member."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, apitypes.MessageResponse{Message: "member removed."})
+}
+
+func (server *Server) HouseholdFinancials(ctx *gin.Context) {
+	member := ctx.MustGet(householdMemberKey).(db.HouseholdMember)
+
+	financials, err := server.store.HouseholdFinancials(ctx, pgtype.Int8{Int64: member.HouseholdID, Valid: true})
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get financial data."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convertAll(financials, newHouseholdFinancial))
+}
+
+// HouseholdSummary shows who paid what in a month.
+// The expenses are split evenly between the members, a positive balance means the member paid more than their share.
+func (server *Server) HouseholdSummary(ctx *gin.Context) {
+	member := ctx.MustGet(householdMemberKey).(db.HouseholdMember)
+
+	month, year, err := monthYearQuery(ctx)
+	if err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	members, err := server.store.ListHouseholdMembers(ctx, member.HouseholdID)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get household members."))
+		return
+	}
+
+	rows, err := server.store.SummaryHouseholdByMember(ctx, db.SummaryHouseholdByMemberParams{
+		HouseholdID: member.HouseholdID,
+		Month:       int32(month),
+		Year:        int32(year),
+	})
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get household summary."))
+		return
+	}
+
+	paid := map[string]db.SummaryHouseholdByMemberRow{}
+	var totalExpense int64
+	for _, row := range rows {
+		paid[row.UserID] = row
+		totalExpense += -row.TotalExpense
+	}
+
+	fairShare := 0.0
+	if len(members) > 0 {
+		fairShare = float64(totalExpense) / float64(len(members))
+	}
+
+	summary := make([]apitypes.HouseholdMemberSummary, 0, len(members))
+	for _, m := range members {
+		row := paid[m.Username]
+		summary = append(summary, apitypes.HouseholdMemberSummary{
+			Username:     m.Username,
+			TotalIncome:  row.TotalIncome,
+			TotalExpense: row.TotalExpense,
+			FairShare:    fairShare,
+			Balance:      float64(-row.TotalExpense) - fairShare,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, apitypes.HouseholdSummaryResponse{
+		Month:        month,
+		Year:         year,
+		TotalExpense: totalExpense,
+		Members:      summary,
+	})
+}
+
+func (server *Server) AddHouseholdBudget(ctx *gin.Context) {
+	user := ctx.MustGet("user").(db.User)
+	member := ctx.MustGet(householdMemberKey).(db.HouseholdMember)
+
+	var req apitypes.BudgetRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	year, month := currentPeriod(user)
+	budget, err := server.store.AddHouseholdBudgetTx(ctx, db.AddNewHouseholdBudgetParams{
+		UserID:      user.Username,
+		HouseholdID: pgtype.Int8{Int64: member.HouseholdID, Valid: true},
+		Month:       int32(month),
+		Year:        int32(year),
+		Amount:      pgtype.Numeric{Int: big.NewInt(req.Amount), Valid: true},
+	}, auditInfo(ctx, user))
+	if err != nil {
+		if errors.Is(err, db.ErrPeriodClosed) {
+			respondError(ctx, err)
+			return
+		}
+
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			respondError(ctx, apierror.New(http.StatusBadRequest, "the household already has budget in the current month."))
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, "cannot add household budget."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newBudget(budget))
+}
+
+// GetHouseholdBudget gets the household budget of the month and year in the query, the current month by default.
+func (server *Server) GetHouseholdBudget(ctx *gin.Context) {
+	member := ctx.MustGet(householdMemberKey).(db.HouseholdMember)
+
+	month, year, err := monthYearQuery(ctx)
+	if err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	budget, err := server.store.GetHouseholdBudget(ctx, db.GetHouseholdBudgetParams{
+		HouseholdID: pgtype.Int8{Int64: member.HouseholdID, Valid: true},
+		Month:       int32(month),
+		Year:        int32(year),
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			respondError(ctx, apierror.New(http.StatusNotFound, "budget not found."))
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, "cannot get household budget."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newBudget(budget))
+}
+
+// monthYearQuery reads the month and year query params, both default to the current month of the user.
+func monthYearQuery(ctx *gin.Context) (int, int, error) {
+	year, currentMonth := currentPeriod(ctx.MustGet("user").(db.User))
+	month := int(currentMonth)
+
+	if m := ctx.Query("month"); m != "" {
+		v, err := strconv.Atoi(m)
+		if err != nil || v < 1 || v > 12 {
+			return 0, 0, fmt.Errorf("invalid month")
+		}
+		month = v
+	}
+
+	if y := ctx.Query("year"); y != "" {
+		v, err := strconv.Atoi(y)
+		if err != nil || v < 2000 {
+			return 0, 0, fmt.Errorf("invalid year")
+		}
+		year = v
+	}
+
+	return month, year, nil
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/sangketkit01/personal-financial/db/sqlc"
+	"github.com/sangketkit01/personal-financial/util"
+	"github.com/stretchr/testify/require"
+)
+
+// serveAsMember runs handler as a member of household 1, the way HouseholdMiddleware leaves the context.
+func serveAsMember(handler gin.HandlerFunc, user db.User, role string, target string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = httptest.NewRequest(http.MethodGet, target, nil)
+	ctx.Set("user", user)
+	ctx.Set(householdMemberKey, db.HouseholdMember{HouseholdID: 1, Username: user.Username, Role: role})
+
+	handler(ctx)
+	return recorder
+}
+
+func TestGetHouseholdBudgetMonth(t *testing.T) {
+	user := db.User{Username: "alice", TimeZone: util.DefaultTimeZone, PeriodStartDay: 1}
+	year, month := currentPeriod(user)
+
+	store := newFakeStore()
+	store.addHouseholdBudget(db.Budget{ID: 1, HouseholdID: pgtype.Int8{Int64: 1, Valid: true}, Month: int32(month), Year: int32(year)})
+	store.addHouseholdBudget(db.Budget{ID: 2, HouseholdID: pgtype.Int8{Int64: 1, Valid: true}, Month: 3, Year: 2024})
+	server := newTestServer(t, store, util.Config{})
+
+	testCases := []struct {
+		name   string
+		target string
+		status int
+		id     string
+	}{
+		{"current month by default", "/budgets", http.StatusOK, `"id":1`},
+		{"month from the query", "/budgets?month=3&year=2024", http.StatusOK, `"id":2`},
+		{"month without a budget", "/budgets?month=4&year=2024", http.StatusNotFound, ""},
+		{"invalid month", "/budgets?month=13", http.StatusBadRequest, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveAsMember(server.GetHouseholdBudget, user, householdRoleViewer, tc.target)
+			require.Equal(t, tc.status, recorder.Code)
+			require.Contains(t, recorder.Body.String(), tc.id)
+		})
+	}
+}
+
+func TestFinancialMiddlewareHouseholdRoles(t *testing.T) {
+	household := pgtype.Int8{Int64: 1, Valid: true}
+
+	store := newFakeStore()
+	store.addFinancial(1, db.GetFinancialAccessRow{UserID: "bob", HouseholdID: household})
+	store.addFinancial(2, db.GetFinancialAccessRow{UserID: "bob"})
+	store.addFinancial(3, db.GetFinancialAccessRow{UserID: "alice"})
+	store.addHouseholdMember(db.HouseholdMember{HouseholdID: 1, Username: "bob", Role: householdRoleOwner})
+	store.addHouseholdMember(db.HouseholdMember{HouseholdID: 1, Username: "olivia", Role: householdRoleOwner})
+	store.addHouseholdMember(db.HouseholdMember{HouseholdID: 1, Username: "eve", Role: householdRoleEditor})
+	store.addHouseholdMember(db.HouseholdMember{HouseholdID: 1, Username: "victor", Role: householdRoleViewer})
+	server := newTestServer(t, store, util.Config{})
+
+	testCases := []struct {
+		name     string
+		username string
+		method   string
+		id       string
+		status   int
+	}{
+		{"own record", "alice", http.MethodPut, "3", http.StatusNoContent},
+		{"personal record of another user", "alice", http.MethodGet, "2", http.StatusForbidden},
+		{"member of another household", "victor", http.MethodGet, "2", http.StatusForbidden},
+		{"not a member", "alice", http.MethodGet, "1", http.StatusForbidden},
+		{"viewer reads", "victor", http.MethodGet, "1", http.StatusNoContent},
+		{"viewer cannot change", "victor", http.MethodPut, "1", http.StatusForbidden},
+		{"viewer cannot delete", "victor", http.MethodDelete, "1", http.StatusForbidden},
+		{"editor reads", "eve", http.MethodGet, "1", http.StatusNoContent},
+		{"editor changes", "eve", http.MethodPut, "1", http.StatusNoContent},
+		{"owner deletes", "olivia", http.MethodDelete, "1", http.StatusNoContent},
+		{"unknown record", "alice", http.MethodGet, "9", http.StatusNotFound},
+		{"invalid id", "alice", http.MethodGet, "abc", http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+			router.Any("/financials/:id",
+				func(ctx *gin.Context) { ctx.Set("user", db.User{Username: tc.username}) },
+				server.FinancialMiddleware(),
+				func(ctx *gin.Context) { ctx.Status(http.StatusNoContent) },
+			)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(tc.method, "/financials/"+tc.id, nil))
+			require.Equal(t, tc.status, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// startBackgroundJobs runs the periodic maintenance jobs of the server until ctx is canceled.
+func (server *Server) startBackgroundJobs(ctx context.Context) {
+	server.runEvery(ctx, server.config.AccountPurgeInterval, server.purgeDeletedAccounts)
+	server.runEvery(ctx, server.config.TrashPurgeInterval, server.purgeTrash)
+	server.runEvery(ctx, server.config.AnomalyScanInterval, server.scanAnomalies)
+	server.runEvery(ctx, server.config.LoginFailureWindow, server.purgeLoginFailures)
+
+	if server.oidc != nil {
+		server.runEvery(ctx, server.config.OIDCStateDuration, server.purgeExpiredOAuthStates)
+	}
+}
+
+// runEvery starts job in the background, a run in progress gets the canceled ctx and is waited for on shutdown.
+func (server *Server) runEvery(ctx context.Context, interval time.Duration, job func(ctx context.Context)) {
+	if interval <= 0 {
+		return
+	}
+
+	server.jobs.Add(1)
+	go func() {
+		defer server.jobs.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				job(ctx)
+			}
+		}
+	}()
+}
+
+// purgeDeletedAccounts hard deletes the accounts whose deletion grace period is over.
+func (server *Server) purgeDeletedAccounts(ctx context.Context) {
+	usernames, err := server.store.ListUsersToPurge(ctx, pgtype.Timestamptz{
+		Time:  time.Now().Add(-server.config.AccountDeletionGrace),
+		Valid: true,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "cannot list accounts to purge", "err", err)
+		return
+	}
+
+	for _, username := range usernames {
+		if err := server.store.PurgeUserTx(ctx, username); err != nil {
+			slog.ErrorContext(ctx, "cannot purge account", "username", username, "err", err)
+			continue
+		}
+
+		slog.InfoContext(ctx, "purged account", "username", username)
+	}
+}
+
+// purgeLoginFailures removes the failed logins that slid out of the window, they no longer count.
+func (server *Server) purgeLoginFailures(ctx context.Context) {
+	if err := server.store.DeleteLoginFailuresBefore(ctx, time.Now().Add(-server.config.LoginFailureWindow)); err != nil {
+		slog.ErrorContext(ctx, "cannot delete old login failures", "err", err)
+	}
+}
+
+// purgeExpiredOAuthStates removes the login attempts that were never completed.
+func (server *Server) purgeExpiredOAuthStates(ctx context.Context) {
+	if err := server.store.DeleteExpiredOAuthStates(ctx); err != nil {
+		slog.ErrorContext(ctx, "cannot delete expired oauth states", "err", err)
+	}
+}
+
+// purgeTrash hard deletes the financials that stayed in the trash longer than the retention period.
+func (server *Server) purgeTrash(ctx context.Context) {
+	if server.config.TrashRetentionDays <= 0 {
+		return
+	}
+
+	deletedBefore := time.Now().AddDate(0, 0, -server.config.TrashRetentionDays)
+	financials, err := server.store.PurgeFinancialsTx(ctx, deletedBefore)
+	if err != nil {
+		slog.ErrorContext(ctx, "cannot purge the trash", "err", err)
+		return
+	}
+
+	if len(financials) > 0 {
+		slog.InfoContext(ctx, "purged financials from the trash", "count", len(financials))
+	}
+}
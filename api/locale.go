@@ -0,0 +1,37 @@
+package api
+
+import (
+	"time"
+
+	db "github.com/sangketkit01/personal-financial/db/sqlc"
+	"github.com/sangketkit01/personal-financial/util"
+)
+
+// userLocation is the time zone the periods of the user are computed in.
+func userLocation(user db.User) *time.Location {
+	return util.Location(user.TimeZone)
+}
+
+// userNow is the current time in the time zone of the user.
+func userNow(user db.User) time.Time {
+	return time.Now().In(userLocation(user))
+}
+
+// userCalendar cuts time into the months and fiscal years the user has chosen.
+func userCalendar(user db.User) util.Calendar {
+	return util.Calendar{
+		Location:         userLocation(user),
+		StartDay:         int(user.PeriodStartDay),
+		FiscalStartMonth: int(user.FiscalYearStartMonth),
+	}
+}
+
+// currentPeriod returns the year and month of the budgeting month the user is in now.
+func currentPeriod(user db.User) (int, time.Month) {
+	return userCalendar(user).Period(time.Now())
+}
+
+// currentFiscalYear returns the fiscal year the user is in now.
+func currentFiscalYear(user db.User) int {
+	return userCalendar(user).FiscalYear(time.Now())
+}
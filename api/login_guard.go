@@ -0,0 +1,257 @@
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/sangketkit01/personal-financial/apierror"
+	"github.com/sangketkit01/personal-financial/apitypes"
+	db "github.com/sangketkit01/personal-financial/db/sqlc"
+	"github.com/sangketkit01/personal-financial/util"
+)
+
+const (
+	loginScopeUsername = "username"
+	loginScopeIP       = "ip"
+)
+
+type loginKey struct {
+	scope      string
+	identifier string
+}
+
+func loginKeys(ctx *gin.Context, username string) []loginKey {
+	return []loginKey{
+		{scope: loginScopeUsername, identifier: username},
+		{scope: loginScopeIP, identifier: ctx.ClientIP()},
+	}
+}
+
+// maxLoginBackoffExponent caps how often the wait doubles, 2^20 times the base is far past any lockout.
+const maxLoginBackoffExponent = 20
+
+// loginBackoff returns how long a key has to wait after failedCount failures
+// and whether the key is now locked out. The wait doubles on every failure
+// and is capped by the lockout duration.
+func loginBackoff(config util.Config, failedCount int32) (time.Duration, bool) {
+	if failedCount <= 0 {
+		return 0, false
+	}
+
+	if config.LoginMaxAttempts > 0 && int(failedCount) >= config.LoginMaxAttempts {
+		return config.LoginLockoutDuration, true
+	}
+
+	exponent := min(failedCount-1, maxLoginBackoffExponent)
+	if config.LoginBackoffBase > config.LoginLockoutDuration>>exponent {
+		return config.LoginLockoutDuration, false
+	}
+
+	return config.LoginBackoffBase << exponent, false
+}
+
+// loginWaits lists the wait in microseconds after one failure, two failures and so on, for RecordFailedLogin.
+// It ends at the lockout or once the wait stops growing, more failures wait as long as the last entry.
+func loginWaits(config util.Config) []int64 {
+	var waits []int64
+	for failedCount := int32(1); ; failedCount++ {
+		wait, locked := loginBackoff(config, failedCount)
+		waits = append(waits, wait.Microseconds())
+
+		if locked || wait == config.LoginLockoutDuration || failedCount > maxLoginBackoffExponent {
+			return waits
+		}
+	}
+}
+
+// loginRetryAfter returns how long the caller has to wait before trying to log in
+// with this username from this ip again. Zero means the attempt is allowed.
+func (server *Server) loginRetryAfter(ctx *gin.Context, username string) (time.Duration, error) {
+	var retryAfter time.Duration
+
+	for _, key := range loginKeys(ctx, username) {
+		attempt, err := server.store.GetLoginAttempt(ctx, db.GetLoginAttemptParams{
+			Scope:      key.scope,
+			Identifier: key.identifier,
+		})
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				continue
+			}
+
+			return 0, err
+		}
+
+		if wait := time.Until(attempt.LockedUntil); wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+
+	return retryAfter, nil
+}
+
+// recordFailedLogin counts a failed attempt against both the username and the client ip,
+// pushes their next allowed attempt back and writes an audit record for every lockout.
+// Only the failures within LoginFailureWindow count, older ones slide out of the window.
+// The returned wait is not zero when a key was already locked by a parallel attempt,
+// the attempt is then answered like one that was blocked up front.
+func (server *Server) recordFailedLogin(ctx *gin.Context, username string) (time.Duration, error) {
+	var retryAfter time.Duration
+
+	for _, key := range loginKeys(ctx, username) {
+		result, err := server.store.RecordFailedLoginTx(ctx, db.RecordFailedLoginParams{
+			Scope:       key.scope,
+			Identifier:  key.identifier,
+			Waits:       loginWaits(server.config),
+			WindowStart: time.Now().Add(-server.config.LoginFailureWindow),
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		attempt := result.Attempt
+		if result.WasLocked {
+			retryAfter = max(retryAfter, time.Until(attempt.LockedUntil))
+			continue
+		}
+
+		if _, locked := loginBackoff(server.config, attempt.FailedCount); locked {
+			_, err = server.store.CreateLockoutAudit(ctx, db.CreateLockoutAuditParams{
+				Scope:       key.scope,
+				Identifier:  key.identifier,
+				FailedCount: attempt.FailedCount,
+				ClientIp:    ctx.ClientIP(),
+				LockedUntil: attempt.LockedUntil,
+			})
+			if err != nil {
+				return 0, err
+			}
+
+			slog.WarnContext(ctx, "login locked", "scope", key.scope, "identifier", key.identifier, "locked_until", attempt.LockedUntil)
+		}
+	}
+
+	return retryAfter, nil
+}
+
+// resetLoginAttempts clears the failed attempts of a username.
+// The failures of the client ip are left to slide out of the window, logging into an account
+// of your own must not lift a lockout earned by guessing the passwords of others.
+func (server *Server) resetLoginAttempts(ctx *gin.Context, username string) error {
+	return server.store.ResetLoginAttempts(ctx, db.ResetLoginAttemptsParams{
+		Scope:      loginScopeUsername,
+		Identifier: username,
+	})
+}
+
+// failLogin records a failed login and answers it with a 401, or with a 429 when a parallel attempt
+// locked the username or the ip meanwhile, so the answer doesn't tell a locked key's passwords apart.
+func (server *Server) failLogin(ctx *gin.Context, username string, detail string) {
+	retryAfter, err := server.recordFailedLogin(ctx, username)
+	if err != nil {
+		slog.WarnContext(ctx, "cannot record failed login", "username", username, "err", err)
+	}
+
+	if retryAfter > 0 {
+		tooManyLoginAttempts(ctx, retryAfter)
+		return
+	}
+
+	respondError(ctx, apierror.New(http.StatusUnauthorized, detail))
+}
+
+func tooManyLoginAttempts(ctx *gin.Context, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	ctx.Header("Retry-After", strconv.Itoa(seconds))
+	respondError(ctx, apierror.New(http.StatusTooManyRequests, fmt.Sprintf("too many failed login attempts, try again in %d seconds.", seconds)))
+}
+
+// RequestUnlock emails an unlock link to the owner of a locked account.
+// It always answers with the same message so it can't be used to find registered emails.
+func (server *Server) RequestUnlock(ctx *gin.Context) {
+	var req apitypes.RequestUnlockRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	response := apitypes.MessageResponse{Message: "if the email is registered, an unlock link has been sent."}
+
+	user, err := server.store.GetUserByEmail(ctx, req.Email)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			ctx.JSON(http.StatusOK, response)
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, "cannot get user"))
+		return
+	}
+
+	token, err := util.RandomToken(32)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot create unlock token"))
+		return
+	}
+
+	unlockToken, err := server.store.CreateUnlockToken(ctx, db.CreateUnlockTokenParams{
+		TokenHash: hashToken(token),
+		Username:  user.Username,
+		ExpiredAt: time.Now().Add(server.config.UnlockTokenDuration),
+	})
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot create unlock token"))
+		return
+	}
+
+	content := fmt.Sprintf("Hello %s,\n\nUse this token to unlock your account: %s\nIt expires at %s.", user.Name, token, util.FormatDateTime(user.Locale, unlockToken.ExpiredAt.In(userLocation(user))))
+	if err := server.mailer.SendEmail(user.Email, "Unlock your account", content); err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot send unlock email"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// UnlockAccount clears the failed login attempts of the user who owns the unlock token.
+func (server *Server) UnlockAccount(ctx *gin.Context) {
+	var req apitypes.UnlockAccountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	unlockToken, err := server.store.GetUnlockToken(ctx, hashToken(req.Token))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			respondError(ctx, apierror.New(http.StatusBadRequest, "invalid unlock token"))
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, "cannot get unlock token"))
+		return
+	}
+
+	if unlockToken.UsedAt.Valid || time.Now().After(unlockToken.ExpiredAt) {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "unlock token has expired"))
+		return
+	}
+
+	if err := server.resetLoginAttempts(ctx, unlockToken.Username); err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot unlock account"))
+		return
+	}
+
+	if err := server.store.UseUnlockToken(ctx, unlockToken.TokenHash); err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot unlock account"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, apitypes.MessageResponse{Message: "your account has been unlocked."})
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/sangketkit01/personal-financial/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginBackoff(t *testing.T) {
+	config := util.Config{
+		LoginMaxAttempts:     5,
+		LoginBackoffBase:     time.Second,
+		LoginLockoutDuration: 15 * time.Minute,
+	}
+
+	unlimited := config
+	unlimited.LoginMaxAttempts = 0
+
+	long := unlimited
+	long.LoginBackoffBase = time.Hour
+	long.LoginLockoutDuration = time.Duration(math.MaxInt64)
+
+	testCases := []struct {
+		name        string
+		config      util.Config
+		failedCount int32
+		wait        time.Duration
+		locked      bool
+	}{
+		{"no failures", config, 0, 0, false},
+		{"first failure", config, 1, time.Second, false},
+		{"doubles", config, 3, 4 * time.Second, false},
+		{"last before lockout", config, 4, 8 * time.Second, false},
+		{"lockout", config, 5, 15 * time.Minute, true},
+		{"past lockout", config, 9, 15 * time.Minute, true},
+		{"capped by the lockout duration", unlimited, 11, 15 * time.Minute, false},
+		{"no lockout without max attempts", unlimited, 1000, 15 * time.Minute, false},
+		{"exponent is clamped", long, 1000, time.Hour << maxLoginBackoffExponent, false},
+		{"no overflow", long, 64, time.Hour << maxLoginBackoffExponent, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			wait, locked := loginBackoff(tc.config, tc.failedCount)
+			require.Equal(t, tc.wait, wait)
+			require.Equal(t, tc.locked, locked)
+			require.GreaterOrEqual(t, wait, time.Duration(0))
+		})
+	}
+}
+
+func TestLoginWaits(t *testing.T) {
+	config := util.Config{
+		LoginMaxAttempts:     5,
+		LoginBackoffBase:     time.Second,
+		LoginLockoutDuration: 15 * time.Minute,
+	}
+
+	require.Equal(t, []int64{1e6, 2e6, 4e6, 8e6, (15 * time.Minute).Microseconds()}, loginWaits(config))
+
+	// without a lockout the waits end where the lockout duration caps them
+	config.LoginMaxAttempts = 0
+	config.LoginLockoutDuration = 5 * time.Second
+	require.Equal(t, []int64{1e6, 2e6, 4e6, 5e6}, loginWaits(config))
+
+	// and never run past the clamped exponent
+	config.LoginLockoutDuration = time.Duration(math.MaxInt64)
+	require.Len(t, loginWaits(config), maxLoginBackoffExponent+1)
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/sangketkit01/personal-financial/db/sqlc"
+	"github.com/sangketkit01/personal-financial/token"
+	"github.com/sangketkit01/personal-financial/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+func newTestServer(t *testing.T, store db.Store, config util.Config) *Server {
+	t.Helper()
+
+	if config.OIDCStateDuration == 0 {
+		config.OIDCStateDuration = 10 * time.Minute
+	}
+
+	secret, err := util.RandomToken(32)
+	require.NoError(t, err)
+
+	tokenMaker, err := token.NewJWTMaker(secret)
+	require.NoError(t, err)
+
+	server, err := NewServer(config, store, tokenMaker)
+	require.NoError(t, err)
+
+	return server
+}
+
+// serveJSON runs handler on a request with body, as user when it is not nil.
+func serveJSON(handler gin.HandlerFunc, user *db.User, method string, target string, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = httptest.NewRequest(method, target, strings.NewReader(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	if user != nil {
+		ctx.Set("user", *user)
+	}
+
+	handler(ctx)
+	return recorder
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sangketkit01/personal-financial/metrics"
+)
+
+type serverMetrics struct {
+	registry *metrics.Registry
+
+	requests        *metrics.CounterVec
+	requestDuration *metrics.HistogramVec
+
+	financialsCreated *metrics.CounterVec
+	budgetAlerts      *metrics.CounterVec
+	anomaliesFound    *metrics.CounterVec
+}
+
+func (server *Server) setupMetrics() {
+	registry := metrics.NewRegistry()
+
+	server.metrics = &serverMetrics{
+		registry: registry,
+		requests: registry.NewCounterVec("http_requests_total",
+			"HTTP requests by route and status.", "method", "route", "status"),
+		requestDuration: registry.NewHistogramVec("http_request_duration_seconds",
+			"HTTP request latency by route.", metrics.DefaultBuckets, "method", "route"),
+		financialsCreated: registry.NewCounterVec("financials_created_total",
+			"Financial records created through the API."),
+		budgetAlerts: registry.NewCounterVec("budget_alerts_total",
+			"New records that took the spending of the month to or over the budget."),
+		anomaliesFound: registry.NewCounterVec("anomalies_detected_total",
+			"Anomalies found in new records by kind.", "kind"),
+	}
+
+	pool := func(stat func(s *pgxpool.Stat) float64) func() float64 {
+		return func() float64 {
+			return stat(server.store.PoolStat())
+		}
+	}
+	registry.NewGaugeFunc("db_pool_max_conns", "Maximum size of the connection pool.",
+		pool(func(s *pgxpool.Stat) float64 { return float64(s.MaxConns()) }))
+	registry.NewGaugeFunc("db_pool_total_conns", "Open connections in the pool.",
+		pool(func(s *pgxpool.Stat) float64 { return float64(s.TotalConns()) }))
+	registry.NewGaugeFunc("db_pool_acquired_conns", "Connections in use.",
+		pool(func(s *pgxpool.Stat) float64 { return float64(s.AcquiredConns()) }))
+	registry.NewGaugeFunc("db_pool_idle_conns", "Idle connections.",
+		pool(func(s *pgxpool.Stat) float64 { return float64(s.IdleConns()) }))
+	registry.NewGaugeFunc("db_pool_acquire_count", "Connections acquired from the pool since start.",
+		pool(func(s *pgxpool.Stat) float64 { return float64(s.AcquireCount()) }))
+	registry.NewGaugeFunc("db_pool_empty_acquire_count", "Acquires that had to wait for a connection since start.",
+		pool(func(s *pgxpool.Stat) float64 { return float64(s.EmptyAcquireCount()) }))
+	registry.NewGaugeFunc("db_pool_acquire_duration_seconds", "Total time spent waiting for connections since start.",
+		pool(func(s *pgxpool.Stat) float64 { return s.AcquireDuration().Seconds() }))
+}
+
+// metricsMiddleware counts every request and its latency under the route pattern, not the raw path,
+// so ids in the path do not create a series each.
+func (server *Server) metricsMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		route := requestRoute(ctx)
+		method := ctx.Request.Method
+		server.metrics.requests.Inc(method, route, strconv.Itoa(ctx.Writer.Status()))
+		server.metrics.requestDuration.Observe(time.Since(start).Seconds(), method, route)
+	}
+}
+
+// Metrics serves the metrics in the Prometheus text format.
+func (server *Server) Metrics(ctx *gin.Context) {
+	ctx.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	ctx.Status(http.StatusOK)
+
+	if err := server.metrics.registry.Write(ctx.Writer); err != nil {
+		ctx.Error(err)
+	}
+}
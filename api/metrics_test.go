@@ -0,0 +1,32 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sangketkit01/personal-financial/metrics"
+	"github.com/sangketkit01/personal-financial/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsCountPanics(t *testing.T) {
+	server := newTestServer(t, newFakeStore(), util.Config{})
+	// the pool gauges need a real pool, only the request counter is written here
+	server.metrics.registry = metrics.NewRegistry()
+	server.metrics.requests = server.metrics.registry.NewCounterVec("http_requests_total",
+		"HTTP requests by route and status.", "method", "route", "status")
+	server.router.GET("/test/panic", func(ctx *gin.Context) {
+		panic("handler failed")
+	})
+
+	recorder := httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/test/panic", nil))
+	require.Equal(t, http.StatusInternalServerError, recorder.Code)
+
+	var output bytes.Buffer
+	require.NoError(t, server.metrics.registry.Write(&output))
+	require.Contains(t, output.String(), `http_requests_total{method="GET",route="/test/panic",status="500"} 1`)
+}
@@ -1,13 +1,15 @@
 package api
 
 import (
-	"database/sql"
 	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/sangketkit01/personal-financial/apierror"
 	db "github.com/sangketkit01/personal-financial/db/sqlc"
 	"github.com/sangketkit01/personal-financial/token"
 )
@@ -23,37 +25,70 @@ func (server *Server) authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
 
 		if len(authorizationHeader) == 0 {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authorization header is not provided"})
+			respondError(ctx, apierror.New(http.StatusUnauthorized, "authorization header is not provided"))
 			return
 		}
 
 		fields := strings.Fields(authorizationHeader)
-		if len(fields) > 2 {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization header format"})
+		if len(fields) != 2 {
+			respondError(ctx, apierror.New(http.StatusUnauthorized, "invalid authorization header format"))
 			return
 		}
 
 		authType := strings.ToLower(fields[0])
 		if authType != authorizationHeaderType {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("unsupported authorization type: %s", authType)})
+			respondError(ctx, apierror.New(http.StatusUnauthorized, fmt.Sprintf("unsupported authorization type: %s", authType)))
 			return
 		}
 
 		accessToken := fields[1]
-		payload, err := tokenMaker.VerifyToken(accessToken)
-		if err != nil {
-			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
+
+		var username string
+		var issuedAt time.Time
+		if strings.HasPrefix(accessToken, personalAccessTokenPrefix) {
+			pat, err := server.verifyPersonalAccessToken(ctx, accessToken)
+			if err != nil {
+				respondError(ctx, err)
+				return
+			}
+
+			username = pat.Username
+			issuedAt = pat.CreatedAt
+			ctx.Set(tokenScopesKey, pat.Scopes)
+		} else {
+			payload, err := tokenMaker.VerifyToken(accessToken)
+			if err != nil {
+				respondError(ctx, err)
+				return
+			}
+
+			username = payload.Username
+			issuedAt = payload.IssuedAt
 		}
 
-		user, err := server.store.GetUser(ctx, payload.Username)
+		user, err := server.store.GetUser(ctx, username)
 		if err != nil {
-			if err == sql.ErrNoRows {
-				ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "user not found, then why are you here ?"})
+			if err == pgx.ErrNoRows {
+				respondError(ctx, apierror.New(http.StatusUnauthorized, "the user of this token does not exist."))
 				return
 			}
 
-			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "cannot get user data."})
+			respondError(ctx, apierror.Internal(err, "cannot get user data."))
+			return
+		}
+
+		if user.DisabledAt.Valid {
+			respondError(ctx, apierror.New(http.StatusForbidden, "account has been disabled."))
+			return
+		}
+
+		if user.TokensValidAfter.Valid && issuedAt.Before(user.TokensValidAfter.Time) {
+			respondError(ctx, apierror.New(http.StatusUnauthorized, "token has been revoked"))
+			return
+		}
+
+		if user.DeletedAt.Valid {
+			respondError(ctx, apierror.New(http.StatusForbidden, "account is scheduled for deletion, log in again to restore it."))
 			return
 		}
 
@@ -62,34 +97,70 @@ func (server *Server) authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 	}
 }
 
+// roleMiddleware must run after authMiddleware, it only lets users with one of the roles through.
+func (server *Server) roleMiddleware(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user := ctx.MustGet("user").(db.User)
+
+		for _, role := range roles {
+			if user.Role == role {
+				ctx.Next()
+				return
+			}
+		}
+
+		respondError(ctx, apierror.New(http.StatusForbidden, "you are not allowed to access this resource"))
+	}
+}
+
 func (server *Server) FinancialMiddleware() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		user := ctx.MustGet("user").(db.User)
 
 		financialId, err := strconv.Atoi(ctx.Param("id"))
 		if err != nil || financialId <= 0 {
-			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid financial id."})
+			respondError(ctx, apierror.New(http.StatusBadRequest, "invalid financial id."))
 			return
 		}
 
-		owner, err := server.store.GetFinancialOwner(ctx, int64(financialId))
+		access, err := server.store.GetFinancialAccess(ctx, int64(financialId))
 		if err != nil {
-			if err == sql.ErrNoRows {
-				ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "no financial found."})
+			if err == pgx.ErrNoRows {
+				respondError(ctx, apierror.New(http.StatusNotFound, "no financial found."))
 				return
 			}
 
-			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(ctx, err)
 			return
 		}
 
-		if user.Username != owner {
-			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
-				"status":  http.StatusForbidden,
-				"message": "you are not authorized to access this financial record",
+		if user.Username != access.UserID {
+			if !access.HouseholdID.Valid {
+				respondError(ctx, apierror.New(http.StatusForbidden, "you are not authorized to access this financial record"))
+
+				return
+			}
+
+			// shared records can be read by every household member and changed by editors
+			member, err := server.store.GetHouseholdMember(ctx, db.GetHouseholdMemberParams{
+				HouseholdID: access.HouseholdID.Int64,
+				Username:    user.Username,
 			})
+			if err != nil && err != pgx.ErrNoRows {
+				respondError(ctx, err)
+				return
+			}
 
-			return
+			minRole := householdRoleEditor
+			if ctx.Request.Method == http.MethodGet {
+				minRole = householdRoleViewer
+			}
+
+			if err == pgx.ErrNoRows || !hasHouseholdRole(member.Role, minRole) {
+				respondError(ctx, apierror.New(http.StatusForbidden, "you are not authorized to access this financial record"))
+
+				return
+			}
 		}
 
 		ctx.Next()
@@ -0,0 +1,204 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/sangketkit01/personal-financial/apierror"
+	db "github.com/sangketkit01/personal-financial/db/sqlc"
+	"github.com/sangketkit01/personal-financial/util"
+	"github.com/stretchr/testify/require"
+)
+
+// newMiddlewareRouter serves /test behind authMiddleware and the given middlewares, the handler answers 204.
+func newMiddlewareRouter(server *Server, middlewares ...gin.HandlerFunc) *gin.Engine {
+	handlers := append([]gin.HandlerFunc{server.authMiddleware(server.tokenMaker)}, middlewares...)
+	handlers = append(handlers, func(ctx *gin.Context) { ctx.Status(http.StatusNoContent) })
+
+	router := gin.New()
+	router.Any("/test", handlers...)
+	return router
+}
+
+func serveWithToken(t *testing.T, router *gin.Engine, method string, token string) (int, apierror.Problem) {
+	t.Helper()
+
+	request := httptest.NewRequest(method, "/test", nil)
+	request.Header.Set(authorizationHeaderKey, "Bearer "+token)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	var problem apierror.Problem
+	if recorder.Code >= http.StatusBadRequest {
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+	}
+	return recorder.Code, problem
+}
+
+func addTestPersonalAccessToken(store *fakeStore, secret string, update func(pat *db.PersonalAccessToken)) string {
+	token := personalAccessTokenPrefix + secret
+	pat := db.PersonalAccessToken{
+		ID:        int64(len(store.pats) + 1),
+		Username:  "alice",
+		TokenHash: hashToken(token),
+		Scopes:    []string{scopeReadFinancial},
+		CreatedAt: time.Now().Add(-time.Hour),
+	}
+	if update != nil {
+		update(&pat)
+	}
+
+	store.addPersonalAccessToken(pat)
+	return token
+}
+
+func TestPersonalAccessTokenErrors(t *testing.T) {
+	past := pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true}
+
+	testCases := []struct {
+		name   string
+		update func(pat *db.PersonalAccessToken)
+		token  string
+		status int
+		code   apierror.Code
+	}{
+		{name: "valid", status: http.StatusNoContent},
+		{name: "unknown", token: personalAccessTokenPrefix + "unknown", status: http.StatusUnauthorized, code: apierror.CodeInvalidToken},
+		{name: "revoked", update: func(pat *db.PersonalAccessToken) { pat.RevokedAt = past }, status: http.StatusUnauthorized, code: apierror.CodeInvalidToken},
+		{name: "expired", update: func(pat *db.PersonalAccessToken) { pat.ExpiredAt = past }, status: http.StatusUnauthorized, code: apierror.CodeTokenExpired},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := newFakeStore()
+			store.addUser(db.User{Username: "alice"})
+			server := newTestServer(t, store, util.Config{})
+
+			token := addTestPersonalAccessToken(store, "secret", tc.update)
+			if tc.token != "" {
+				token = tc.token
+			}
+
+			status, problem := serveWithToken(t, newMiddlewareRouter(server), http.MethodGet, token)
+			require.Equal(t, tc.status, status)
+			require.Equal(t, tc.code, problem.Code)
+		})
+	}
+}
+
+func TestPersonalAccessTokenStoreFailure(t *testing.T) {
+	store := newFakeStore()
+	store.addUser(db.User{Username: "alice"})
+	server := newTestServer(t, store, util.Config{})
+
+	token := addTestPersonalAccessToken(store, "secret", nil)
+	store.patErr = errors.New("connection refused by 10.0.0.5")
+
+	status, problem := serveWithToken(t, newMiddlewareRouter(server), http.MethodGet, token)
+	require.Equal(t, http.StatusInternalServerError, status)
+	require.Equal(t, apierror.CodeInternal, problem.Code)
+	require.NotContains(t, problem.Detail, "10.0.0.5")
+}
+
+func TestPersonalAccessTokenTouchInterval(t *testing.T) {
+	store := newFakeStore()
+	store.addUser(db.User{Username: "alice"})
+	server := newTestServer(t, store, util.Config{})
+	router := newMiddlewareRouter(server)
+
+	token := addTestPersonalAccessToken(store, "secret", nil)
+	for range 3 {
+		status, _ := serveWithToken(t, router, http.MethodGet, token)
+		require.Equal(t, http.StatusNoContent, status)
+	}
+	require.Equal(t, 1, store.patTouches)
+
+	// once last_used_at is older than the interval it is updated again
+	stale := addTestPersonalAccessToken(store, "stale", func(pat *db.PersonalAccessToken) {
+		pat.LastUsedAt = pgtype.Timestamptz{Time: time.Now().Add(-personalAccessTokenTouchInterval - time.Second), Valid: true}
+	})
+	status, _ := serveWithToken(t, router, http.MethodGet, stale)
+	require.Equal(t, http.StatusNoContent, status)
+	require.Equal(t, 2, store.patTouches)
+}
+
+func TestScopeMiddleware(t *testing.T) {
+	testCases := []struct {
+		name   string
+		scopes []string
+		method string
+		status int
+	}{
+		{"read scope reads", []string{scopeReadFinancial}, http.MethodGet, http.StatusNoContent},
+		{"read scope cannot write", []string{scopeReadFinancial}, http.MethodPost, http.StatusForbidden},
+		{"write scope writes", []string{scopeWriteFinancial}, http.MethodDelete, http.StatusNoContent},
+		{"write implies read", []string{scopeWriteFinancial}, http.MethodGet, http.StatusNoContent},
+		{"other scopes", []string{scopeReadBudget, scopeWriteBudget}, http.MethodGet, http.StatusForbidden},
+		{"no scopes", []string{}, http.MethodGet, http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := newFakeStore()
+			store.addUser(db.User{Username: "alice", Role: roleUser})
+			server := newTestServer(t, store, util.Config{})
+			router := newMiddlewareRouter(server, server.scopeMiddleware(scopeReadFinancial, scopeWriteFinancial))
+
+			token := addTestPersonalAccessToken(store, "secret", func(pat *db.PersonalAccessToken) { pat.Scopes = tc.scopes })
+			status, problem := serveWithToken(t, router, tc.method, token)
+			require.Equal(t, tc.status, status)
+			if tc.status == http.StatusForbidden {
+				require.Equal(t, apierror.CodeForbidden, problem.Code)
+			}
+		})
+	}
+}
+
+func TestScopeMiddlewareSession(t *testing.T) {
+	store := newFakeStore()
+	store.addUser(db.User{Username: "alice", Role: roleUser})
+	server := newTestServer(t, store, util.Config{})
+	router := newMiddlewareRouter(server, server.scopeMiddleware(scopeReadFinancial, scopeWriteFinancial))
+
+	// login sessions are not limited by scopes
+	token, err := server.tokenMaker.CreateToken("alice", time.Minute)
+	require.NoError(t, err)
+
+	status, _ := serveWithToken(t, router, http.MethodPost, token)
+	require.Equal(t, http.StatusNoContent, status)
+}
+
+func TestAdminScope(t *testing.T) {
+	testCases := []struct {
+		name   string
+		role   string
+		scopes []string
+		status int
+	}{
+		{"admin with the admin scope", roleAdmin, []string{scopeAdmin}, http.StatusNoContent},
+		{"admin without the admin scope", roleAdmin, []string{scopeWriteFinancial}, http.StatusForbidden},
+		{"admin scope needs the admin role", roleUser, []string{scopeAdmin}, http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := newFakeStore()
+			store.addUser(db.User{Username: "alice", Role: tc.role})
+			server := newTestServer(t, store, util.Config{})
+			router := newMiddlewareRouter(server, server.scopeMiddleware(scopeAdmin, scopeAdmin), server.roleMiddleware(roleAdmin))
+
+			token := addTestPersonalAccessToken(store, "secret", func(pat *db.PersonalAccessToken) { pat.Scopes = tc.scopes })
+			for _, method := range []string{http.MethodGet, http.MethodPost} {
+				status, _ := serveWithToken(t, router, method, token)
+				require.Equal(t, tc.status, status, method)
+			}
+		})
+	}
+}
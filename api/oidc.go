@@ -0,0 +1,216 @@
+package api
+
+import (
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/sangketkit01/personal-financial/apierror"
+	db "github.com/sangketkit01/personal-financial/db/sqlc"
+	"github.com/sangketkit01/personal-financial/oidc"
+)
+
+// oidcStateCookie binds a login to the browser that started it, the callback only accepts the state found in it.
+// Without it an attacker could make a victim finish a login started by the attacker and use the attacker's account.
+const oidcStateCookie = "oidc_state"
+
+// errEmailNotVerified means the identity matches an account whose email was never verified, it is not linked.
+var errEmailNotVerified = errors.New("local email is not verified")
+
+// OIDCLogin starts the authorization code flow and redirects to the identity provider.
+// The state, nonce and PKCE verifier are kept in the database until the callback,
+// the state is also set in an HttpOnly cookie the callback compares.
+func (server *Server) OIDCLogin(ctx *gin.Context) {
+	state, err := oidc.RandomString()
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot start login"))
+		return
+	}
+
+	nonce, err := oidc.RandomString()
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot start login"))
+		return
+	}
+
+	verifier, err := oidc.RandomString()
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot start login"))
+		return
+	}
+
+	authURL, err := server.oidc.AuthCodeURL(ctx, state, nonce, oidc.CodeChallenge(verifier))
+	if err != nil {
+		slog.ErrorContext(ctx, "cannot build authorization url", "err", err)
+		respondError(ctx, apierror.New(http.StatusBadGateway, "identity provider is not available"))
+		return
+	}
+
+	_, err = server.store.CreateOAuthState(ctx, db.CreateOAuthStateParams{
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiredAt:    time.Now().Add(server.config.OIDCStateDuration),
+	})
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot start login"))
+		return
+	}
+
+	server.setOIDCStateCookie(ctx, state, int(server.config.OIDCStateDuration.Seconds()))
+	ctx.Redirect(http.StatusFound, authURL)
+}
+
+// setOIDCStateCookie sets the state cookie, a negative maxAge deletes it.
+// It is Lax so it is sent along the top level redirect back from the provider.
+func (server *Server) setOIDCStateCookie(ctx *gin.Context, state string, maxAge int) {
+	secure := strings.HasPrefix(server.config.OIDCRedirectURL, "https://")
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		Path:     "/",
+		MaxAge:   maxAge,
+		Secure:   secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// OIDCCallback finishes the login. The provider identity is looked up first, on the first login it is linked
+// to the user with the same email when both the provider and the user verified it.
+func (server *Server) OIDCCallback(ctx *gin.Context) {
+	if providerError := ctx.Query("error"); providerError != "" {
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "login failed: "+providerError))
+		return
+	}
+
+	code := ctx.Query("code")
+	stateParam := ctx.Query("state")
+	if code == "" || stateParam == "" {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "code and state are required"))
+		return
+	}
+
+	cookieState, err := ctx.Cookie(oidcStateCookie)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookieState), []byte(stateParam)) != 1 {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "login was not started in this browser, please try again"))
+		return
+	}
+	server.setOIDCStateCookie(ctx, "", -1)
+
+	// the state is deleted as it is read, so a callback cannot be replayed
+	state, err := server.store.ConsumeOAuthState(ctx, stateParam)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			respondError(ctx, apierror.New(http.StatusBadRequest, "invalid state"))
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, "cannot check state"))
+		return
+	}
+
+	if time.Now().After(state.ExpiredAt) {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "login has expired, please try again"))
+		return
+	}
+
+	rawIDToken, err := server.oidc.Exchange(ctx, code, state.CodeVerifier)
+	if err != nil {
+		slog.WarnContext(ctx, "cannot exchange authorization code", "err", err)
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "cannot complete login with the identity provider"))
+		return
+	}
+
+	claims, err := server.oidc.Verify(ctx, rawIDToken, state.Nonce)
+	if err != nil {
+		slog.WarnContext(ctx, "cannot verify id token", "err", err)
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "invalid id token"))
+		return
+	}
+
+	user, err := server.userForIdentity(ctx, claims)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			respondError(ctx, apierror.New(http.StatusNotFound, "no account matches this identity, sign up or verify your email first"))
+			return
+		}
+
+		if err == errEmailNotVerified {
+			respondError(ctx, apierror.New(http.StatusForbidden, "an account uses this email but has not verified it, log in with the password and verify the email first"))
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, "cannot get user"))
+		return
+	}
+
+	if user.DisabledAt.Valid {
+		respondError(ctx, apierror.New(http.StatusForbidden, "account has been disabled"))
+		return
+	}
+
+	if user.DeletedAt.Valid {
+		// logging in during the grace period cancels the account deletion
+		if err := server.store.CancelUserDeletion(ctx, user.Username); err != nil {
+			respondError(ctx, apierror.Internal(err, "cannot restore account"))
+			return
+		}
+	}
+
+	response, err := server.newLoginResponse(user)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// userForIdentity returns pgx.ErrNoRows when the identity is unknown and cannot be linked,
+// and errEmailNotVerified when the only match is an account that never verified its email.
+func (server *Server) userForIdentity(ctx *gin.Context, claims *oidc.Claims) (db.User, error) {
+	identity, err := server.store.GetUserIdentity(ctx, db.GetUserIdentityParams{
+		Issuer:  server.oidc.Issuer(),
+		Subject: claims.Subject,
+	})
+	if err == nil {
+		return server.store.GetUser(ctx, identity.Username)
+	}
+
+	if err != pgx.ErrNoRows {
+		return db.User{}, err
+	}
+
+	// an unverified email could belong to anyone, so it is never used for linking
+	if !claims.EmailVerified || claims.Email == "" {
+		return db.User{}, pgx.ErrNoRows
+	}
+
+	user, err := server.store.GetUserByEmail(ctx, claims.Email)
+	if err != nil {
+		return db.User{}, err
+	}
+
+	// anyone can sign up with an address they do not own, linking to it would hand the owner's login to them
+	if !user.EmailVerifiedAt.Valid {
+		return db.User{}, errEmailNotVerified
+	}
+
+	_, err = server.store.CreateUserIdentity(ctx, db.CreateUserIdentityParams{
+		Issuer:   server.oidc.Issuer(),
+		Subject:  claims.Subject,
+		Username: user.Username,
+		Email:    claims.Email,
+	})
+	if err != nil {
+		return db.User{}, err
+	}
+
+	return user, nil
+}
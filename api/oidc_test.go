@@ -0,0 +1,366 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/sangketkit01/personal-financial/apitypes"
+	db "github.com/sangketkit01/personal-financial/db/sqlc"
+	"github.com/sangketkit01/personal-financial/oidc"
+	"github.com/sangketkit01/personal-financial/util"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testClientID = "personal-financial"
+	testKeyID    = "test-key"
+)
+
+// fakeGrant is what the fake issuer remembers about an authorization code.
+type fakeGrant struct {
+	challenge     string
+	nonce         string
+	subject       string
+	email         string
+	emailVerified bool
+}
+
+// fakeIssuer is an OpenID Connect provider with discovery, a key set and a token endpoint.
+// Codes are handed out with authorize, the token endpoint checks the PKCE verifier against the challenge.
+type fakeIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu     sync.Mutex
+	grants map[string]fakeGrant
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	issuer := &fakeIssuer{key: key, grants: map[string]fakeGrant{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", issuer.discovery)
+	mux.HandleFunc("GET /jwks", issuer.jwks)
+	mux.HandleFunc("POST /token", issuer.token)
+
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+
+	return issuer
+}
+
+func (issuer *fakeIssuer) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 issuer.server.URL,
+		"authorization_endpoint": issuer.server.URL + "/authorize",
+		"token_endpoint":         issuer.server.URL + "/token",
+		"jwks_uri":               issuer.server.URL + "/jwks",
+	})
+}
+
+func (issuer *fakeIssuer) jwks(w http.ResponseWriter, _ *http.Request) {
+	public := issuer.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kid": testKeyID,
+			"kty": "RSA",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+		}},
+	})
+}
+
+func (issuer *fakeIssuer) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	issuer.mu.Lock()
+	grant, ok := issuer.grants[r.PostForm.Get("code")]
+	delete(issuer.grants, r.PostForm.Get("code"))
+	issuer.mu.Unlock()
+
+	if !ok || r.PostForm.Get("client_id") != testClientID {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	if oidc.CodeChallenge(r.PostForm.Get("code_verifier")) != grant.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "code verifier does not match"})
+		return
+	}
+
+	now := time.Now()
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, &oidc.Claims{
+		Issuer:        issuer.server.URL,
+		Subject:       grant.subject,
+		Audience:      oidc.Audience{testClientID},
+		ExpiresAt:     now.Add(time.Minute).Unix(),
+		IssuedAt:      now.Unix(),
+		Nonce:         grant.nonce,
+		Email:         grant.email,
+		EmailVerified: grant.emailVerified,
+	})
+	idToken.Header["kid"] = testKeyID
+
+	signed, err := idToken.SignedString(issuer.key)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"id_token": signed, "token_type": "Bearer"})
+}
+
+// authorize plays the user logging in at the provider and returns the code for the callback.
+func (issuer *fakeIssuer) authorize(t *testing.T, authURL *url.URL, grant fakeGrant) string {
+	t.Helper()
+
+	query := authURL.Query()
+	require.Equal(t, issuer.server.URL+"/authorize", authURL.Scheme+"://"+authURL.Host+authURL.Path)
+	require.Equal(t, "S256", query.Get("code_challenge_method"))
+
+	grant.challenge = query.Get("code_challenge")
+	if grant.nonce == "" {
+		grant.nonce = query.Get("nonce")
+	}
+
+	code, err := util.RandomToken(16)
+	require.NoError(t, err)
+
+	issuer.mu.Lock()
+	issuer.grants[code] = grant
+	issuer.mu.Unlock()
+
+	return code
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// oidcTest wires a server with a fake store to a fake issuer.
+type oidcTest struct {
+	issuer *fakeIssuer
+	store  *fakeStore
+	server *Server
+}
+
+func newOIDCTest(t *testing.T) *oidcTest {
+	t.Helper()
+
+	issuer := newFakeIssuer(t)
+	store := newFakeStore()
+	server := newTestServer(t, store, util.Config{
+		OIDCIssuer:      issuer.server.URL,
+		OIDCClientID:    testClientID,
+		OIDCRedirectURL: "https://app.example.com/api/v1/oauth/callback",
+	})
+
+	return &oidcTest{issuer: issuer, store: store, server: server}
+}
+
+// login starts a login and returns the state, its cookie and the provider url the browser is sent to.
+func (test *oidcTest) login(t *testing.T) (string, *http.Cookie, *url.URL) {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/oauth/login", nil)
+	test.server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusFound, recorder.Code)
+
+	authURL, err := url.Parse(recorder.Header().Get("Location"))
+	require.NoError(t, err)
+
+	var cookie *http.Cookie
+	for _, c := range recorder.Result().Cookies() {
+		if c.Name == oidcStateCookie {
+			cookie = c
+		}
+	}
+	require.NotNil(t, cookie)
+	require.True(t, cookie.HttpOnly)
+	require.True(t, cookie.Secure)
+
+	state := authURL.Query().Get("state")
+	require.Equal(t, state, cookie.Value)
+
+	return state, cookie, authURL
+}
+
+func (test *oidcTest) callback(code string, state string, cookie *http.Cookie) *httptest.ResponseRecorder {
+	query := url.Values{}
+	query.Set("code", code)
+	query.Set("state", state)
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/oauth/callback?"+query.Encode(), nil)
+	if cookie != nil {
+		request.AddCookie(cookie)
+	}
+	test.server.router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func verifiedUser(username string, email string) db.User {
+	return db.User{
+		Username:        username,
+		Email:           email,
+		Name:            "Test User",
+		EmailVerifiedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}
+}
+
+func TestOIDCCallbackLinksVerifiedEmail(t *testing.T) {
+	test := newOIDCTest(t)
+	test.store.addUser(verifiedUser("alice", "alice@example.com"))
+
+	state, cookie, authURL := test.login(t)
+	code := test.issuer.authorize(t, authURL, fakeGrant{subject: "sub-1", email: "alice@example.com", emailVerified: true})
+
+	recorder := test.callback(code, state, cookie)
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+	var response apitypes.LoginUserRespose
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Equal(t, "alice", response.Username)
+	require.NotEmpty(t, response.AccessToken)
+
+	identity, err := test.store.GetUserIdentity(t.Context(), db.GetUserIdentityParams{Issuer: test.issuer.server.URL, Subject: "sub-1"})
+	require.NoError(t, err)
+	require.Equal(t, "alice", identity.Username)
+
+	// the next login finds the identity even when the provider no longer vouches for the email
+	state, cookie, authURL = test.login(t)
+	code = test.issuer.authorize(t, authURL, fakeGrant{subject: "sub-1"})
+
+	recorder = test.callback(code, state, cookie)
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+}
+
+func TestOIDCCallbackRefusesUnverifiedLocalEmail(t *testing.T) {
+	test := newOIDCTest(t)
+	test.store.addUser(db.User{Username: "mallory", Email: "victim@example.com"})
+
+	state, cookie, authURL := test.login(t)
+	code := test.issuer.authorize(t, authURL, fakeGrant{subject: "sub-victim", email: "victim@example.com", emailVerified: true})
+
+	recorder := test.callback(code, state, cookie)
+	require.Equal(t, http.StatusForbidden, recorder.Code, recorder.Body.String())
+
+	_, err := test.store.GetUserIdentity(t.Context(), db.GetUserIdentityParams{Issuer: test.issuer.server.URL, Subject: "sub-victim"})
+	require.Error(t, err)
+}
+
+func TestOIDCCallbackRefusesUnverifiedProviderEmail(t *testing.T) {
+	test := newOIDCTest(t)
+	test.store.addUser(verifiedUser("alice", "alice@example.com"))
+
+	state, cookie, authURL := test.login(t)
+	code := test.issuer.authorize(t, authURL, fakeGrant{subject: "sub-1", email: "alice@example.com"})
+
+	recorder := test.callback(code, state, cookie)
+	require.Equal(t, http.StatusNotFound, recorder.Code, recorder.Body.String())
+}
+
+func TestOIDCCallbackPKCEVerifierMismatch(t *testing.T) {
+	test := newOIDCTest(t)
+	test.store.addUser(verifiedUser("alice", "alice@example.com"))
+
+	state, cookie, authURL := test.login(t)
+	code := test.issuer.authorize(t, authURL, fakeGrant{subject: "sub-1", email: "alice@example.com", emailVerified: true})
+
+	// a code stolen from another login cannot be redeemed without its verifier
+	other, err := oidc.RandomString()
+	require.NoError(t, err)
+	test.store.updateState(state, func(state *db.OauthState) {
+		state.CodeVerifier = other
+	})
+
+	recorder := test.callback(code, state, cookie)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code, recorder.Body.String())
+}
+
+func TestOIDCCallbackNonceMismatch(t *testing.T) {
+	test := newOIDCTest(t)
+	test.store.addUser(verifiedUser("alice", "alice@example.com"))
+
+	state, cookie, authURL := test.login(t)
+	code := test.issuer.authorize(t, authURL, fakeGrant{subject: "sub-1", nonce: "replayed-nonce", email: "alice@example.com", emailVerified: true})
+
+	recorder := test.callback(code, state, cookie)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code, recorder.Body.String())
+}
+
+func TestOIDCCallbackExpiredState(t *testing.T) {
+	test := newOIDCTest(t)
+	test.store.addUser(verifiedUser("alice", "alice@example.com"))
+
+	state, cookie, authURL := test.login(t)
+	code := test.issuer.authorize(t, authURL, fakeGrant{subject: "sub-1", email: "alice@example.com", emailVerified: true})
+
+	test.store.updateState(state, func(state *db.OauthState) {
+		state.ExpiredAt = time.Now().Add(-time.Second)
+	})
+
+	recorder := test.callback(code, state, cookie)
+	require.Equal(t, http.StatusBadRequest, recorder.Code, recorder.Body.String())
+}
+
+func TestOIDCCallbackStateReplay(t *testing.T) {
+	test := newOIDCTest(t)
+	test.store.addUser(verifiedUser("alice", "alice@example.com"))
+
+	state, cookie, authURL := test.login(t)
+	code := test.issuer.authorize(t, authURL, fakeGrant{subject: "sub-1", email: "alice@example.com", emailVerified: true})
+
+	recorder := test.callback(code, state, cookie)
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+	code = test.issuer.authorize(t, authURL, fakeGrant{subject: "sub-1", email: "alice@example.com", emailVerified: true})
+	recorder = test.callback(code, state, cookie)
+	require.Equal(t, http.StatusBadRequest, recorder.Code, recorder.Body.String())
+}
+
+func TestOIDCCallbackStateCookie(t *testing.T) {
+	test := newOIDCTest(t)
+	test.store.addUser(verifiedUser("alice", "alice@example.com"))
+
+	state, cookie, authURL := test.login(t)
+	code := test.issuer.authorize(t, authURL, fakeGrant{subject: "sub-1", email: "alice@example.com", emailVerified: true})
+
+	// the attacker's state delivered to a browser that never started a login
+	recorder := test.callback(code, state, nil)
+	require.Equal(t, http.StatusBadRequest, recorder.Code, recorder.Body.String())
+
+	// a browser that started a different login
+	otherState, otherCookie, _ := test.login(t)
+	require.NotEqual(t, state, otherState)
+
+	recorder = test.callback(code, state, otherCookie)
+	require.Equal(t, http.StatusBadRequest, recorder.Code, recorder.Body.String())
+
+	// the state is kept for the browser it belongs to
+	recorder = test.callback(code, state, cookie)
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+}
@@ -0,0 +1,491 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/sangketkit01/personal-financial/apierror"
+)
+
+const (
+	apiVersion = "1.0.0"
+	apiPrefix  = "/api/v1"
+)
+
+// legacyDeprecatedAt is when the routes without a version prefix were deprecated, sent in the Deprecation header.
+var legacyDeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+// legacySunsetAt is when the routes without a version prefix will be removed, sent in the Sunset header.
+var legacySunsetAt = time.Date(2027, time.April, 19, 0, 0, 0, 0, time.UTC)
+
+type openAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       openAPIInfo                             `json:"info"`
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components openAPIComponents                       `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*openAPISchema        `json:"schemas"`
+	Responses       map[string]openAPIResponse       `json:"responses"`
+	SecuritySchemes map[string]openAPISecurityScheme `json:"securitySchemes"`
+}
+
+type openAPISecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme"`
+}
+
+type openAPIOperation struct {
+	OperationID string                     `json:"operationId"`
+	Summary     string                     `json:"summary,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	Tags        []string                   `json:"tags,omitempty"`
+	Deprecated  bool                       `json:"deprecated,omitempty"`
+	Parameters  []openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+	Security    []map[string][]string      `json:"security,omitempty"`
+}
+
+type openAPIParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required,omitempty"`
+	Schema   *openAPISchema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Ref         string                      `json:"$ref,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Nullable             bool                      `json:"nullable,omitempty"`
+	Enum                 []string                  `json:"enum,omitempty"`
+	Minimum              *float64                  `json:"minimum,omitempty"`
+	Maximum              *float64                  `json:"maximum,omitempty"`
+	MinLength            *int                      `json:"minLength,omitempty"`
+	MaxLength            *int                      `json:"maxLength,omitempty"`
+	MinItems             *int                      `json:"minItems,omitempty"`
+	MaxItems             *int                      `json:"maxItems,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	AdditionalProperties *openAPISchema            `json:"additionalProperties,omitempty"`
+}
+
+// knownSchemas are the types that marshal themselves, as they appear in JSON.
+var knownSchemas = map[reflect.Type]openAPISchema{
+	reflect.TypeOf(time.Time{}):          {Type: "string", Format: "date-time"},
+	reflect.TypeOf(pgtype.Timestamptz{}): {Type: "string", Format: "date-time", Nullable: true},
+	reflect.TypeOf(pgtype.Date{}):        {Type: "string", Format: "date", Nullable: true},
+	reflect.TypeOf(pgtype.Numeric{}):     {Type: "number", Nullable: true},
+	reflect.TypeOf(pgtype.Text{}):        {Type: "string", Nullable: true},
+	reflect.TypeOf(pgtype.Int8{}):        {Type: "integer", Format: "int64", Nullable: true},
+	reflect.TypeOf(json.RawMessage{}):    {},
+	reflect.TypeOf(json.Number("")):      {Type: "number"},
+}
+
+// schemaBuilder turns Go types into schemas, named structs become components that are referenced.
+type schemaBuilder struct {
+	components map[string]*openAPISchema
+	names      map[reflect.Type]string
+}
+
+func newSchemaBuilder() *schemaBuilder {
+	return &schemaBuilder{
+		components: map[string]*openAPISchema{},
+		names:      map[reflect.Type]string{},
+	}
+}
+
+func (b *schemaBuilder) schema(t reflect.Type) *openAPISchema {
+	if known, ok := knownSchemas[t]; ok {
+		return &known
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := *b.schema(t.Elem())
+		if s.Ref != "" {
+			return &s
+		}
+		s.Nullable = true
+		return &s
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &openAPISchema{Type: "string", Format: "byte"}
+		}
+		return &openAPISchema{Type: "array", Items: b.schema(t.Elem())}
+	case reflect.Map:
+		return &openAPISchema{Type: "object", AdditionalProperties: b.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			s := &openAPISchema{Type: "object"}
+			b.fields(t, s)
+			return s
+		}
+		return &openAPISchema{Ref: "#/components/schemas/" + b.component(t)}
+	case reflect.String:
+		return &openAPISchema{Type: "string"}
+	case reflect.Bool:
+		return &openAPISchema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &openAPISchema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &openAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &openAPISchema{Type: "number"}
+	}
+
+	// interfaces, e.g. the anything of gin.H
+	return &openAPISchema{}
+}
+
+// component names t, builds its schema once and returns the name.
+// A name used by a type of another package gets the package as prefix.
+func (b *schemaBuilder) component(t reflect.Type) string {
+	if name, ok := b.names[t]; ok {
+		return name
+	}
+
+	name := exportedName(t.Name())
+	if _, taken := b.components[name]; taken {
+		name = exportedName(path.Base(t.PkgPath())) + name
+	}
+
+	s := &openAPISchema{Type: "object"}
+	b.names[t] = name
+	b.components[name] = s
+	b.fields(t, s)
+
+	return name
+}
+
+func (b *schemaBuilder) fields(t reflect.Type, s *openAPISchema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				b.fields(embedded, s)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		rules := field.Tag.Get("binding")
+		if s.Properties == nil {
+			s.Properties = map[string]*openAPISchema{}
+		}
+		s.Properties[name] = withRules(b.schema(field.Type), rules)
+		if hasRule(rules, "required") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// parameters lists the fields of a struct bound by the given tag, form for the query, uri for the path.
+func (b *schemaBuilder) parameters(t reflect.Type, tag string, in string) []openAPIParameter {
+	var params []openAPIParameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		rules := field.Tag.Get("binding")
+		params = append(params, openAPIParameter{
+			Name:     name,
+			In:       in,
+			Required: in == "path" || hasRule(rules, "required"),
+			Schema:   withRules(b.schema(field.Type), rules),
+		})
+	}
+	return params
+}
+
+func exportedName(name string) string {
+	if name == "" {
+		return name
+	}
+	runes := []rune(name)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// bindingRules are the validator rules of a binding tag that apply to the field itself, not to its elements.
+func bindingRules(tag string) []string {
+	rules := strings.Split(tag, ",")
+	for i, rule := range rules {
+		if rule == "dive" {
+			return rules[:i]
+		}
+	}
+	return rules
+}
+
+func hasRule(tag string, name string) bool {
+	for _, rule := range bindingRules(tag) {
+		if rule == name {
+			return true
+		}
+	}
+	return false
+}
+
+// withRules adds the limits of the validator rules to a schema of a value, references are left alone.
+func withRules(s *openAPISchema, tag string) *openAPISchema {
+	if s.Ref != "" || tag == "" {
+		return s
+	}
+
+	for _, rule := range bindingRules(tag) {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "min", "gte", "max", "lte", "len":
+			limit, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				continue
+			}
+			lower := name == "min" || name == "gte" || name == "len"
+			upper := name == "max" || name == "lte" || name == "len"
+			setLimit(s, limit, lower, upper)
+		case "oneof":
+			s.Enum = strings.Fields(param)
+		case "email":
+			s.Format = "email"
+		case "url":
+			s.Format = "uri"
+		}
+	}
+	return s
+}
+
+func setLimit(s *openAPISchema, limit float64, lower bool, upper bool) {
+	n := int(limit)
+	switch s.Type {
+	case "integer", "number":
+		if lower {
+			s.Minimum = &limit
+		}
+		if upper {
+			s.Maximum = &limit
+		}
+	case "string":
+		if lower {
+			s.MinLength = &n
+		}
+		if upper {
+			s.MaxLength = &n
+		}
+	case "array":
+		if lower {
+			s.MinItems = &n
+		}
+		if upper {
+			s.MaxItems = &n
+		}
+	}
+}
+
+// openAPIPath turns the gin path syntax into the OpenAPI one, /financial/:id to /financial/{id}.
+func openAPIPath(ginPath string) string {
+	segments := strings.Split(ginPath, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func pathParamNames(ginPath string) []string {
+	var names []string
+	for _, segment := range strings.Split(ginPath, "/") {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			names = append(names, segment[1:])
+		}
+	}
+	return names
+}
+
+// operationID names an operation after the method of the server that handles it,
+// routes with an inline handler are named after their method and path.
+func operationID(route gin.RouteInfo) string {
+	name := strings.TrimSuffix(route.Handler[strings.LastIndex(route.Handler, ".")+1:], "-fm")
+	if !strings.HasPrefix(name, "func") {
+		return name
+	}
+
+	name = strings.ToLower(route.Method)
+	for _, segment := range strings.Split(route.Path, "/") {
+		name += exportedName(strings.TrimLeft(segment, ":*"))
+	}
+	if route.Path == "/" {
+		name += "Root"
+	}
+	return name
+}
+
+func routeKey(method string, ginPath string) string {
+	return method + " " + ginPath
+}
+
+// buildOpenAPI describes the registered routes with apiOperations.
+// It fails for a route that is not in apiOperations, so a new route cannot be added without documenting it.
+func buildOpenAPI(routes gin.RoutesInfo) (*openAPIDocument, error) {
+	operations := map[string]apiOperation{}
+	legacy := map[string]apiOperation{}
+	for _, op := range apiOperations {
+		operations[routeKey(op.method, op.path)] = op
+		for _, old := range op.legacy {
+			legacy[old] = op
+		}
+	}
+
+	builder := newSchemaBuilder()
+	doc := &openAPIDocument{
+		OpenAPI: "3.0.3",
+		Info: openAPIInfo{
+			Title:   "Personal Financial Management System",
+			Version: apiVersion,
+			Description: "Routes without the " + apiPrefix + " prefix are deprecated aliases, " +
+				"they answer with a Deprecation header, a Sunset header with the date they will be removed " +
+				"and a Link to their successor.",
+		},
+		Paths: map[string]map[string]*openAPIOperation{},
+		Components: openAPIComponents{
+			Schemas: builder.components,
+			Responses: map[string]openAPIResponse{
+				"Problem": {
+					Description: "The request failed, see code for why.",
+					Content: map[string]openAPIMediaType{
+						apierror.ContentType: {Schema: builder.schema(reflect.TypeOf(apierror.Problem{}))},
+					},
+				},
+			},
+			SecuritySchemes: map[string]openAPISecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer"},
+			},
+		},
+	}
+
+	var missing []string
+	for _, route := range routes {
+		key := routeKey(route.Method, route.Path)
+
+		op, ok := operations[key]
+		deprecated := false
+		if !ok {
+			op, ok = legacy[key]
+			deprecated = true
+		}
+		if !ok {
+			missing = append(missing, key)
+			continue
+		}
+
+		operation := op.describe(builder, route.Path)
+		operation.OperationID = operationID(route)
+
+		if deprecated {
+			operation.OperationID += "Deprecated"
+			operation.Deprecated = true
+			operation.Description = fmt.Sprintf("Use %s %s instead.", op.method, openAPIPath(op.path))
+		}
+
+		specPath := openAPIPath(route.Path)
+		if doc.Paths[specPath] == nil {
+			doc.Paths[specPath] = map[string]*openAPIOperation{}
+		}
+		doc.Paths[specPath][strings.ToLower(route.Method)] = operation
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("routes missing from the OpenAPI operations: %s", strings.Join(missing, ", "))
+	}
+
+	return doc, nil
+}
+
+// OpenAPI serves the OpenAPI 3 document of the registered routes.
+func (server *Server) OpenAPI(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, server.openAPI)
+}
+
+// deprecatedMiddleware marks the response of a route without the version prefix as deprecated,
+// with the date it will be removed and a link to the route that replaces it.
+func deprecatedMiddleware() gin.HandlerFunc {
+	successors := map[string]string{}
+	for _, op := range apiOperations {
+		for _, old := range op.legacy {
+			successors[old] = op.path
+		}
+	}
+
+	deprecation := fmt.Sprintf("@%d", legacyDeprecatedAt.Unix())
+	sunset := legacySunsetAt.Format(http.TimeFormat)
+
+	return func(ctx *gin.Context) {
+		ctx.Header("Deprecation", deprecation)
+		ctx.Header("Sunset", sunset)
+
+		if successor, ok := successors[routeKey(ctx.Request.Method, ctx.FullPath())]; ok {
+			for _, param := range ctx.Params {
+				successor = strings.Replace(successor, ":"+param.Key, param.Value, 1)
+			}
+			ctx.Header("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", openAPIPath(successor)))
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,286 @@
+package api
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/sangketkit01/personal-financial/apitypes"
+)
+
+// apiOperation documents a route for the OpenAPI document.
+// legacy lists the routes without version prefix, as "METHOD /path", that do the same and are deprecated.
+type apiOperation struct {
+	method   string
+	path     string
+	tag      string
+	summary  string
+	public   bool
+	uri      any // struct bound from the path, gives the path params their types
+	query    any // struct bound from the query
+	body     any
+	response any
+	status   int    // 200 when not set
+	text     string // media type of a response that is not JSON
+	legacy   []string
+}
+
+type overrideQuery struct {
+	Override bool `form:"override"`
+}
+
+type oidcCallbackQuery struct {
+	Code  string `form:"code" binding:"required"`
+	State string `form:"state" binding:"required"`
+	Error string `form:"error"`
+}
+
+// describe builds the operation of a route, routePath is the v1 path or one of the legacy paths.
+func (op apiOperation) describe(builder *schemaBuilder, routePath string) *openAPIOperation {
+	operation := &openAPIOperation{
+		Summary: op.summary,
+		Tags:    []string{op.tag},
+	}
+
+	var uriParams []openAPIParameter
+	if op.uri != nil {
+		uriParams = builder.parameters(reflect.TypeOf(op.uri), "uri", "path")
+	}
+	for _, name := range pathParamNames(routePath) {
+		param := openAPIParameter{Name: name, In: "path", Required: true, Schema: &openAPISchema{Type: "string"}}
+		if name == "id" || strings.HasSuffix(name, "_id") {
+			param.Schema = &openAPISchema{Type: "integer", Format: "int64"}
+		}
+		for _, uriParam := range uriParams {
+			if uriParam.Name == name {
+				param = uriParam
+			}
+		}
+		operation.Parameters = append(operation.Parameters, param)
+	}
+
+	if op.query != nil {
+		operation.Parameters = append(operation.Parameters, builder.parameters(reflect.TypeOf(op.query), "form", "query")...)
+	}
+
+	if op.body != nil {
+		operation.RequestBody = &openAPIRequestBody{
+			Required: true,
+			Content: map[string]openAPIMediaType{
+				"application/json": {Schema: builder.schema(reflect.TypeOf(op.body))},
+			},
+		}
+	}
+
+	status := op.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	response := openAPIResponse{Description: http.StatusText(status)}
+	switch {
+	case op.text != "":
+		response.Content = map[string]openAPIMediaType{op.text: {Schema: &openAPISchema{Type: "string"}}}
+	case status == http.StatusFound:
+	case op.response != nil:
+		response.Content = map[string]openAPIMediaType{"application/json": {Schema: builder.schema(reflect.TypeOf(op.response))}}
+	default:
+		response.Content = map[string]openAPIMediaType{"application/json": {Schema: &openAPISchema{Type: "object"}}}
+	}
+	operation.Responses = map[string]openAPIResponse{
+		strconv.Itoa(status): response,
+		"default":            {Ref: "#/components/responses/Problem"},
+	}
+
+	if !op.public {
+		operation.Security = []map[string][]string{{"bearerAuth": {}}}
+	}
+
+	return operation
+}
+
+var apiOperations = []apiOperation{
+	{method: http.MethodGet, path: "/", tag: "system", summary: "Welcome message.", public: true},
+	{method: http.MethodGet, path: "/healthz", tag: "system", summary: "Liveness.", public: true},
+	{method: http.MethodGet, path: "/readyz", tag: "system", summary: "Readiness, 503 until the database is reachable and migrated.", public: true, response: apitypes.ReadyResponse{}},
+	{method: http.MethodGet, path: "/metrics", tag: "system", summary: "Prometheus metrics.", public: true, text: "text/plain"},
+	{method: http.MethodGet, path: apiPrefix + "/openapi.json", tag: "system", summary: "This document.", public: true},
+
+	{method: http.MethodPost, path: apiPrefix + "/users", tag: "auth", summary: "Register a new user.", public: true,
+		body: apitypes.CreateUserRequest{}, response: apitypes.CreateUserResponse{}, legacy: []string{"POST /create-user"}},
+	{method: http.MethodPost, path: apiPrefix + "/sessions", tag: "auth", summary: "Log in and get an access token.", public: true,
+		body: apitypes.LoginUserRequest{}, response: apitypes.LoginUserRespose{}, legacy: []string{"POST /login-user"}},
+	{method: http.MethodPost, path: apiPrefix + "/unlock-requests", tag: "auth", summary: "Send an unlock link to a locked account.", public: true,
+		body: apitypes.RequestUnlockRequest{}, response: apitypes.MessageResponse{}, legacy: []string{"POST /request-unlock"}},
+	{method: http.MethodPost, path: apiPrefix + "/unlocks", tag: "auth", summary: "Unlock an account with the token of an unlock link.", public: true,
+		body: apitypes.UnlockAccountRequest{}, response: apitypes.MessageResponse{}, legacy: []string{"POST /unlock-account"}},
+	{method: http.MethodGet, path: apiPrefix + "/oauth/login", tag: "auth", summary: "Redirect to the identity provider.", public: true,
+		status: http.StatusFound, legacy: []string{"GET /oauth/login"}},
+	{method: http.MethodGet, path: apiPrefix + "/oauth/callback", tag: "auth", summary: "Log in with the answer of the identity provider.", public: true,
+		query: oidcCallbackQuery{}, response: apitypes.LoginUserRespose{}, legacy: []string{"GET /oauth/callback"}},
+
+	{method: http.MethodGet, path: apiPrefix + "/me", tag: "account", summary: "Profile of the user.",
+		response: apitypes.ProfileResponse{}, legacy: []string{"GET /me"}},
+	{method: http.MethodPatch, path: apiPrefix + "/me", tag: "account", summary: "Update the profile.",
+		body: apitypes.UpdateProfileRequest{}, response: apitypes.UpdateProfileResponse{}, legacy: []string{"PATCH /me"}},
+	{method: http.MethodDelete, path: apiPrefix + "/me", tag: "account", summary: "Schedule the account for deletion.",
+		body: apitypes.DeleteAccountRequest{}, response: apitypes.DeleteAccountResponse{}, legacy: []string{"DELETE /me"}},
+	{method: http.MethodPost, path: apiPrefix + "/me/verify", tag: "account", summary: "Verify a changed email or phone.",
+		body: apitypes.VerifyContactRequest{}, response: apitypes.ProfileResponse{}, legacy: []string{"POST /me/verify"}},
+	{method: http.MethodPut, path: apiPrefix + "/me/password", tag: "account", summary: "Change the password.",
+		body: apitypes.UpdateUserPasswordRequest{}, response: apitypes.MessageResponse{}, legacy: []string{"PUT /update-password"}},
+	{method: http.MethodPost, path: apiPrefix + "/me/tokens", tag: "account", summary: "Create a personal access token.",
+		body: apitypes.CreatePersonalAccessTokenRequest{}, response: apitypes.CreatePersonalAccessTokenResponse{}, legacy: []string{"POST /tokens"}},
+	{method: http.MethodGet, path: apiPrefix + "/me/tokens", tag: "account", summary: "List personal access tokens.",
+		response: []apitypes.PersonalAccessTokenResponse{}, legacy: []string{"GET /tokens"}},
+	{method: http.MethodDelete, path: apiPrefix + "/me/tokens/:id", tag: "account", summary: "Revoke a personal access token.",
+		response: apitypes.PersonalAccessTokenResponse{}, legacy: []string{"DELETE /tokens/:id"}},
+
+	{method: http.MethodPost, path: apiPrefix + "/transactions", tag: "transactions", summary: "Add a financial record.",
+		body: apitypes.NewFinancialRequest{}, response: apitypes.NewFinancialResponse{}, legacy: []string{"POST /new-financial"}},
+	{method: http.MethodGet, path: apiPrefix + "/transactions", tag: "transactions", summary: "List financial records.",
+		response: []apitypes.FinancialEntry{}, legacy: []string{"GET /my-financial"}},
+	{method: http.MethodGet, path: apiPrefix + "/transactions/:id", tag: "transactions", summary: "Get a financial record.",
+		response: apitypes.FinancialEntry{}, legacy: []string{"GET /financial/get/:id"}},
+	{method: http.MethodGet, path: apiPrefix + "/transactions/:id/history", tag: "transactions", summary: "Audit history of a financial record.",
+		response: []apitypes.AuditEventResponse{}, legacy: []string{"GET /financial/get/:id/history"}},
+	{method: http.MethodPut, path: apiPrefix + "/transactions/:id", tag: "transactions", summary: "Update a financial record.",
+		query: overrideQuery{}, body: apitypes.UpdateFinancialRequest{}, response: apitypes.UpdateFinancialResponse{}, legacy: []string{"PUT /financial/update/:id"}},
+	{method: http.MethodDelete, path: apiPrefix + "/transactions/:id", tag: "transactions", summary: "Move a financial record to the trash.",
+		response: apitypes.DeleteFinancialResponse{}, legacy: []string{"DELETE /financial/delete/:id"}},
+	{method: http.MethodPost, path: apiPrefix + "/transactions/:id/restore", tag: "transactions", summary: "Restore a financial record from the trash.",
+		response: apitypes.RestoreFinancialResponse{}, legacy: []string{"POST /financial/restore/:id"}},
+	{method: http.MethodGet, path: apiPrefix + "/trash", tag: "transactions", summary: "Deleted financial records.",
+		response: apitypes.TrashResponse{}, legacy: []string{"GET /trash"}},
+
+	{method: http.MethodGet, path: apiPrefix + "/forecast", tag: "reports", summary: "Forecast of the coming months.",
+		query: apitypes.ForecastRequest{}, response: apitypes.ForecastResponse{}, legacy: []string{"GET /forecast"}},
+	{method: http.MethodGet, path: apiPrefix + "/insights/anomalies", tag: "reports", summary: "Unusual financial records.",
+		response: []apitypes.AnomalyEntry{}, legacy: []string{"GET /insights/anomalies"}},
+	{method: http.MethodGet, path: apiPrefix + "/reports/compare", tag: "reports", summary: "Compare two periods by category.",
+		query: apitypes.CompareQuery{}, response: apitypes.CompareResponse{}, legacy: []string{"GET /reports/compare"}},
+	{method: http.MethodGet, path: apiPrefix + "/reports/trend", tag: "reports", summary: "Monthly totals per category.",
+		query: apitypes.TrendQuery{}, response: apitypes.TrendResponse{}, legacy: []string{"GET /reports/trend"}},
+
+	{method: http.MethodGet, path: apiPrefix + "/summary", tag: "summary", summary: "Income and expense series by bucket.",
+		query: apitypes.SummaryQuery{}, response: apitypes.SummaryResponse{}, legacy: []string{"GET /summary"}},
+	{method: http.MethodGet, path: apiPrefix + "/summary/current-month", tag: "summary", summary: "Summary of the current month.",
+		response: apitypes.FinancialSummary{}, legacy: []string{"GET /summary/current-month"}},
+	{method: http.MethodGet, path: apiPrefix + "/summary/current-year", tag: "summary", summary: "Summary of the current fiscal year.",
+		response: apitypes.FinancialSummary{}, legacy: []string{"GET /summary/current-year"}},
+	{method: http.MethodGet, path: apiPrefix + "/summary/years", tag: "summary", summary: "Summary of every year.",
+		response: []apitypes.YearSummary{}, legacy: []string{"GET /summary/each-year"}},
+	{method: http.MethodGet, path: apiPrefix + "/summary/:year", tag: "summary", summary: "Summary of a year.",
+		uri: apitypes.YearRequest{}, response: apitypes.FinancialSummary{}, legacy: []string{"GET /summary/summary/month/year"}},
+	{method: http.MethodGet, path: apiPrefix + "/summary/:year/types", tag: "summary", summary: "Summary of a year by type.",
+		uri: apitypes.YearRequest{}, response: []apitypes.TypeSummary{}, legacy: []string{"GET /summary/type/year"}},
+	{method: http.MethodGet, path: apiPrefix + "/summary/:year/:month", tag: "summary", summary: "Summary of a month.",
+		uri: apitypes.YearMonthRequest{}, response: apitypes.FinancialSummary{}, legacy: []string{"GET /summary/month"}},
+	{method: http.MethodGet, path: apiPrefix + "/summary/:year/:month/types", tag: "summary", summary: "Summary of a month by type.",
+		uri: apitypes.YearMonthRequest{}, response: []apitypes.TypeSummary{}, legacy: []string{"GET /summary/type/month-year"}},
+
+	{method: http.MethodGet, path: apiPrefix + "/periods", tag: "periods", summary: "Closed months with their drift.",
+		response: []apitypes.ClosedPeriodResponse{}, legacy: []string{"GET /periods"}},
+	{method: http.MethodPost, path: apiPrefix + "/periods", tag: "periods", summary: "Close a month.",
+		body: apitypes.PeriodRequest{}, response: apitypes.PeriodClosing{}, legacy: []string{"POST /periods/close"}},
+	{method: http.MethodDelete, path: apiPrefix + "/periods/:year/:month", tag: "periods", summary: "Reopen a closed month.",
+		uri: apitypes.PeriodRequest{}, response: apitypes.PeriodClosing{}, legacy: []string{"POST /periods/reopen"}},
+
+	{method: http.MethodPost, path: apiPrefix + "/reconciliations", tag: "reconciliations", summary: "Start a reconciliation against a statement.",
+		body: apitypes.CreateReconciliationRequest{}, response: apitypes.ReconciliationResponse{}, legacy: []string{"POST /reconciliations"}},
+	{method: http.MethodGet, path: apiPrefix + "/reconciliations", tag: "reconciliations", summary: "List reconciliations.",
+		response: []apitypes.ReconciliationResponse{}, legacy: []string{"GET /reconciliations"}},
+	{method: http.MethodGet, path: apiPrefix + "/reconciliations/:id", tag: "reconciliations", summary: "Get a reconciliation with its candidates.",
+		response: apitypes.ReconciliationDetailResponse{}, legacy: []string{"GET /reconciliations/:id"}},
+	{method: http.MethodPost, path: apiPrefix + "/reconciliations/:id/clear", tag: "reconciliations", summary: "Clear records found on the statement.",
+		body: apitypes.ClearFinancialsRequest{}, response: apitypes.ClearFinancialsResponse{}, legacy: []string{"POST /reconciliations/:id/clear"}},
+	{method: http.MethodPost, path: apiPrefix + "/reconciliations/:id/unclear", tag: "reconciliations", summary: "Unclear records.",
+		body: apitypes.ClearFinancialsRequest{}, response: apitypes.ClearFinancialsResponse{}, legacy: []string{"POST /reconciliations/:id/unclear"}},
+	{method: http.MethodPost, path: apiPrefix + "/reconciliations/:id/finish", tag: "reconciliations", summary: "Lock a balanced reconciliation.",
+		response: apitypes.ReconciliationResponse{}, legacy: []string{"POST /reconciliations/:id/finish"}},
+	{method: http.MethodDelete, path: apiPrefix + "/reconciliations/:id", tag: "reconciliations", summary: "Cancel a reconciliation.",
+		response: apitypes.MessageResponse{}, legacy: []string{"DELETE /reconciliations/:id"}},
+
+	{method: http.MethodPost, path: apiPrefix + "/households", tag: "households", summary: "Create a household.",
+		body: apitypes.CreateHouseholdRequest{}, response: apitypes.Household{}, legacy: []string{"POST /households"}},
+	{method: http.MethodGet, path: apiPrefix + "/households", tag: "households", summary: "Households of the user.",
+		response: []apitypes.MyHousehold{}, legacy: []string{"GET /households"}},
+	{method: http.MethodPost, path: apiPrefix + "/households/join", tag: "households", summary: "Join a household with an invitation.",
+		body: apitypes.JoinHouseholdRequest{}, response: apitypes.HouseholdMember{}, legacy: []string{"POST /households/join"}},
+	{method: http.MethodGet, path: apiPrefix + "/households/:household_id", tag: "households", summary: "Get a household with its members.",
+		response: apitypes.HouseholdResponse{}, legacy: []string{"GET /households/:household_id"}},
+	{method: http.MethodPost, path: apiPrefix + "/households/:household_id/invitations", tag: "households", summary: "Invite a member, owners only.",
+		body: apitypes.InviteHouseholdMemberRequest{}, response: apitypes.InvitationResponse{}, legacy: []string{"POST /households/:household_id/invitations"}},
+	{method: http.MethodPut, path: apiPrefix + "/households/:household_id/members/:username", tag: "households", summary: "Change the role of a member, owners only.",
+		body: apitypes.UpdateHouseholdMemberRequest{}, response: apitypes.HouseholdMember{}, legacy: []string{"PUT /households/:household_id/members/:username"}},
+	{method: http.MethodDelete, path: apiPrefix + "/households/:household_id/members/:username", tag: "households", summary: "Remove a member, or leave.",
+		response: apitypes.MessageResponse{}, legacy: []string{"DELETE /households/:household_id/members/:username"}},
+	{method: http.MethodGet, path: apiPrefix + "/households/:household_id/transactions", tag: "households", summary: "Financial records of the household.",
+		response: []apitypes.HouseholdFinancial{}, legacy: []string{"GET /households/:household_id/financials"}},
+	{method: http.MethodGet, path: apiPrefix + "/households/:household_id/summary", tag: "households", summary: "Summary of a month by member.",
+		query: apitypes.MonthYearQuery{}, response: apitypes.HouseholdSummaryResponse{}, legacy: []string{"GET /households/:household_id/summary"}},
+	{method: http.MethodPost, path: apiPrefix + "/households/:household_id/budgets", tag: "households", summary: "Set the budget of the current month, editors only.",
+		body: apitypes.BudgetRequest{}, response: apitypes.Budget{}, legacy: []string{"POST /households/:household_id/budget"}},
+	{method: http.MethodGet, path: apiPrefix + "/households/:household_id/budgets", tag: "households", summary: "Budget of a month.",
+		query: apitypes.MonthYearQuery{}, response: apitypes.Budget{}, legacy: []string{"GET /households/:household_id/budget"}},
+
+	{method: http.MethodPost, path: apiPrefix + "/contacts", tag: "splits", summary: "Add a contact to split with.",
+		body: apitypes.CreateContactRequest{}, response: apitypes.Contact{}, legacy: []string{"POST /contacts"}},
+	{method: http.MethodGet, path: apiPrefix + "/contacts", tag: "splits", summary: "List contacts.",
+		response: []apitypes.Contact{}, legacy: []string{"GET /contacts"}},
+	{method: http.MethodPost, path: apiPrefix + "/splits/expenses", tag: "splits", summary: "Split an expense.",
+		body: apitypes.CreateSharedExpenseRequest{}, response: apitypes.SharedExpenseResult{}, legacy: []string{"POST /splits/expenses"}},
+	{method: http.MethodGet, path: apiPrefix + "/splits/expenses", tag: "splits", summary: "List shared expenses.",
+		response: []apitypes.SharedExpenseResponse{}, legacy: []string{"GET /splits/expenses"}},
+	{method: http.MethodGet, path: apiPrefix + "/splits/balances", tag: "splits", summary: "Who owes whom.",
+		response: apitypes.SplitBalancesResponse{}, legacy: []string{"GET /splits/balances"}},
+	{method: http.MethodPost, path: apiPrefix + "/splits/settlements", tag: "splits", summary: "Record a settlement.",
+		body: apitypes.CreateSettlementRequest{}, response: apitypes.Settlement{}, legacy: []string{"POST /splits/settlements"}},
+	{method: http.MethodGet, path: apiPrefix + "/splits/settlements", tag: "splits", summary: "List settlements, pending ones wait for the other side.",
+		response: []apitypes.Settlement{}},
+	{method: http.MethodPost, path: apiPrefix + "/splits/settlements/:id/confirm", tag: "splits", summary: "Confirm a settlement another user recorded.",
+		response: apitypes.Settlement{}},
+	{method: http.MethodPost, path: apiPrefix + "/splits/settlements/:id/reject", tag: "splits", summary: "Reject a settlement another user recorded.",
+		response: apitypes.Settlement{}},
+
+	{method: http.MethodPost, path: apiPrefix + "/budgets", tag: "budgets", summary: "Set the budget of the current month.",
+		body: apitypes.BudgetRequest{}, response: apitypes.Budget{}, legacy: []string{"POST /budget/"}},
+	{method: http.MethodGet, path: apiPrefix + "/budgets", tag: "budgets", summary: "Every budget of the user.",
+		response: []apitypes.Budget{}, legacy: []string{"GET /budget/history"}},
+	{method: http.MethodGet, path: apiPrefix + "/budgets/current", tag: "budgets", summary: "Budget of the current month.",
+		response: apitypes.Budget{}, legacy: []string{"GET /budget/"}},
+	{method: http.MethodPut, path: apiPrefix + "/budgets/current", tag: "budgets", summary: "Change the budget of the current month.",
+		body: apitypes.BudgetRequest{}, response: apitypes.Budget{}, legacy: []string{"PUT /budget/"}},
+	{method: http.MethodGet, path: apiPrefix + "/budgets/current/usage", tag: "budgets", summary: "How much of the budget of the current month is spent.",
+		response: apitypes.CheckBudgetUsageResponse{}, legacy: []string{"GET /budget/check"}},
+	{method: http.MethodGet, path: apiPrefix + "/budgets/:year", tag: "budgets", summary: "Budget of a year.",
+		uri: apitypes.BudgetHistoryRequest{}, response: apitypes.Budget{}, legacy: []string{"GET /budget/history/year"}},
+	{method: http.MethodGet, path: apiPrefix + "/budgets/:year/:month", tag: "budgets", summary: "Budget of a month.",
+		uri: apitypes.PeriodRequest{}, response: apitypes.Budget{}},
+
+	{method: http.MethodGet, path: apiPrefix + "/admin/financial-types", tag: "admin", summary: "List financial types.",
+		response: []apitypes.FinancialType{}, legacy: []string{"GET /admin/financial-types"}},
+	{method: http.MethodPost, path: apiPrefix + "/admin/financial-types", tag: "admin", summary: "Add a financial type.",
+		body: apitypes.FinancialTypeRequest{}, response: apitypes.FinancialType{}, legacy: []string{"POST /admin/financial-types"}},
+	{method: http.MethodPut, path: apiPrefix + "/admin/financial-types/:id", tag: "admin", summary: "Rename a financial type.",
+		body: apitypes.FinancialTypeRequest{}, response: apitypes.FinancialType{}, legacy: []string{"PUT /admin/financial-types/:id"}},
+	{method: http.MethodDelete, path: apiPrefix + "/admin/financial-types/:id", tag: "admin", summary: "Delete an unused financial type.",
+		response: apitypes.FinancialType{}, legacy: []string{"DELETE /admin/financial-types/:id"}},
+	{method: http.MethodGet, path: apiPrefix + "/admin/users", tag: "admin", summary: "Search users.",
+		query: apitypes.SearchUsersRequest{}, response: []apitypes.AdminUserResponse{}, legacy: []string{"GET /admin/users"}},
+	{method: http.MethodGet, path: apiPrefix + "/admin/users/:username", tag: "admin", summary: "Get a user.",
+		response: apitypes.AdminUserResponse{}, legacy: []string{"GET /admin/users/:username"}},
+	{method: http.MethodPut, path: apiPrefix + "/admin/users/:username/role", tag: "admin", summary: "Set the role of a user.",
+		body: apitypes.SetUserRoleRequest{}, response: apitypes.AdminUserResponse{}, legacy: []string{"PUT /admin/users/:username/role"}},
+	{method: http.MethodPost, path: apiPrefix + "/admin/users/:username/disable", tag: "admin", summary: "Disable a user.",
+		response: apitypes.AdminUserResponse{}, legacy: []string{"POST /admin/users/:username/disable"}},
+	{method: http.MethodPost, path: apiPrefix + "/admin/users/:username/enable", tag: "admin", summary: "Enable a user.",
+		response: apitypes.AdminUserResponse{}, legacy: []string{"POST /admin/users/:username/enable"}},
+	{method: http.MethodPost, path: apiPrefix + "/admin/users/:username/revoke-sessions", tag: "admin", summary: "Revoke every token of a user.",
+		response: apitypes.AdminUserResponse{}, legacy: []string{"POST /admin/users/:username/revoke-sessions"}},
+	{method: http.MethodGet, path: apiPrefix + "/admin/stats", tag: "admin", summary: "System statistics.",
+		response: apitypes.SystemStats{}, legacy: []string{"GET /admin/stats"}},
+	{method: http.MethodGet, path: apiPrefix + "/admin/audits", tag: "admin", summary: "Admin audit log.",
+		query: apitypes.ListAdminAuditsRequest{}, response: []apitypes.AdminAudit{}, legacy: []string{"GET /admin/audits"}},
+}
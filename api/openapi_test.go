@@ -0,0 +1,141 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sangketkit01/personal-financial/util"
+	"github.com/stretchr/testify/require"
+)
+
+// newRoutesTestServer builds a server with every optional route registered.
+func newRoutesTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	issuer := newFakeIssuer(t)
+	return newTestServer(t, newFakeStore(), util.Config{
+		OIDCIssuer:      issuer.server.URL,
+		OIDCClientID:    testClientID,
+		OIDCRedirectURL: "http://localhost/api/v1/oauth/callback",
+	})
+}
+
+// examplePath fills the params of a gin path so it can be requested.
+func examplePath(ginPath string) string {
+	segments := strings.Split(ginPath, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "1"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func TestOpenAPIOperationsMatchRoutes(t *testing.T) {
+	server := newRoutesTestServer(t)
+
+	registered := map[string]bool{}
+	for _, route := range server.router.Routes() {
+		registered[routeKey(route.Method, route.Path)] = true
+	}
+
+	documented := map[string]bool{}
+	for _, op := range apiOperations {
+		key := routeKey(op.method, op.path)
+		require.False(t, documented[key], "%s is listed twice", key)
+		documented[key] = true
+		require.True(t, registered[key], "%s is documented but not registered", key)
+
+		for _, old := range op.legacy {
+			require.False(t, documented[old], "%s is listed twice", old)
+			documented[old] = true
+			require.True(t, registered[old], "legacy route %s is documented but not registered", old)
+			require.False(t, strings.HasPrefix(old, op.method+" "+apiPrefix), "legacy route %s has the version prefix", old)
+		}
+	}
+
+	for _, route := range server.router.Routes() {
+		key := routeKey(route.Method, route.Path)
+		require.True(t, documented[key], "%s is registered but not documented", key)
+
+		if strings.HasPrefix(route.Path, apiPrefix+"/") {
+			found := false
+			for _, op := range apiOperations {
+				found = found || (op.method == route.Method && op.path == route.Path)
+			}
+			require.True(t, found, "%s is not a v1 operation", key)
+		}
+	}
+
+	operations := 0
+	for specPath, methods := range server.openAPI.Paths {
+		for method, operation := range methods {
+			operations++
+
+			found := false
+			for _, route := range server.router.Routes() {
+				found = found || (strings.ToLower(route.Method) == method && openAPIPath(route.Path) == specPath)
+			}
+			require.True(t, found, "%s %s is in the document but not registered", method, specPath)
+			require.NotEmpty(t, operation.OperationID)
+			require.Equal(t, !strings.HasPrefix(specPath, apiPrefix) && documentedLegacy(method, specPath), operation.Deprecated,
+				"%s %s deprecated", method, specPath)
+		}
+	}
+	require.Equal(t, len(server.router.Routes()), operations)
+}
+
+// documentedLegacy tells whether the spec operation is one of the legacy aliases.
+func documentedLegacy(method string, specPath string) bool {
+	for _, op := range apiOperations {
+		for _, old := range op.legacy {
+			oldMethod, oldPath, _ := strings.Cut(old, " ")
+			if strings.ToLower(oldMethod) == method && openAPIPath(oldPath) == specPath {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestLegacyRoutesAreDeprecated(t *testing.T) {
+	server := newRoutesTestServer(t)
+
+	sunset, err := http.ParseTime(legacySunsetAt.Format(http.TimeFormat))
+	require.NoError(t, err)
+	require.True(t, sunset.After(legacyDeprecatedAt))
+
+	for _, op := range apiOperations {
+		for _, old := range op.legacy {
+			method, ginPath, _ := strings.Cut(old, " ")
+
+			t.Run(old, func(t *testing.T) {
+				// no credentials and an empty body, the request is refused before it reaches the store
+				recorder := httptest.NewRecorder()
+				request := httptest.NewRequest(method, examplePath(ginPath), strings.NewReader("{}"))
+				request.Header.Set("Content-Type", "application/json")
+				server.router.ServeHTTP(recorder, request)
+
+				require.Equal(t, "@"+strconv.FormatInt(legacyDeprecatedAt.Unix(), 10), recorder.Header().Get("Deprecation"))
+
+				header, err := http.ParseTime(recorder.Header().Get("Sunset"))
+				require.NoError(t, err)
+				require.True(t, header.Equal(legacySunsetAt))
+
+				link := recorder.Header().Get("Link")
+				require.True(t, strings.HasPrefix(link, "<"+apiPrefix+"/"), link)
+				require.True(t, strings.HasSuffix(link, ">; rel=\"successor-version\""), link)
+			})
+		}
+	}
+
+	// the versioned routes are not deprecated
+	recorder := httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, apiPrefix+"/openapi.json", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Empty(t, recorder.Header().Get("Deprecation"))
+	require.Empty(t, recorder.Header().Get("Sunset"))
+}
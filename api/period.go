@@ -0,0 +1,120 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/sangketkit01/personal-financial/apierror"
+	"github.com/sangketkit01/personal-financial/apitypes"
+	db "github.com/sangketkit01/personal-financial/db/sqlc"
+)
+
+// bindPeriod reads the month and year of a request from the path in the /api/v1 routes,
+// and from the JSON body in the legacy routes.
+func bindPeriod(ctx *gin.Context, req any) error {
+	if ctx.Param("year") != "" {
+		return ctx.ShouldBindUri(req)
+	}
+	return ctx.ShouldBindJSON(req)
+}
+
+// ClosePeriod locks a month against changes and stores a snapshot of its totals.
+func (server *Server) ClosePeriod(ctx *gin.Context) {
+	user := ctx.MustGet("user").(db.User)
+
+	var req apitypes.PeriodRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	year, month := currentPeriod(user)
+	if req.Year > int32(year) || (req.Year == int32(year) && req.Month > int32(month)) {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "cannot close a month in the future."))
+		return
+	}
+
+	closing, err := server.store.ClosePeriodTx(ctx, db.ClosePeriodTxParams{
+		UserID: user.Username,
+		Month:  req.Month,
+		Year:   req.Year,
+		Audit:  auditInfo(ctx, user),
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			respondError(ctx, apierror.New(http.StatusBadRequest, "this month is already closed."))
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, "cannot close the month."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPeriodClosing(closing))
+}
+
+func (server *Server) ReopenPeriod(ctx *gin.Context) {
+	user := ctx.MustGet("user").(db.User)
+
+	var req apitypes.PeriodRequest
+	if err := bindPeriod(ctx, &req); err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	closing, err := server.store.ReopenPeriodTx(ctx, db.ReopenPeriodParams{
+		UserID: user.Username,
+		Month:  req.Month,
+		Year:   req.Year,
+	}, auditInfo(ctx, user))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			respondError(ctx, apierror.New(http.StatusNotFound, "this month is not closed."))
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, "cannot reopen the month."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPeriodClosing(closing))
+}
+
+// ListClosedPeriods compares every closed month with its closing snapshot.
+// A drift other than zero means the month changed after it was closed, e.g. through an override.
+func (server *Server) ListClosedPeriods(ctx *gin.Context) {
+	user := ctx.MustGet("user").(db.User)
+
+	closings, err := server.store.ListPeriodClosings(ctx, user.Username)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get closed months."))
+		return
+	}
+
+	response := make([]apitypes.ClosedPeriodResponse, len(closings))
+	for i, closing := range closings {
+		response[i] = apitypes.ClosedPeriodResponse{
+			PeriodClosing: newPeriodClosing(db.PeriodClosing{
+				ID:           closing.ID,
+				UserID:       closing.UserID,
+				Month:        closing.Month,
+				Year:         closing.Year,
+				TotalIncome:  closing.TotalIncome,
+				TotalExpense: closing.TotalExpense,
+				Status:       closing.Status,
+				ClosedAt:     closing.ClosedAt,
+				ReopenedAt:   closing.ReopenedAt,
+			}),
+			CurrentIncome:  closing.CurrentIncome,
+			CurrentExpense: closing.CurrentExpense,
+			IncomeDrift:    closing.CurrentIncome - closing.TotalIncome,
+			ExpenseDrift:   closing.CurrentExpense - closing.TotalExpense,
+		}
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,309 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/sangketkit01/personal-financial/apierror"
+	"github.com/sangketkit01/personal-financial/apitypes"
+	db "github.com/sangketkit01/personal-financial/db/sqlc"
+	"github.com/sangketkit01/personal-financial/util"
+)
+
+func newProfileResponse(user db.User) apitypes.ProfileResponse {
+	response := apitypes.ProfileResponse{
+		Username:         user.Username,
+		Name:             user.Name,
+		Email:            user.Email,
+		EmailVerified:    user.EmailVerifiedAt.Valid,
+		Phone:            user.Phone,
+		TimeZone:         user.TimeZone,
+		Locale:           user.Locale,
+		PeriodStartDay:   user.PeriodStartDay,
+		FiscalStartMonth: user.FiscalYearStartMonth,
+		CreatedAt:        user.CreatedAt,
+		UpdatedAt:        user.UpdatedAt,
+	}
+
+	if user.DeletedAt.Valid {
+		response.DeletionScheduled = &user.DeletedAt.Time
+	}
+
+	return response
+}
+
+func (server *Server) GetProfile(ctx *gin.Context) {
+	u, exists := ctx.Get("user")
+	if !exists {
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "unauthorized"))
+		return
+	}
+	user, ok := u.(db.User)
+	if !ok {
+		respondError(ctx, apierror.Internal(nil, "invalid user type"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newProfileResponse(user))
+}
+
+// UpdateProfile changes the name, time zone, locale and periods right away.
+// A new email or phone only takes effect after the code sent to it is confirmed at /me/verify.
+func (server *Server) UpdateProfile(ctx *gin.Context) {
+	u, exists := ctx.Get("user")
+	if !exists {
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "unauthorized"))
+		return
+	}
+	user, ok := u.(db.User)
+	if !ok {
+		respondError(ctx, apierror.Internal(nil, "invalid user type"))
+		return
+	}
+
+	var req apitypes.UpdateProfileRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	arg := db.UpdateProfileTxParams{Username: user.Username}
+
+	if req.Name != nil {
+		arg.Name = strings.TrimSpace(*req.Name)
+		if arg.Name == "" {
+			respondError(ctx, apierror.New(http.StatusBadRequest, "name cannot be empty"))
+			return
+		}
+	}
+
+	if req.TimeZone != nil || req.Locale != nil || req.PeriodStartDay != nil || req.FiscalStartMonth != nil {
+		preferences := db.UpdateUserPreferencesParams{
+			TimeZone:             user.TimeZone,
+			Locale:               user.Locale,
+			PeriodStartDay:       user.PeriodStartDay,
+			FiscalYearStartMonth: user.FiscalYearStartMonth,
+			Username:             user.Username,
+		}
+		if req.TimeZone != nil {
+			preferences.TimeZone = *req.TimeZone
+		}
+		if req.Locale != nil {
+			preferences.Locale = *req.Locale
+		}
+		if req.PeriodStartDay != nil {
+			preferences.PeriodStartDay = *req.PeriodStartDay
+		}
+		if req.FiscalStartMonth != nil {
+			preferences.FiscalYearStartMonth = *req.FiscalStartMonth
+		}
+
+		if !util.IsTimeZone(preferences.TimeZone) {
+			respondError(ctx, apierror.New(http.StatusBadRequest, "unknown time zone"))
+			return
+		}
+
+		if !util.IsLocale(preferences.Locale) {
+			respondError(ctx, apierror.New(http.StatusBadRequest, "invalid locale"))
+			return
+		}
+
+		arg.Preferences = &preferences
+	}
+
+	// the name and the preferences change together or not at all
+	if arg.Name != "" || arg.Preferences != nil {
+		updatedUser, err := server.store.UpdateProfileTx(ctx, arg)
+		if err != nil {
+			respondError(ctx, err)
+			return
+		}
+
+		user = updatedUser
+	}
+
+	pending := []string{}
+
+	if req.Email != nil && (*req.Email != user.Email || !user.EmailVerifiedAt.Valid) {
+		if err := server.sendContactVerification(ctx, user, "email", *req.Email); err != nil {
+			respondError(ctx, err)
+			return
+		}
+		pending = append(pending, "email")
+	}
+
+	if req.Phone != nil && *req.Phone != user.Phone {
+		if err := server.sendContactVerification(ctx, user, "phone", *req.Phone); err != nil {
+			respondError(ctx, err)
+			return
+		}
+		pending = append(pending, "phone")
+	}
+
+	ctx.JSON(http.StatusOK, apitypes.UpdateProfileResponse{
+		Profile:             newProfileResponse(user),
+		PendingVerification: pending,
+	})
+}
+
+// sendContactVerification replaces the pending code of the field with a new one and sends it to value.
+// Errors are ready for respondError, db.ErrTooManyVerifications once the user asked for too many codes.
+func (server *Server) sendContactVerification(ctx *gin.Context, user db.User, field string, value string) error {
+	code, err := util.RandomDigits(6)
+	if err != nil {
+		return apierror.Internal(err, "cannot generate verification code")
+	}
+
+	verification, err := server.store.CreateContactVerificationTx(ctx, db.CreateContactVerificationTxParams{
+		Verification: db.CreateContactVerificationParams{
+			Username:  user.Username,
+			Field:     field,
+			NewValue:  value,
+			Code:      code,
+			ExpiredAt: time.Now().Add(server.config.VerificationCodeDuration),
+		},
+		MaxSends:        server.config.VerificationMaxSends,
+		SendWindowStart: time.Now().Add(-server.config.VerificationSendWindow),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrTooManyVerifications) {
+			return err
+		}
+		return apierror.Internal(err, fmt.Sprintf("cannot create %s verification", field))
+	}
+
+	content := fmt.Sprintf("Your verification code is %s. It expires at %s.", code, util.FormatDateTime(user.Locale, verification.ExpiredAt.In(userLocation(user))))
+	if field == "phone" {
+		err = server.sms.SendSMS(value, content)
+	} else {
+		err = server.mailer.SendEmail(value, "Verify your new email", content)
+	}
+	if err != nil {
+		return apierror.Internal(err, fmt.Sprintf("cannot send %s verification", field))
+	}
+
+	return nil
+}
+
+// VerifyContact applies a pending email or phone change once its code is confirmed.
+func (server *Server) VerifyContact(ctx *gin.Context) {
+	u, exists := ctx.Get("user")
+	if !exists {
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "unauthorized"))
+		return
+	}
+	user, ok := u.(db.User)
+	if !ok {
+		respondError(ctx, apierror.Internal(nil, "invalid user type"))
+		return
+	}
+
+	var req apitypes.VerifyContactRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	verification, err := server.store.GetContactVerification(ctx, db.GetContactVerificationParams{
+		Username:    user.Username,
+		Code:        req.Code,
+		MaxAttempts: server.config.VerificationMaxAttempts,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			// every wrong code counts against the pending verifications, they stop accepting codes after too many
+			if err := server.store.RecordContactVerificationFailure(ctx, user.Username); err != nil {
+				respondError(ctx, apierror.Internal(err, "cannot record failed verification"))
+				return
+			}
+
+			respondError(ctx, apierror.New(http.StatusBadRequest, "invalid verification code"))
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, "cannot get verification"))
+		return
+	}
+
+	if verification.VerifiedAt.Valid || time.Now().After(verification.ExpiredAt) {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "verification code has expired"))
+		return
+	}
+
+	var updatedUser db.User
+	switch verification.Field {
+	case "email":
+		updatedUser, err = server.store.UpdateUserEmail(ctx, db.UpdateUserEmailParams{
+			Email:    verification.NewValue,
+			Username: user.Username,
+		})
+	case "phone":
+		updatedUser, err = server.store.UpdateUserPhone(ctx, db.UpdateUserPhoneParams{
+			Phone:    verification.NewValue,
+			Username: user.Username,
+		})
+	}
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			respondError(ctx, apierror.New(http.StatusForbidden, fmt.Sprintf("%s already exists", verification.Field)))
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, fmt.Sprintf("cannot update %s", verification.Field)))
+		return
+	}
+
+	if err := server.store.MarkContactVerified(ctx, verification.ID); err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot mark verification"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newProfileResponse(updatedUser))
+}
+
+// DeleteAccount schedules the account for deletion.
+// Everything the user owns is purged once the grace period is over,
+// logging in again before that cancels the deletion.
+func (server *Server) DeleteAccount(ctx *gin.Context) {
+	u, exists := ctx.Get("user")
+	if !exists {
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "unauthorized"))
+		return
+	}
+	user, ok := u.(db.User)
+	if !ok {
+		respondError(ctx, apierror.Internal(nil, "invalid user type"))
+		return
+	}
+
+	var req apitypes.DeleteAccountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	if err := util.CheckPassword(req.Password, user.Password); err != nil {
+		respondError(ctx, apierror.New(http.StatusForbidden, "invalid credentials"))
+		return
+	}
+
+	deletedUser, err := server.store.ScheduleUserDeletion(ctx, user.Username)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot delete account"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, apitypes.DeleteAccountResponse{
+		Message: "your account is scheduled for deletion.",
+		PurgeAt: deletedUser.DeletedAt.Time.Add(server.config.AccountDeletionGrace),
+		Profile: newProfileResponse(deletedUser),
+	})
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	db "github.com/sangketkit01/personal-financial/db/sqlc"
+	"github.com/sangketkit01/personal-financial/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateProfileOneTransaction(t *testing.T) {
+	user := db.User{Username: "alice", Name: "Alice", TimeZone: util.DefaultTimeZone, Locale: "th-TH", PeriodStartDay: 1, FiscalYearStartMonth: 1}
+
+	testCases := []struct {
+		name    string
+		body    string
+		status  int
+		updates int
+	}{
+		{"name and preferences", `{"name":"Alicia","time_zone":"UTC","period_start_day":25}`, http.StatusOK, 1},
+		{"name only", `{"name":"Alicia"}`, http.StatusOK, 1},
+		{"nothing to update", `{}`, http.StatusOK, 0},
+		{"blank name keeps the preferences", `{"name":"  ","time_zone":"UTC"}`, http.StatusBadRequest, 0},
+		{"unknown time zone keeps the name", `{"name":"Alicia","time_zone":"Mars/Olympus"}`, http.StatusBadRequest, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := newFakeStore()
+			store.addUser(user)
+			server := newTestServer(t, store, util.Config{})
+
+			recorder := serveJSON(server.UpdateProfile, &user, http.MethodPatch, "/me", tc.body)
+			require.Equal(t, tc.status, recorder.Code)
+			require.Len(t, store.profileUpdates, tc.updates)
+		})
+	}
+}
+
+func TestUpdateProfileVerificationLimit(t *testing.T) {
+	user := db.User{Username: "alice", Email: "alice@example.com", TimeZone: util.DefaultTimeZone}
+
+	store := newFakeStore()
+	store.addUser(user)
+	server := newTestServer(t, store, util.Config{
+		VerificationCodeDuration: 10 * time.Minute,
+		VerificationMaxSends:     2,
+		VerificationSendWindow:   time.Hour,
+	})
+
+	for range 2 {
+		recorder := serveJSON(server.UpdateProfile, &user, http.MethodPatch, "/me", `{"email":"new@example.com"}`)
+		require.Equal(t, http.StatusOK, recorder.Code)
+	}
+
+	recorder := serveJSON(server.UpdateProfile, &user, http.MethodPatch, "/me", `{"phone":"0812345678"}`)
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	require.Len(t, store.verifications, 2)
+}
@@ -0,0 +1,301 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/sangketkit01/personal-financial/apierror"
+	"github.com/sangketkit01/personal-financial/apitypes"
+	db "github.com/sangketkit01/personal-financial/db/sqlc"
+)
+
+const statementDateLayout = "2006-01-02"
+
+// newReconciliationResponse reports the totals, difference is zero once every statement line has been matched.
+func newReconciliationResponse(reconciliation db.Reconciliation, totals db.ReconciliationTotalsRow) apitypes.ReconciliationResponse {
+	return apitypes.ReconciliationResponse{
+		ID:                reconciliation.ID,
+		StatementDate:     reconciliation.StatementDate.Time.Format(statementDateLayout),
+		StatementBalance:  reconciliation.StatementBalance,
+		ReconciledBalance: totals.ReconciledBalance,
+		ClearedBalance:    totals.ClearedBalance,
+		ClearedCount:      totals.ClearedCount,
+		Difference:        db.ReconciliationDifference(reconciliation, totals),
+		Locked:            reconciliation.LockedAt.Valid,
+		LockedAt:          timePtr(reconciliation.LockedAt),
+		CreatedAt:         reconciliation.CreatedAt,
+	}
+}
+
+// statementCutoff is the first instant after the statement date in the time zone of the user,
+// only records before it can be cleared.
+func statementCutoff(reconciliation db.Reconciliation, user db.User) time.Time {
+	year, month, day := reconciliation.StatementDate.Time.Date()
+	return time.Date(year, month, day+1, 0, 0, 0, 0, userLocation(user))
+}
+
+// getReconciliation loads the reconciliation in the path for the current user and writes the error response itself.
+func (server *Server) getReconciliation(ctx *gin.Context, user db.User) (db.Reconciliation, bool) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || id <= 0 {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "invalid reconciliation id."))
+		return db.Reconciliation{}, false
+	}
+
+	reconciliation, err := server.store.GetReconciliation(ctx, db.GetReconciliationParams{
+		ID:     int64(id),
+		UserID: user.Username,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			respondError(ctx, apierror.New(http.StatusNotFound, "reconciliation not found."))
+			return db.Reconciliation{}, false
+		}
+
+		respondError(ctx, apierror.Internal(err, "cannot get reconciliation."))
+		return db.Reconciliation{}, false
+	}
+
+	return reconciliation, true
+}
+
+func (server *Server) reconciliationResponse(ctx *gin.Context, reconciliation db.Reconciliation) (apitypes.ReconciliationResponse, error) {
+	totals, err := server.store.ReconciliationTotals(ctx, db.ReconciliationTotalsParams{
+		ReconciliationID: reconciliation.ID,
+		UserID:           reconciliation.UserID,
+	})
+	if err != nil {
+		return apitypes.ReconciliationResponse{}, err
+	}
+
+	return newReconciliationResponse(reconciliation, totals), nil
+}
+
+// CreateReconciliation starts matching the ledger against a bank statement.
+func (server *Server) CreateReconciliation(ctx *gin.Context) {
+	user := ctx.MustGet("user").(db.User)
+
+	var req apitypes.CreateReconciliationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	statementDate, err := time.Parse(statementDateLayout, req.StatementDate)
+	if err != nil {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "statement_date must be in YYYY-MM-DD format."))
+		return
+	}
+
+	reconciliation, err := server.store.CreateReconciliation(ctx, db.CreateReconciliationParams{
+		UserID:           user.Username,
+		StatementDate:    pgtype.Date{Time: statementDate, Valid: true},
+		StatementBalance: *req.StatementBalance,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			respondError(ctx, apierror.New(http.StatusBadRequest, "finish or cancel your open reconciliation first."))
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, "cannot create reconciliation."))
+		return
+	}
+
+	response, err := server.reconciliationResponse(ctx, reconciliation)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get reconciliation totals."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (server *Server) ListReconciliations(ctx *gin.Context) {
+	user := ctx.MustGet("user").(db.User)
+
+	reconciliations, err := server.store.ListReconciliations(ctx, user.Username)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get reconciliations."))
+		return
+	}
+
+	response := make([]apitypes.ReconciliationResponse, len(reconciliations))
+	for i, reconciliation := range reconciliations {
+		response[i], err = server.reconciliationResponse(ctx, reconciliation)
+		if err != nil {
+			respondError(ctx, apierror.Internal(err, "cannot get reconciliation totals."))
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetReconciliation returns the totals and the records that can still be ticked off.
+func (server *Server) GetReconciliation(ctx *gin.Context) {
+	user := ctx.MustGet("user").(db.User)
+
+	reconciliation, ok := server.getReconciliation(ctx, user)
+	if !ok {
+		return
+	}
+
+	response, err := server.reconciliationResponse(ctx, reconciliation)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get reconciliation totals."))
+		return
+	}
+
+	candidates := []db.ListReconciliationCandidatesRow{}
+	if !reconciliation.LockedAt.Valid {
+		candidates, err = server.store.ListReconciliationCandidates(ctx, db.ListReconciliationCandidatesParams{
+			UserID:           user.Username,
+			CreatedBefore:    statementCutoff(reconciliation, user),
+			ReconciliationID: reconciliation.ID,
+		})
+		if err != nil {
+			respondError(ctx, apierror.Internal(err, "cannot get financials."))
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, apitypes.ReconciliationDetailResponse{
+		Reconciliation: response,
+		Financials:     convertAll(candidates, newReconciliationCandidate),
+	})
+}
+
+// ClearFinancials ticks off records that appear on the statement.
+func (server *Server) ClearFinancials(ctx *gin.Context) {
+	server.setCleared(ctx, true)
+}
+
+// UnclearFinancials unticks records cleared by mistake.
+func (server *Server) UnclearFinancials(ctx *gin.Context) {
+	server.setCleared(ctx, false)
+}
+
+func (server *Server) setCleared(ctx *gin.Context, cleared bool) {
+	user := ctx.MustGet("user").(db.User)
+
+	var req apitypes.ClearFinancialsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	reconciliation, ok := server.getReconciliation(ctx, user)
+	if !ok {
+		return
+	}
+
+	if reconciliation.LockedAt.Valid {
+		respondError(ctx, apierror.New(http.StatusConflict, "reconciliation is locked."))
+		return
+	}
+
+	var updated []int64
+	var err error
+	if cleared {
+		updated, err = server.store.ClearFinancials(ctx, db.ClearFinancialsParams{
+			ReconciliationID: reconciliation.ID,
+			Ids:              req.FinancialIDs,
+			UserID:           user.Username,
+			CreatedBefore:    statementCutoff(reconciliation, user),
+		})
+	} else {
+		updated, err = server.store.UnclearFinancials(ctx, db.UnclearFinancialsParams{
+			Ids:              req.FinancialIDs,
+			ReconciliationID: reconciliation.ID,
+		})
+	}
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot update financials."))
+		return
+	}
+
+	response, err := server.reconciliationResponse(ctx, reconciliation)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get reconciliation totals."))
+		return
+	}
+
+	// ids that were not changed are already cleared, reconciled, deleted or after the statement date
+	ctx.JSON(http.StatusOK, apitypes.ClearFinancialsResponse{
+		Updated:        updated,
+		Reconciliation: response,
+	})
+}
+
+// FinishReconciliation locks the reconciliation once the difference is zero.
+func (server *Server) FinishReconciliation(ctx *gin.Context) {
+	user := ctx.MustGet("user").(db.User)
+
+	reconciliation, ok := server.getReconciliation(ctx, user)
+	if !ok {
+		return
+	}
+
+	locked, err := server.store.FinishReconciliationTx(ctx, reconciliation.ID, auditInfo(ctx, user))
+	if err != nil {
+		if errors.Is(err, db.ErrReconciliationLocked) {
+			respondError(ctx, err)
+			return
+		}
+
+		if errors.Is(err, db.ErrReconciliationUnbalanced) {
+			response, err := server.reconciliationResponse(ctx, reconciliation)
+			if err != nil {
+				respondError(ctx, apierror.Internal(err, "cannot get reconciliation totals."))
+				return
+			}
+
+			detail := fmt.Sprintf("the difference must be zero to finish the reconciliation, it is %d.", response.Difference)
+			respondError(ctx, apierror.New(http.StatusBadRequest, detail).WithCode(apierror.CodeReconcileUnbalanced))
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, "cannot finish reconciliation."))
+		return
+	}
+
+	response, err := server.reconciliationResponse(ctx, locked)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get reconciliation totals."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// CancelReconciliation drops an open reconciliation, its cleared records go back to uncleared.
+func (server *Server) CancelReconciliation(ctx *gin.Context) {
+	user := ctx.MustGet("user").(db.User)
+
+	reconciliation, ok := server.getReconciliation(ctx, user)
+	if !ok {
+		return
+	}
+
+	err := server.store.CancelReconciliationTx(ctx, reconciliation.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrReconciliationLocked) {
+			respondError(ctx, err)
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, "cannot cancel reconciliation."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, apitypes.MessageResponse{Message: "reconciliation cancelled."})
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/sangketkit01/personal-financial/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatementCutoff(t *testing.T) {
+	reconciliation := db.Reconciliation{
+		StatementDate: pgtype.Date{Time: time.Date(2026, time.January, 31, 0, 0, 0, 0, time.UTC), Valid: true},
+	}
+
+	testCases := []struct {
+		timeZone string
+		cutoff   time.Time
+	}{
+		{"UTC", time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"Asia/Bangkok", time.Date(2026, time.January, 31, 17, 0, 0, 0, time.UTC)},
+		{"America/New_York", time.Date(2026, time.February, 1, 5, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.timeZone, func(t *testing.T) {
+			cutoff := statementCutoff(reconciliation, db.User{TimeZone: tc.timeZone})
+			require.True(t, tc.cutoff.Equal(cutoff), cutoff)
+		})
+	}
+}
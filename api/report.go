@@ -0,0 +1,224 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sangketkit01/personal-financial/apierror"
+	"github.com/sangketkit01/personal-financial/apitypes"
+	db "github.com/sangketkit01/personal-financial/db/sqlc"
+	"github.com/sangketkit01/personal-financial/util"
+)
+
+const (
+	reportDateLayout  = "2006-01-02"
+	reportMonthLayout = "2006-01"
+	maxTrendMonths    = 60
+)
+
+// reportDate parses an optional query date in loc, the fallback is used when the param is missing.
+func reportDate(ctx *gin.Context, key string, layout string, loc *time.Location, fallback time.Time) (time.Time, error) {
+	value := ctx.Query(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	t, err := time.ParseInLocation(layout, value, loc)
+	if err != nil {
+		return t, fmt.Errorf("%s must be in %s format", key, layout)
+	}
+
+	return t, nil
+}
+
+func newDelta(current int64, previous int64) apitypes.Delta {
+	delta := apitypes.Delta{Absolute: current - previous}
+	if previous != 0 {
+		percent := float64(current-previous) / float64(util.Abs(previous)) * 100
+		delta.Percent = &percent
+	}
+	return delta
+}
+
+// ComparePeriods returns per category totals of two periods with their deltas.
+// from/to is the current period and compare_from/compare_to the previous one, both ends are inclusive dates.
+// The default compares this month with last month.
+func (server *Server) ComparePeriods(ctx *gin.Context) {
+	user := ctx.MustGet("user").(db.User)
+
+	loc := userLocation(user)
+	thisMonth := util.MonthStart(time.Now().In(loc))
+
+	var dates [4]time.Time
+	for i, param := range []struct {
+		key      string
+		fallback time.Time
+	}{
+		{"from", thisMonth},
+		{"to", thisMonth.AddDate(0, 1, -1)},
+		{"compare_from", thisMonth.AddDate(0, -1, 0)},
+		{"compare_to", thisMonth.AddDate(0, 0, -1)},
+	} {
+		t, err := reportDate(ctx, param.key, reportDateLayout, loc, param.fallback)
+		if err != nil {
+			respondError(ctx, badRequest(err))
+			return
+		}
+		dates[i] = t
+	}
+
+	if dates[1].Before(dates[0]) || dates[3].Before(dates[2]) {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "a period cannot end before it starts."))
+		return
+	}
+
+	current, err := server.store.SummaryByTypeBetween(ctx, db.SummaryByTypeBetweenParams{
+		UserID:   user.Username,
+		FromTime: dates[0],
+		ToTime:   dates[1].AddDate(0, 0, 1),
+	})
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get summary."))
+		return
+	}
+
+	previous, err := server.store.SummaryByTypeBetween(ctx, db.SummaryByTypeBetweenParams{
+		UserID:   user.Username,
+		FromTime: dates[2],
+		ToTime:   dates[3].AddDate(0, 0, 1),
+	})
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get summary."))
+		return
+	}
+
+	byType := map[string]*apitypes.CategoryComparison{}
+	get := func(t string) *apitypes.CategoryComparison {
+		if byType[t] == nil {
+			byType[t] = &apitypes.CategoryComparison{Type: t}
+		}
+		return byType[t]
+	}
+
+	var currentTotal, previousTotal apitypes.Totals
+	for _, row := range current {
+		get(row.Type).Current = apitypes.Totals{Income: row.TotalIncome, Expense: row.TotalExpense, Net: row.TotalIncome + row.TotalExpense}
+		currentTotal.Income += row.TotalIncome
+		currentTotal.Expense += row.TotalExpense
+	}
+	for _, row := range previous {
+		get(row.Type).Previous = apitypes.Totals{Income: row.TotalIncome, Expense: row.TotalExpense, Net: row.TotalIncome + row.TotalExpense}
+		previousTotal.Income += row.TotalIncome
+		previousTotal.Expense += row.TotalExpense
+	}
+	currentTotal.Net = currentTotal.Income + currentTotal.Expense
+	previousTotal.Net = previousTotal.Income + previousTotal.Expense
+
+	categories := make([]apitypes.CategoryComparison, 0, len(byType))
+	for _, category := range byType {
+		category.IncomeDelta = newDelta(category.Current.Income, category.Previous.Income)
+		category.ExpenseDelta = newDelta(category.Current.Expense, category.Previous.Expense)
+		category.NetDelta = newDelta(category.Current.Net, category.Previous.Net)
+		categories = append(categories, *category)
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].Type < categories[j].Type })
+
+	ctx.JSON(http.StatusOK, apitypes.CompareResponse{
+		Current:    apitypes.Period{From: dates[0].Format(reportDateLayout), To: dates[1].Format(reportDateLayout)},
+		Previous:   apitypes.Period{From: dates[2].Format(reportDateLayout), To: dates[3].Format(reportDateLayout)},
+		Categories: categories,
+		Total: apitypes.CategoryComparison{
+			Type:         "Total",
+			Current:      currentTotal,
+			Previous:     previousTotal,
+			IncomeDelta:  newDelta(currentTotal.Income, previousTotal.Income),
+			ExpenseDelta: newDelta(currentTotal.Expense, previousTotal.Expense),
+			NetDelta:     newDelta(currentTotal.Net, previousTotal.Net),
+		},
+	})
+}
+
+// Trend returns a monthly series per category from the from month to the to month (YYYY-MM, inclusive).
+// Months are the budgeting months of the user, months without records are filled with zeros.
+// The default is the last 12 months.
+func (server *Server) Trend(ctx *gin.Context) {
+	user := ctx.MustGet("user").(db.User)
+
+	loc := userLocation(user)
+	calendar := userCalendar(user)
+	year, month := currentPeriod(user)
+	thisMonth := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+
+	from, err := reportDate(ctx, "from", reportMonthLayout, loc, thisMonth.AddDate(0, -11, 0))
+	if err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	to, err := reportDate(ctx, "to", reportMonthLayout, loc, thisMonth)
+	if err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	var months []time.Time
+	for month := from; !month.After(to); month = month.AddDate(0, 1, 0) {
+		months = append(months, month)
+	}
+
+	if len(months) == 0 || len(months) > maxTrendMonths {
+		respondError(ctx, apierror.New(http.StatusBadRequest, fmt.Sprintf("the range must cover 1 to %d months.", maxTrendMonths)))
+		return
+	}
+
+	rows, err := server.store.MonthlySummaryByTypeBetween(ctx, db.MonthlySummaryByTypeBetweenParams{
+		UserID:   user.Username,
+		FromTime: calendar.PeriodStart(from.Year(), from.Month()),
+		ToTime:   calendar.PeriodStart(to.Year(), to.Month()).AddDate(0, 1, 0),
+	})
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get summary."))
+		return
+	}
+
+	index := map[string]int{}
+	for i, month := range months {
+		index[month.Format(reportMonthLayout)] = i
+	}
+
+	trends := []apitypes.CategoryTrend{}
+	byType := map[string]int{}
+	for _, row := range rows {
+		i, ok := byType[row.Type]
+		if !ok {
+			points := make([]apitypes.TrendPoint, len(months))
+			for m, month := range months {
+				points[m].Month = month.Format(reportMonthLayout)
+			}
+
+			trends = append(trends, apitypes.CategoryTrend{Type: row.Type, Points: points})
+			i = len(trends) - 1
+			byType[row.Type] = i
+		}
+
+		m, ok := index[row.Month]
+		if !ok {
+			continue
+		}
+
+		trends[i].Points[m].Totals = apitypes.Totals{
+			Income:  row.TotalIncome,
+			Expense: row.TotalExpense,
+			Net:     row.TotalIncome + row.TotalExpense,
+		}
+	}
+
+	ctx.JSON(http.StatusOK, apitypes.TrendResponse{
+		From:       from.Format(reportMonthLayout),
+		To:         to.Format(reportMonthLayout),
+		Categories: trends,
+	})
+}
@@ -1,14 +1,52 @@
 package api
 
 import (
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/sangketkit01/personal-financial/apierror"
+	"github.com/sangketkit01/personal-financial/telemetry"
 )
 
-func errorResponse(err error) gin.H{
-	return gin.H{"error" : err.Error()}
+// respondError answers with err as RFC 7807 problem details and stops the handler chain.
+// Errors that toAPIError does not know become a 500, their text is logged but not sent.
+func respondError(ctx *gin.Context, err error) {
+	apiErr := toAPIError(err)
+
+	if apiErr.Err != nil {
+		ctx.Error(apiErr.Err)
+	}
+	if apiErr.Status >= http.StatusInternalServerError {
+		slog.ErrorContext(ctx, "request failed", "code", apiErr.Code, "detail", apiErr.Detail, "err", apiErr.Err)
+	}
+
+	ctx.Header("Content-Type", apierror.ContentType)
+	ctx.AbortWithStatusJSON(apiErr.Status, apiErr.Problem(ctx.Request.URL.Path, telemetry.RequestID(ctx)))
 }
 
-func newErrorResponse(message string) gin.H{
-	return gin.H{"error" : message}
+// useRequestFieldNames makes validation errors name fields as the client sends them,
+// by their json tag, or form tag for query parameters, instead of the Go field name.
+func useRequestFieldNames() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "form", "uri"} {
+			name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return field.Name
+	})
 }
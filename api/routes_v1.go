@@ -0,0 +1,135 @@
+package api
+
+import "github.com/gin-gonic/gin"
+
+// setupV1Routes registers the /api/v1 routes. Every route must be described in apiOperations,
+// NewServer fails otherwise.
+func (server *Server) setupV1Routes(router *gin.RouterGroup) {
+	router.GET("/openapi.json", server.OpenAPI)
+
+	router.POST("/users", server.createUser)
+	router.POST("/sessions", server.LoginUser)
+	router.POST("/unlock-requests", server.RequestUnlock)
+	router.POST("/unlocks", server.UnlockAccount)
+
+	if server.oidc != nil {
+		router.GET("/oauth/login", server.OIDCLogin)
+		router.GET("/oauth/callback", server.OIDCCallback)
+	}
+
+	authRoute := router.Group("/")
+	authRoute.Use(server.authMiddleware(server.tokenMaker))
+
+	accountRoute := authRoute.Group("/me")
+	accountRoute.Use(server.sessionOnlyMiddleware())
+	accountRoute.GET("", server.GetProfile)
+	accountRoute.PATCH("", server.UpdateProfile)
+	accountRoute.DELETE("", server.DeleteAccount)
+	accountRoute.POST("/verify", server.VerifyContact)
+	accountRoute.PUT("/password", server.UpdateUserPassword)
+	accountRoute.POST("/tokens", server.CreatePersonalAccessToken)
+	accountRoute.GET("/tokens", server.ListPersonalAccessTokens)
+	accountRoute.DELETE("/tokens/:id", server.RevokePersonalAccessToken)
+
+	ledgerRoute := authRoute.Group("/")
+	ledgerRoute.Use(server.scopeMiddleware(scopeReadFinancial, scopeWriteFinancial))
+
+	transactionRoute := ledgerRoute.Group("/transactions")
+	transactionRoute.POST("", server.AddNewFinancial)
+	transactionRoute.GET("", server.MyFinancial)
+
+	recordRoute := transactionRoute.Group("/:id")
+	recordRoute.Use(server.FinancialMiddleware())
+	recordRoute.GET("", server.GetFinancialById)
+	recordRoute.GET("/history", server.FinancialHistory)
+	recordRoute.PUT("", server.UpdateFinancial)
+	recordRoute.DELETE("", server.DeleteFinancial)
+	recordRoute.POST("/restore", server.RestoreFinancial)
+
+	ledgerRoute.GET("/trash", server.Trash)
+	ledgerRoute.GET("/forecast", server.Forecast)
+	ledgerRoute.GET("/insights/anomalies", server.ListAnomalies)
+	ledgerRoute.GET("/reports/compare", server.ComparePeriods)
+	ledgerRoute.GET("/reports/trend", server.Trend)
+
+	summaryRoute := ledgerRoute.Group("/summary")
+	summaryRoute.GET("", server.Summary)
+	summaryRoute.GET("/current-month", server.SummaryCurrentMonth)
+	summaryRoute.GET("/current-year", server.SummaryCurrentYear)
+	summaryRoute.GET("/years", server.SummaryEachYear)
+	summaryRoute.GET("/:year", server.SummaryByYear)
+	summaryRoute.GET("/:year/types", server.SummaryTypeByYear)
+	summaryRoute.GET("/:year/:month", server.SummaryByMonthYear)
+	summaryRoute.GET("/:year/:month/types", server.SummaryTypeByMonthYear)
+
+	periodRoute := ledgerRoute.Group("/periods")
+	periodRoute.GET("", server.ListClosedPeriods)
+	periodRoute.POST("", server.ClosePeriod)
+	periodRoute.DELETE("/:year/:month", server.ReopenPeriod)
+
+	reconciliationRoute := ledgerRoute.Group("/reconciliations")
+	reconciliationRoute.POST("", server.CreateReconciliation)
+	reconciliationRoute.GET("", server.ListReconciliations)
+	reconciliationRoute.GET("/:id", server.GetReconciliation)
+	reconciliationRoute.POST("/:id/clear", server.ClearFinancials)
+	reconciliationRoute.POST("/:id/unclear", server.UnclearFinancials)
+	reconciliationRoute.POST("/:id/finish", server.FinishReconciliation)
+	reconciliationRoute.DELETE("/:id", server.CancelReconciliation)
+
+	householdRoute := ledgerRoute.Group("/households")
+	householdRoute.POST("", server.CreateHousehold)
+	householdRoute.GET("", server.MyHouseholds)
+	householdRoute.POST("/join", server.JoinHousehold)
+
+	householdMemberRoute := householdRoute.Group("/:household_id")
+	householdMemberRoute.Use(server.HouseholdMiddleware())
+	householdMemberRoute.GET("", server.GetHousehold)
+	householdMemberRoute.POST("/invitations", server.requireHouseholdRole(householdRoleOwner), server.InviteHouseholdMember)
+	householdMemberRoute.PUT("/members/:username", server.requireHouseholdRole(householdRoleOwner), server.UpdateHouseholdMember)
+	householdMemberRoute.DELETE("/members/:username", server.RemoveHouseholdMember)
+	householdMemberRoute.GET("/transactions", server.HouseholdFinancials)
+	householdMemberRoute.GET("/summary", server.HouseholdSummary)
+
+	// household budgets need the budget scopes, not the financial ones of the other household routes
+	householdBudgetRoute := authRoute.Group("/households/:household_id/budgets")
+	householdBudgetRoute.Use(server.scopeMiddleware(scopeReadBudget, scopeWriteBudget), server.HouseholdMiddleware())
+	householdBudgetRoute.POST("", server.requireHouseholdRole(householdRoleEditor), server.AddHouseholdBudget)
+	householdBudgetRoute.GET("", server.GetHouseholdBudget)
+
+	ledgerRoute.POST("/contacts", server.CreateContact)
+	ledgerRoute.GET("/contacts", server.ListContacts)
+
+	splitRoute := ledgerRoute.Group("/splits")
+	splitRoute.POST("/expenses", server.CreateSharedExpense)
+	splitRoute.GET("/expenses", server.ListSharedExpenses)
+	splitRoute.GET("/balances", server.SplitBalances)
+	splitRoute.POST("/settlements", server.CreateSettlement)
+	splitRoute.GET("/settlements", server.ListSettlements)
+	splitRoute.POST("/settlements/:id/confirm", server.ConfirmSettlement)
+	splitRoute.POST("/settlements/:id/reject", server.RejectSettlement)
+
+	budgetRoute := authRoute.Group("/budgets")
+	budgetRoute.Use(server.scopeMiddleware(scopeReadBudget, scopeWriteBudget))
+	budgetRoute.POST("", server.AddNewBudget)
+	budgetRoute.GET("", server.GetHistoryBudget)
+	budgetRoute.GET("/current", server.GetCurrentBudget)
+	budgetRoute.PUT("/current", server.UpdateBudget)
+	budgetRoute.GET("/current/usage", server.CheckBudgetUsage)
+	budgetRoute.GET("/:year", server.GetBudgetHistoryByYear)
+	budgetRoute.GET("/:year/:month", server.GetBudgetOfMonth)
+
+	adminRoute := authRoute.Group("/admin")
+	adminRoute.Use(server.scopeMiddleware(scopeAdmin, scopeAdmin), server.roleMiddleware(roleAdmin))
+	adminRoute.GET("/financial-types", server.AdminListFinancialTypes)
+	adminRoute.POST("/financial-types", server.AdminCreateFinancialType)
+	adminRoute.PUT("/financial-types/:id", server.AdminUpdateFinancialType)
+	adminRoute.DELETE("/financial-types/:id", server.AdminDeleteFinancialType)
+	adminRoute.GET("/users", server.AdminSearchUsers)
+	adminRoute.GET("/users/:username", server.AdminGetUser)
+	adminRoute.PUT("/users/:username/role", server.AdminSetUserRole)
+	adminRoute.POST("/users/:username/disable", server.AdminDisableUser)
+	adminRoute.POST("/users/:username/enable", server.AdminEnableUser)
+	adminRoute.POST("/users/:username/revoke-sessions", server.AdminRevokeSessions)
+	adminRoute.GET("/stats", server.AdminStats)
+	adminRoute.GET("/audits", server.AdminListAudits)
+}
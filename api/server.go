@@ -1,10 +1,16 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"strings"
+	"sync"
 
 	"github.com/gin-gonic/gin"
 	db "github.com/sangketkit01/personal-financial/db/sqlc"
+	"github.com/sangketkit01/personal-financial/mail"
+	"github.com/sangketkit01/personal-financial/oidc"
 	"github.com/sangketkit01/personal-financial/token"
 	"github.com/sangketkit01/personal-financial/util"
 )
@@ -14,6 +20,16 @@ type Server struct {
 	router *gin.Engine
 	store db.Store
 	tokenMaker token.Maker
+	mailer mail.EmailSender
+	sms mail.SMSSender
+	oidc *oidc.Provider
+	metrics *serverMetrics
+	openAPI *openAPIDocument
+
+	httpServer *http.Server
+	jobsCtx context.Context
+	stopJobs context.CancelFunc
+	jobs sync.WaitGroup
 }
 
 func NewServer(config util.Config, store db.Store, tokenMaker token.Maker) (*Server, error){
@@ -21,38 +37,173 @@ func NewServer(config util.Config, store db.Store, tokenMaker token.Maker) (*Ser
 		config: config,
 		store: store,
 		tokenMaker: tokenMaker,
+		mailer: mail.NewLogSender(),
+		sms: mail.NewLogSender(),
+	}
+
+	if config.OIDCIssuer != "" {
+		server.oidc = oidc.NewProvider(oidc.Config{
+			Issuer: config.OIDCIssuer,
+			ClientID: config.OIDCClientID,
+			ClientSecret: config.OIDCClientSecret,
+			RedirectURL: config.OIDCRedirectURL,
+		})
 	}
 
+	server.jobsCtx, server.stopJobs = context.WithCancel(context.Background())
+
+	useRequestFieldNames()
+	server.setupMetrics()
 	server.setupRoute()
-	server.router.Run(":5315")
+
+	openAPI, err := buildOpenAPI(server.router.Routes())
+	if err != nil {
+		return nil, err
+	}
+	server.openAPI = openAPI
+
+	server.httpServer = &http.Server{
+		Addr: listenAddress(config.ServerPort),
+		Handler: server.router,
+		ReadHeaderTimeout: config.ServerReadTimeout,
+		ReadTimeout: config.ServerReadTimeout,
+		WriteTimeout: config.ServerWriteTimeout,
+		IdleTimeout: config.ServerIdleTimeout,
+	}
 
 	return server, nil
 }
 
+// listenAddress accepts a bare port like 5315 as well as host:port.
+func listenAddress(port string) string {
+	if strings.Contains(port, ":") {
+		return port
+	}
+	return ":" + port
+}
+
+// Address is the address the server listens on.
+func (server *Server) Address() string {
+	return server.httpServer.Addr
+}
+
+// Start runs the background jobs and serves requests until Shutdown is called.
+func (server *Server) Start() error {
+	server.startBackgroundJobs(server.jobsCtx)
+
+	err := server.httpServer.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+
+	server.stopJobs()
+	return err
+}
+
+// Shutdown stops accepting requests and waits for the ones in flight and the running jobs to finish,
+// or for ctx to end.
+func (server *Server) Shutdown(ctx context.Context) error {
+	err := server.httpServer.Shutdown(ctx)
+	server.stopJobs()
+
+	done := make(chan struct{})
+	go func() {
+		server.jobs.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		if err == nil {
+			err = ctx.Err()
+		}
+	}
+
+	return err
+}
+
 func (server *Server) setupRoute(){
-	router := gin.Default()
+	router := gin.New()
+	// handlers pass the gin context on, it must reach the request id and span of the request context
+	router.ContextWithFallback = true
+	// metrics wrap the recovery so a request that panicked is still counted, as the 500 it answered
+	router.Use(requestIDMiddleware(), tracingMiddleware(), accessLogMiddleware(), server.metricsMiddleware(), gin.Recovery())
 
 	router.GET("/", func(ctx *gin.Context) {
 		ctx.JSON(http.StatusOK,gin.H{"message": "Welcome to Personal Financial Management System!"})
 	})
 
-	router.POST("/create-user",server.createUser)
-	router.POST("/login-user",server.LoginUser)
+	router.GET("/healthz", server.Healthz)
+	router.GET("/readyz", server.Readyz)
+	router.GET("/metrics", server.Metrics)
+
+	server.setupV1Routes(router.Group(apiPrefix))
 
-	authRoute := router.Group("/")
+	// the routes from before /api/v1, kept for old clients
+	legacyRoute := router.Group("/")
+	legacyRoute.Use(deprecatedMiddleware())
+
+	legacyRoute.POST("/create-user",server.createUser)
+	legacyRoute.POST("/login-user",server.LoginUser)
+	legacyRoute.POST("/request-unlock", server.RequestUnlock)
+	legacyRoute.POST("/unlock-account", server.UnlockAccount)
+
+	if server.oidc != nil {
+		legacyRoute.GET("/oauth/login", server.OIDCLogin)
+		legacyRoute.GET("/oauth/callback", server.OIDCCallback)
+	}
+
+	authRoute := legacyRoute.Group("/")
 	authRoute.Use(server.authMiddleware(server.tokenMaker))
-	authRoute.PUT("/update-password", server.UpdateUserPassword)
 
-	authRoute.POST("/new-financial", server.AddNewFinancial)
-	authRoute.GET("/my-financial", server.MyFinancial)
+	accountRoute := authRoute.Group("/")
+	accountRoute.Use(server.sessionOnlyMiddleware())
+	accountRoute.PUT("/update-password", server.UpdateUserPassword)
+	accountRoute.GET("/me", server.GetProfile)
+	accountRoute.PATCH("/me", server.UpdateProfile)
+	accountRoute.POST("/me/verify", server.VerifyContact)
+	accountRoute.DELETE("/me", server.DeleteAccount)
+
+	accountRoute.POST("/tokens", server.CreatePersonalAccessToken)
+	accountRoute.GET("/tokens", server.ListPersonalAccessTokens)
+	accountRoute.DELETE("/tokens/:id", server.RevokePersonalAccessToken)
 
-	financialRoute := authRoute.Group("/financial")
+	ledgerRoute := authRoute.Group("/")
+	ledgerRoute.Use(server.scopeMiddleware(scopeReadFinancial, scopeWriteFinancial))
+	ledgerRoute.POST("/new-financial", server.AddNewFinancial)
+	ledgerRoute.GET("/my-financial", server.MyFinancial)
+
+	financialRoute := ledgerRoute.Group("/financial")
 	financialRoute.Use(server.FinancialMiddleware())
 	financialRoute.GET("/get/:id", server.GetFinancialById)
+	financialRoute.GET("/get/:id/history", server.FinancialHistory)
 	financialRoute.PUT("/update/:id", server.UpdateFinancial)
 	financialRoute.DELETE("/delete/:id", server.DeleteFinancial)
+	financialRoute.POST("/restore/:id", server.RestoreFinancial)
+
+	ledgerRoute.GET("/trash", server.Trash)
+	ledgerRoute.GET("/forecast", server.Forecast)
+	ledgerRoute.GET("/insights/anomalies", server.ListAnomalies)
+	ledgerRoute.GET("/reports/compare", server.ComparePeriods)
+	ledgerRoute.GET("/reports/trend", server.Trend)
+
+	periodRoute := ledgerRoute.Group("/periods")
+	periodRoute.GET("", server.ListClosedPeriods)
+	periodRoute.POST("/close", server.ClosePeriod)
+	periodRoute.POST("/reopen", server.ReopenPeriod)
 
-	summaryRoute := authRoute.Group("/summary")
+	reconciliationRoute := ledgerRoute.Group("/reconciliations")
+	reconciliationRoute.POST("", server.CreateReconciliation)
+	reconciliationRoute.GET("", server.ListReconciliations)
+	reconciliationRoute.GET("/:id", server.GetReconciliation)
+	reconciliationRoute.POST("/:id/clear", server.ClearFinancials)
+	reconciliationRoute.POST("/:id/unclear", server.UnclearFinancials)
+	reconciliationRoute.POST("/:id/finish", server.FinishReconciliation)
+	reconciliationRoute.DELETE("/:id", server.CancelReconciliation)
+
+	summaryRoute := ledgerRoute.Group("/summary")
+	summaryRoute.GET("", server.Summary)
 
 	summaryRoute.GET("/current-month", server.SummaryCurrentMonth)
 	summaryRoute.GET("/current-year", server.SummaryCurrentYear)
@@ -64,8 +215,52 @@ func (server *Server) setupRoute(){
 	summaryRoute.GET("/type/month-year", server.SummaryTypeByMonthYear)
 	summaryRoute.GET("/type/year", server.SummaryTypeByYear)
 
+	householdRoute := ledgerRoute.Group("/households")
+	householdRoute.POST("", server.CreateHousehold)
+	householdRoute.GET("", server.MyHouseholds)
+	householdRoute.POST("/join", server.JoinHousehold)
+
+	householdMemberRoute := householdRoute.Group("/:household_id")
+	householdMemberRoute.Use(server.HouseholdMiddleware())
+	householdMemberRoute.GET("", server.GetHousehold)
+	householdMemberRoute.POST("/invitations", server.requireHouseholdRole(householdRoleOwner), server.InviteHouseholdMember)
+	householdMemberRoute.PUT("/members/:username", server.requireHouseholdRole(householdRoleOwner), server.UpdateHouseholdMember)
+	householdMemberRoute.DELETE("/members/:username", server.RemoveHouseholdMember)
+	householdMemberRoute.GET("/financials", server.HouseholdFinancials)
+	householdMemberRoute.GET("/summary", server.HouseholdSummary)
+
+	// household budgets need the budget scopes, not the financial ones of the other household routes
+	householdBudgetRoute := authRoute.Group("/households/:household_id/budget")
+	householdBudgetRoute.Use(server.scopeMiddleware(scopeReadBudget, scopeWriteBudget), server.HouseholdMiddleware())
+	householdBudgetRoute.POST("", server.requireHouseholdRole(householdRoleEditor), server.AddHouseholdBudget)
+	householdBudgetRoute.GET("", server.GetHouseholdBudget)
+
+	ledgerRoute.POST("/contacts", server.CreateContact)
+	ledgerRoute.GET("/contacts", server.ListContacts)
+
+	splitRoute := ledgerRoute.Group("/splits")
+	splitRoute.POST("/expenses", server.CreateSharedExpense)
+	splitRoute.GET("/expenses", server.ListSharedExpenses)
+	splitRoute.GET("/balances", server.SplitBalances)
+	splitRoute.POST("/settlements", server.CreateSettlement)
+
+	adminRoute := authRoute.Group("/admin")
+	adminRoute.Use(server.scopeMiddleware(scopeAdmin, scopeAdmin), server.roleMiddleware(roleAdmin))
+	adminRoute.GET("/financial-types", server.AdminListFinancialTypes)
+	adminRoute.POST("/financial-types", server.AdminCreateFinancialType)
+	adminRoute.PUT("/financial-types/:id", server.AdminUpdateFinancialType)
+	adminRoute.DELETE("/financial-types/:id", server.AdminDeleteFinancialType)
+	adminRoute.GET("/users", server.AdminSearchUsers)
+	adminRoute.GET("/users/:username", server.AdminGetUser)
+	adminRoute.PUT("/users/:username/role", server.AdminSetUserRole)
+	adminRoute.POST("/users/:username/disable", server.AdminDisableUser)
+	adminRoute.POST("/users/:username/enable", server.AdminEnableUser)
+	adminRoute.POST("/users/:username/revoke-sessions", server.AdminRevokeSessions)
+	adminRoute.GET("/stats", server.AdminStats)
+	adminRoute.GET("/audits", server.AdminListAudits)
+
 	budgetRoute := authRoute.Group("/budget")
-	budgetRoute.Use(server.authMiddleware(server.tokenMaker))
+	budgetRoute.Use(server.scopeMiddleware(scopeReadBudget, scopeWriteBudget))
 	budgetRoute.POST("/", server.AddNewBudget)
 	budgetRoute.GET("/", server.GetCurrentBudget)
 	budgetRoute.PUT("/", server.UpdateBudget)
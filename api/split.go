@@ -0,0 +1,500 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/sangketkit01/personal-financial/apierror"
+	"github.com/sangketkit01/personal-financial/apitypes"
+	db "github.com/sangketkit01/personal-financial/db/sqlc"
+	"github.com/sangketkit01/personal-financial/util"
+)
+
+func participantKey(p apitypes.Participant) string {
+	if p.Username != "" {
+		return "user:" + p.Username
+	}
+	return "contact:" + strconv.FormatInt(p.ContactID, 10)
+}
+
+func participantText(p apitypes.Participant) pgtype.Text {
+	return pgtype.Text{String: p.Username, Valid: p.Username != ""}
+}
+
+func participantContact(p apitypes.Participant) pgtype.Int8 {
+	return pgtype.Int8{Int64: p.ContactID, Valid: p.Username == ""}
+}
+
+func participantOf(username pgtype.Text, contactID pgtype.Int8) apitypes.Participant {
+	if username.Valid {
+		return apitypes.Participant{Username: username.String}
+	}
+	return apitypes.Participant{ContactID: contactID.Int64}
+}
+
+// checkConnected fails with a 403 unless other shares a household with username or keeps them as a contact.
+func (server *Server) checkConnected(ctx *gin.Context, username, other string) error {
+	connected, err := server.store.UsersAreConnected(ctx, db.UsersAreConnectedParams{
+		Username: username,
+		Other:    other,
+	})
+	if err != nil {
+		return apierror.Internal(err, "cannot check the other user.")
+	}
+
+	if !connected {
+		return apierror.New(http.StatusForbidden, fmt.Sprintf("%s is not a member of your households and does not have you as a contact.", other))
+	}
+
+	return nil
+}
+
+// validateParticipant checks that the participant is an existing user or a contact owned by the caller.
+// A participant that does not exist is a 400, a failed lookup a 500.
+func (server *Server) validateParticipant(ctx *gin.Context, owner string, p apitypes.Participant) error {
+	if (p.Username == "") == (p.ContactID == 0) {
+		return apierror.New(http.StatusBadRequest, "a participant needs either a username or a contact_id")
+	}
+
+	if p.Username != "" {
+		if _, err := server.store.GetUser(ctx, p.Username); err != nil {
+			if err == pgx.ErrNoRows {
+				return apierror.New(http.StatusBadRequest, fmt.Sprintf("user %s not found", p.Username))
+			}
+			return apierror.Internal(err, "cannot get participant.")
+		}
+		return nil
+	}
+
+	if _, err := server.store.GetContact(ctx, db.GetContactParams{ID: p.ContactID, Owner: owner}); err != nil {
+		if err == pgx.ErrNoRows {
+			return apierror.New(http.StatusBadRequest, fmt.Sprintf("contact %d not found", p.ContactID))
+		}
+		return apierror.Internal(err, "cannot get participant.")
+	}
+
+	return nil
+}
+
+func (server *Server) CreateContact(ctx *gin.Context) {
+	user := ctx.MustGet("user").(db.User)
+
+	var req apitypes.CreateContactRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	contact, err := server.store.CreateContact(ctx, db.CreateContactParams{
+		Owner: user.Username,
+		Name:  strings.TrimSpace(req.Name),
+		Email: pgtype.Text{String: req.Email, Valid: req.Email != ""},
+	})
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot create contact."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newContact(contact))
+}
+
+func (server *Server) ListContacts(ctx *gin.Context) {
+	user := ctx.MustGet("user").(db.User)
+
+	contacts, err := server.store.ListContacts(ctx, user.Username)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get contacts."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convertAll(contacts, newContact))
+}
+
+// CreateSharedExpense records an expense paid by one person and splits it
+// equally, by shares or by exact amounts between the participants.
+func (server *Server) CreateSharedExpense(ctx *gin.Context) {
+	user := ctx.MustGet("user").(db.User)
+
+	var req apitypes.CreateSharedExpenseRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	involved := req.PaidBy.Username == user.Username
+	seen := map[string]bool{}
+	for _, p := range req.Participants {
+		if seen[participantKey(p.Participant)] {
+			respondError(ctx, apierror.New(http.StatusBadRequest, fmt.Sprintf("%s is listed twice.", participantKey(p.Participant))))
+			return
+		}
+		seen[participantKey(p.Participant)] = true
+
+		if p.Username == user.Username {
+			involved = true
+		}
+	}
+
+	if !involved {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "you must be the payer or one of the participants."))
+		return
+	}
+
+	for _, p := range append([]apitypes.Participant{req.PaidBy}, participantsOf(req.Participants)...) {
+		if err := server.validateParticipant(ctx, user.Username, p); err != nil {
+			respondError(ctx, err)
+			return
+		}
+
+		// the expense shows up in the balances of every registered user on it
+		if p.Username != "" && p.Username != user.Username {
+			if err := server.checkConnected(ctx, user.Username, p.Username); err != nil {
+				respondError(ctx, err)
+				return
+			}
+		}
+	}
+
+	amounts, err := splitAmounts(req)
+	if err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	shares := make([]db.AddSharedExpenseShareParams, len(req.Participants))
+	for i, p := range req.Participants {
+		shares[i] = db.AddSharedExpenseShareParams{
+			Username:  participantText(p.Participant),
+			ContactID: participantContact(p.Participant),
+			Amount:    amounts[i],
+		}
+	}
+
+	result, err := server.store.CreateSharedExpenseTx(ctx, db.CreateSharedExpenseParams{
+		CreatedBy:     user.Username,
+		Description:   strings.TrimSpace(req.Description),
+		Amount:        req.Amount,
+		PaidByUser:    participantText(req.PaidBy),
+		PaidByContact: participantContact(req.PaidBy),
+		SplitMethod:   req.SplitMethod,
+	}, shares)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot save shared expense."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newSharedExpenseResult(result))
+}
+
+func participantsOf(reqs []apitypes.SplitParticipantRequest) []apitypes.Participant {
+	participants := make([]apitypes.Participant, len(reqs))
+	for i, p := range reqs {
+		participants[i] = p.Participant
+	}
+	return participants
+}
+
+func splitAmounts(req apitypes.CreateSharedExpenseRequest) ([]int64, error) {
+	switch req.SplitMethod {
+	case "shares":
+		shares := make([]int64, len(req.Participants))
+		for i, p := range req.Participants {
+			shares[i] = p.Shares
+		}
+		return util.SplitByShares(req.Amount, shares)
+	case "exact":
+		amounts := make([]int64, len(req.Participants))
+		var total int64
+		for i, p := range req.Participants {
+			amounts[i] = p.Amount
+			total += p.Amount
+		}
+		if total != req.Amount {
+			return nil, fmt.Errorf("exact amounts add up to %d, expected %d", total, req.Amount)
+		}
+		return amounts, nil
+	default:
+		return util.SplitEqual(req.Amount, len(req.Participants)), nil
+	}
+}
+
+func (server *Server) ListSharedExpenses(ctx *gin.Context) {
+	user := ctx.MustGet("user").(db.User)
+
+	expenses, err := server.store.ListSharedExpensesForUser(ctx, user.Username)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get shared expenses."))
+		return
+	}
+
+	sharesByExpense, err := server.sharesByExpense(ctx, expenses)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get shared expenses."))
+		return
+	}
+
+	response := make([]apitypes.SharedExpenseResponse, len(expenses))
+	for i, expense := range expenses {
+		response[i] = apitypes.SharedExpenseResponse{
+			SharedExpense: newSharedExpense(expense),
+			Shares:        convertAll(sharesByExpense[expense.ID], newSharedExpenseShare),
+		}
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (server *Server) sharesByExpense(ctx *gin.Context, expenses []db.SharedExpense) (map[int64][]db.SharedExpenseShare, error) {
+	ids := make([]int64, len(expenses))
+	for i, expense := range expenses {
+		ids[i] = expense.ID
+	}
+
+	shares, err := server.store.ListSharedExpenseShares(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[int64][]db.SharedExpenseShare{}
+	for _, share := range shares {
+		result[share.ExpenseID] = append(result[share.ExpenseID], share)
+	}
+
+	return result, nil
+}
+
+// SplitBalances shows who owes whom across every shared expense and settlement the caller is part of,
+// together with the fewest payments that would settle everything.
+func (server *Server) SplitBalances(ctx *gin.Context) {
+	user := ctx.MustGet("user").(db.User)
+
+	expenses, err := server.store.ListSharedExpensesForUser(ctx, user.Username)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get shared expenses."))
+		return
+	}
+
+	sharesByExpense, err := server.sharesByExpense(ctx, expenses)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get shared expenses."))
+		return
+	}
+
+	settlements, err := server.store.ListSettlementsForUser(ctx, user.Username)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get settlements."))
+		return
+	}
+
+	// owes[a][b] is how much a owes b
+	owes := map[string]map[string]int64{}
+	addDebt := func(from string, to string, amount int64) {
+		if from == to {
+			return
+		}
+		if owes[from] == nil {
+			owes[from] = map[string]int64{}
+		}
+		owes[from][to] += amount
+	}
+
+	for _, expense := range expenses {
+		payer := participantKey(participantOf(expense.PaidByUser, expense.PaidByContact))
+		for _, share := range sharesByExpense[expense.ID] {
+			addDebt(participantKey(participantOf(share.Username, share.ContactID)), payer, share.Amount)
+		}
+	}
+
+	for _, settlement := range settlements {
+		// a settlement only moves the balance once both registered sides agree to it
+		if settlement.Status != db.SettlementStatusConfirmed {
+			continue
+		}
+
+		from := participantKey(participantOf(settlement.FromUser, settlement.FromContact))
+		to := participantKey(participantOf(settlement.ToUser, settlement.ToContact))
+		addDebt(to, from, settlement.Amount)
+	}
+
+	pairs := []apitypes.PairBalance{}
+	net := map[string]int64{}
+	done := map[[2]string]bool{}
+	for from, debts := range owes {
+		for to := range debts {
+			a, b := from, to
+			if a > b {
+				a, b = b, a
+			}
+			if done[[2]string{a, b}] {
+				continue
+			}
+			done[[2]string{a, b}] = true
+
+			diff := owes[a][b] - owes[b][a]
+			switch {
+			case diff > 0:
+				pairs = append(pairs, apitypes.PairBalance{From: a, To: b, Amount: diff})
+			case diff < 0:
+				pairs = append(pairs, apitypes.PairBalance{From: b, To: a, Amount: -diff})
+			}
+		}
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].From == pairs[j].From {
+			return pairs[i].To < pairs[j].To
+		}
+		return pairs[i].From < pairs[j].From
+	})
+
+	for _, pair := range pairs {
+		net[pair.From] -= pair.Amount
+		net[pair.To] += pair.Amount
+	}
+
+	ctx.JSON(http.StatusOK, apitypes.SplitBalancesResponse{
+		Balances: pairs,
+		Net:      net,
+		SettleUp: convertAll(util.SettleUp(net), newTransfer),
+	})
+}
+
+// CreateSettlement records a settle-up payment between two people, one of them must be the caller.
+// Only the record of the caller is written. A registered user on the other side must share a household with the caller
+// or keep them as a contact, and the settlement stays pending until they confirm it.
+func (server *Server) CreateSettlement(ctx *gin.Context) {
+	user := ctx.MustGet("user").(db.User)
+
+	var req apitypes.CreateSettlementRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	if req.From.Username != user.Username && req.To.Username != user.Username {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "you must be the payer or the receiver."))
+		return
+	}
+
+	if participantKey(req.From) == participantKey(req.To) {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "cannot settle with yourself."))
+		return
+	}
+
+	for _, p := range []apitypes.Participant{req.From, req.To} {
+		if err := server.validateParticipant(ctx, user.Username, p); err != nil {
+			respondError(ctx, err)
+			return
+		}
+	}
+
+	counterparty := req.To
+	if req.To.Username == user.Username {
+		counterparty = req.From
+	}
+
+	if counterparty.Username != "" {
+		if err := server.checkConnected(ctx, user.Username, counterparty.Username); err != nil {
+			respondError(ctx, err)
+			return
+		}
+	}
+
+	financialType, err := server.store.GetFinancialByName(ctx, db.FallbackFinancialType)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get financial type."))
+		return
+	}
+
+	settlement, err := server.store.CreateSettlementTx(ctx, db.CreateSettlementTxParams{
+		Settlement: db.CreateSettlementParams{
+			CreatedBy:   user.Username,
+			FromUser:    participantText(req.From),
+			FromContact: participantContact(req.From),
+			ToUser:      participantText(req.To),
+			ToContact:   participantContact(req.To),
+			Amount:      req.Amount,
+		},
+		Caller: user.Username,
+		TypeID: financialType.ID,
+		Audit:  auditInfo(ctx, user),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrPeriodClosed) {
+			respondError(ctx, err)
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, "cannot save settlement."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newSettlement(settlement))
+}
+
+// ListSettlements lists the settlements the caller is part of, the pending ones wait for an answer of the other side.
+func (server *Server) ListSettlements(ctx *gin.Context) {
+	user := ctx.MustGet("user").(db.User)
+
+	settlements, err := server.store.ListSettlementsForUser(ctx, user.Username)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get settlements."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convertAll(settlements, newSettlement))
+}
+
+// ConfirmSettlement accepts a pending settlement another user recorded, it writes the matching record of the caller.
+func (server *Server) ConfirmSettlement(ctx *gin.Context) {
+	server.respondToSettlement(ctx, true)
+}
+
+// RejectSettlement refuses a pending settlement, it never reaches the ledger or the balances of the caller.
+func (server *Server) RejectSettlement(ctx *gin.Context) {
+	server.respondToSettlement(ctx, false)
+}
+
+func (server *Server) respondToSettlement(ctx *gin.Context, confirm bool) {
+	user := ctx.MustGet("user").(db.User)
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "invalid id."))
+		return
+	}
+
+	financialType, err := server.store.GetFinancialByName(ctx, db.FallbackFinancialType)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get financial type."))
+		return
+	}
+
+	settlement, err := server.store.RespondToSettlementTx(ctx, db.RespondToSettlementTxParams{
+		ID:       int64(id),
+		Username: user.Username,
+		Confirm:  confirm,
+		TypeID:   financialType.ID,
+		Audit:    auditInfo(ctx, user),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			respondError(ctx, apierror.New(http.StatusNotFound, "no settlement is waiting for your answer."))
+		case errors.Is(err, db.ErrPeriodClosed), errors.Is(err, db.ErrSettlementNotPending):
+			respondError(ctx, err)
+		default:
+			respondError(ctx, apierror.Internal(err, "cannot answer the settlement."))
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newSettlement(settlement))
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sangketkit01/personal-financial/apierror"
+	"github.com/sangketkit01/personal-financial/apitypes"
+	db "github.com/sangketkit01/personal-financial/db/sqlc"
+	"github.com/sangketkit01/personal-financial/util"
+)
+
+const maxSummaryBuckets = 1000
+
+// Summary returns income, expense and net per bucket between from and to (YYYY-MM-DD, inclusive).
+// Buckets are cut in the tz time zone, by default the one of the user. Weeks are ISO weeks, months, quarters
+// and years follow the period start day and fiscal year of the user. Empty buckets are filled with zeros.
+// With group_by=type there is one series per category, otherwise a single "Total" series.
+// Records have no accounts or tags, so type is the only grouping.
+func (server *Server) Summary(ctx *gin.Context) {
+	user := ctx.MustGet("user").(db.User)
+
+	bucket := ctx.DefaultQuery("bucket", util.BucketMonth)
+	if !util.IsBucket(bucket) {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "bucket must be day, week, month, quarter or year."))
+		return
+	}
+
+	groupBy := ctx.Query("group_by")
+	switch groupBy {
+	case "", "type":
+	case "account", "tag":
+		respondError(ctx, apierror.New(http.StatusBadRequest, "records have no accounts or tags, group_by=type is the only grouping."))
+		return
+	default:
+		respondError(ctx, apierror.New(http.StatusBadRequest, "group_by must be type."))
+		return
+	}
+
+	timeZone := ctx.DefaultQuery("tz", userLocation(user).String())
+	if !util.IsTimeZone(timeZone) {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "unknown time zone."))
+		return
+	}
+
+	// the buckets are cut by Postgres, which has a zone database of its own
+	known, err := server.store.IsTimeZone(ctx, timeZone)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot check time zone."))
+		return
+	}
+	if !known {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "unknown time zone."))
+		return
+	}
+	loc := util.Location(timeZone)
+	calendar := userCalendar(user)
+	calendar.Location = loc
+
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	from, err := time.ParseInLocation(reportDateLayout, ctx.DefaultQuery("from", util.MonthStart(today).AddDate(0, -11, 0).Format(reportDateLayout)), loc)
+	if err != nil {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "from must be in 2006-01-02 format."))
+		return
+	}
+
+	to, err := time.ParseInLocation(reportDateLayout, ctx.DefaultQuery("to", today.Format(reportDateLayout)), loc)
+	if err != nil {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "to must be in 2006-01-02 format."))
+		return
+	}
+
+	if to.Before(from) {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "a period cannot end before it starts."))
+		return
+	}
+	end := to.AddDate(0, 0, 1)
+
+	var starts []time.Time
+	index := map[string]int{}
+	for start := calendar.BucketStart(from, bucket); start.Before(end); start = calendar.NextBucket(start, bucket) {
+		if len(starts) == maxSummaryBuckets {
+			respondError(ctx, apierror.New(http.StatusBadRequest, fmt.Sprintf("the range cannot cover more than %d buckets.", maxSummaryBuckets)))
+			return
+		}
+
+		index[calendar.BucketLabel(start, bucket)] = len(starts)
+		starts = append(starts, start)
+	}
+
+	// days are summed up into buckets here, the database does not know the calendar of the user
+	rows, err := server.store.DailySummaryBetween(ctx, db.DailySummaryBetweenParams{
+		TimeZone: loc.String(),
+		GroupBy:  groupBy,
+		UserID:   user.Username,
+		FromTime: from,
+		ToTime:   end,
+	})
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get summary."))
+		return
+	}
+
+	newSeries := func(group string) apitypes.SummarySeries {
+		points := make([]apitypes.SummaryPoint, len(starts))
+		for i, start := range starts {
+			points[i] = apitypes.SummaryPoint{Bucket: calendar.BucketLabel(start, bucket), Start: start}
+		}
+		return apitypes.SummarySeries{Group: group, Points: points}
+	}
+
+	series := []apitypes.SummarySeries{}
+	byGroup := map[string]int{}
+	if groupBy == "" {
+		series = append(series, newSeries("Total"))
+		byGroup[""] = 0
+	}
+
+	for _, row := range rows {
+		s, ok := byGroup[row.GroupKey]
+		if !ok {
+			series = append(series, newSeries(row.GroupKey))
+			s = len(series) - 1
+			byGroup[row.GroupKey] = s
+		}
+
+		i, ok := index[calendar.BucketLabel(calendar.BucketStart(row.Day, bucket), bucket)]
+		if !ok {
+			continue
+		}
+
+		totals := &series[s].Points[i].Totals
+		totals.Income += row.TotalIncome
+		totals.Expense += row.TotalExpense
+		totals.Net += row.TotalIncome + row.TotalExpense
+	}
+
+	ctx.JSON(http.StatusOK, apitypes.SummaryResponse{
+		From:     from.Format(reportDateLayout),
+		To:       to.Format(reportDateLayout),
+		Bucket:   bucket,
+		TimeZone: loc.String(),
+		GroupBy:  groupBy,
+		Series:   series,
+	})
+}
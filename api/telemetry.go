@@ -0,0 +1,103 @@
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sangketkit01/personal-financial/telemetry"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// a request id from the client is kept when it is short and plain, otherwise a new one is made
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// requestIDMiddleware gives every request an id, sent back in the X-Request-ID header and added to its logs.
+func requestIDMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestID := ctx.GetHeader(requestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		ctx.Header(requestIDHeader, requestID)
+		ctx.Request = ctx.Request.WithContext(telemetry.WithRequestID(ctx.Request.Context(), requestID))
+		ctx.Next()
+	}
+}
+
+// tracingMiddleware wraps the handlers of a request in a span, continuing the trace of the caller when it sent one.
+func tracingMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		parent := otel.GetTextMapPropagator().Extract(ctx.Request.Context(), propagation.HeaderCarrier(ctx.Request.Header))
+
+		route := requestRoute(ctx)
+		spanCtx, span := telemetry.Tracer().Start(parent, fmt.Sprintf("%s %s", ctx.Request.Method, route),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", ctx.Request.Method),
+				attribute.String("http.route", route),
+				attribute.String("url.path", ctx.Request.URL.Path),
+				attribute.String("request_id", telemetry.RequestID(parent)),
+			),
+		)
+		defer span.End()
+
+		ctx.Request = ctx.Request.WithContext(spanCtx)
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+		for _, err := range ctx.Errors {
+			span.RecordError(err.Err)
+		}
+	}
+}
+
+// accessLogMiddleware logs every request once it is answered.
+func accessLogMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		level := slog.LevelInfo
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+
+		attrs := []any{
+			slog.String("method", ctx.Request.Method),
+			slog.String("route", requestRoute(ctx)),
+			slog.String("path", ctx.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Duration("duration", time.Since(start)),
+			slog.String("client_ip", ctx.ClientIP()),
+		}
+		if len(ctx.Errors) > 0 {
+			attrs = append(attrs, slog.String("errors", ctx.Errors.String()))
+		}
+
+		slog.Log(ctx.Request.Context(), level, "request", attrs...)
+	}
+}
+
+func requestRoute(ctx *gin.Context) string {
+	if route := ctx.FullPath(); route != "" {
+		return route
+	}
+	return "unmatched"
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/sangketkit01/personal-financial/apierror"
+	"github.com/sangketkit01/personal-financial/apitypes"
+	db "github.com/sangketkit01/personal-financial/db/sqlc"
+)
+
+// Trash lists the deleted financials that can still be restored.
+func (server *Server) Trash(ctx *gin.Context) {
+	user := ctx.MustGet("user").(db.User)
+
+	financials, err := server.store.ListDeletedFinancials(ctx, user.Username)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot get the trash."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, apitypes.TrashResponse{
+		RetentionDays: server.config.TrashRetentionDays,
+		Financials:    convertAll(financials, newDeletedFinancial),
+	})
+}
+
+func (server *Server) RestoreFinancial(ctx *gin.Context) {
+	user := ctx.MustGet("user").(db.User)
+
+	financialId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || financialId <= 0 {
+		respondError(ctx, apierror.New(http.StatusBadRequest, "invalid financial id."))
+		return
+	}
+
+	financial, err := server.store.RestoreFinancialTx(ctx, int64(financialId), auditInfo(ctx, user))
+	if err != nil {
+		if errors.Is(err, db.ErrPeriodClosed) {
+			respondError(ctx, err)
+			return
+		}
+
+		if err == pgx.ErrNoRows {
+			respondError(ctx, apierror.New(http.StatusNotFound, "financial is not in the trash."))
+			return
+		}
+
+		respondError(ctx, apierror.Internal(err, "cannot restore financial."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, apitypes.RestoreFinancialResponse{
+		Message:           "restored financial successfully.",
+		RestoredFinancial: newFinancial(financial),
+	})
+}
@@ -0,0 +1,298 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/sangketkit01/personal-financial/apitypes"
+	db "github.com/sangketkit01/personal-financial/db/sqlc"
+	"github.com/sangketkit01/personal-financial/util"
+)
+
+// The functions below turn database rows into the types of the API, handlers never send a row as it is,
+// so the JSON clients see stays the same when a query changes.
+
+func timePtr(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func textPtr(t pgtype.Text) *string {
+	if !t.Valid {
+		return nil
+	}
+	return &t.String
+}
+
+func int8Ptr(i pgtype.Int8) *int64 {
+	if !i.Valid {
+		return nil
+	}
+	return &i.Int64
+}
+
+// jsonNumber keeps a numeric column as the number the database has, without going through a float.
+func jsonNumber(value any) json.Number {
+	b, err := json.Marshal(value)
+	if err != nil || string(b) == "null" {
+		return "0"
+	}
+	return json.Number(b)
+}
+
+// convertAll converts every row, nil stays nil.
+func convertAll[T any, R any](rows []T, convert func(T) R) []R {
+	if rows == nil {
+		return nil
+	}
+
+	result := make([]R, len(rows))
+	for i, row := range rows {
+		result[i] = convert(row)
+	}
+	return result
+}
+
+func newFinancial(f db.Financial) apitypes.Financial {
+	return apitypes.Financial{
+		ID:               f.ID,
+		UserID:           f.UserID,
+		Amount:           f.Amount,
+		Direction:        f.Direction,
+		TypeID:           f.TypeID,
+		CreatedAt:        f.CreatedAt,
+		HouseholdID:      int8Ptr(f.HouseholdID),
+		DeletedAt:        timePtr(f.DeletedAt),
+		ReconcileStatus:  f.ReconcileStatus,
+		ReconciliationID: int8Ptr(f.ReconciliationID),
+	}
+}
+
+// newFinancialEntry takes a GetFinancialByIdRow as well, converted, it has the same columns.
+func newFinancialEntry(f db.MyFinancialRow) apitypes.FinancialEntry {
+	return apitypes.FinancialEntry{
+		ID:        f.ID,
+		Amount:    f.Amount,
+		Direction: f.Direction,
+		Type:      textPtr(f.Type),
+		CreatedAt: f.CreatedAt,
+	}
+}
+
+func newDeletedFinancial(f db.ListDeletedFinancialsRow) apitypes.DeletedFinancial {
+	return apitypes.DeletedFinancial{
+		ID:        f.ID,
+		Amount:    f.Amount,
+		Direction: f.Direction,
+		Type:      textPtr(f.Type),
+		CreatedAt: f.CreatedAt,
+		DeletedAt: timePtr(f.DeletedAt),
+	}
+}
+
+func newFinancialType(t db.FinancialType) apitypes.FinancialType {
+	return apitypes.FinancialType{ID: t.ID, Type: t.Type}
+}
+
+// newFinancialSummary takes the year rows as well, converted, they have the same columns.
+func newFinancialSummary(row db.SummaryFinancialByMonthRow) apitypes.FinancialSummary {
+	return apitypes.FinancialSummary{TotalIncome: row.TotalIncome, TotalExpense: row.TotalExpense, Status: row.Status}
+}
+
+// newTypeSummary takes the year rows as well, converted, they have the same columns.
+func newTypeSummary(row db.SummaryByTypeMonthRow) apitypes.TypeSummary {
+	return apitypes.TypeSummary{Type: row.Type, TotalIncome: row.TotalIncome, TotalExpense: row.TotalExpense, Status: row.Status}
+}
+
+func newYearSummary(row db.SummaryFinancialEachYearRow) apitypes.YearSummary {
+	return apitypes.YearSummary{
+		Year:      row.Year,
+		InAmount:  jsonNumber(row.InAmount),
+		OutAmount: jsonNumber(row.OutAmount),
+		Status:    row.Status,
+	}
+}
+
+func newAnomaly(a db.Anomaly) apitypes.Anomaly {
+	return apitypes.Anomaly{
+		ID:          a.ID,
+		UserID:      a.UserID,
+		FinancialID: int8Ptr(a.FinancialID),
+		TypeID:      int8Ptr(a.TypeID),
+		Kind:        a.Kind,
+		Reason:      a.Reason,
+		DedupeKey:   a.DedupeKey,
+		CreatedAt:   a.CreatedAt,
+	}
+}
+
+func newAnomalyEntry(a db.ListAnomaliesRow) apitypes.AnomalyEntry {
+	return apitypes.AnomalyEntry{
+		ID:          a.ID,
+		FinancialID: int8Ptr(a.FinancialID),
+		Kind:        a.Kind,
+		Reason:      a.Reason,
+		CreatedAt:   a.CreatedAt,
+		Amount:      int8Ptr(a.Amount),
+		Direction:   textPtr(a.Direction),
+		Type:        textPtr(a.Type),
+	}
+}
+
+func newBudget(b db.Budget) apitypes.Budget {
+	return apitypes.Budget{
+		ID:          b.ID,
+		UserID:      b.UserID,
+		Month:       b.Month,
+		Year:        b.Year,
+		Amount:      jsonNumber(b.Amount),
+		CreatedAt:   b.CreatedAt,
+		UpdatedAt:   b.UpdatedAt,
+		HouseholdID: int8Ptr(b.HouseholdID),
+	}
+}
+
+func newHousehold(h db.Household) apitypes.Household {
+	return apitypes.Household{ID: h.ID, Name: h.Name, Owner: h.Owner, CreatedAt: h.CreatedAt}
+}
+
+func newMyHousehold(h db.ListMyHouseholdsRow) apitypes.MyHousehold {
+	return apitypes.MyHousehold{ID: h.ID, Name: h.Name, Owner: h.Owner, Role: h.Role, CreatedAt: h.CreatedAt}
+}
+
+func newHouseholdMember(m db.HouseholdMember) apitypes.HouseholdMember {
+	return apitypes.HouseholdMember{HouseholdID: m.HouseholdID, Username: m.Username, Role: m.Role, JoinedAt: m.JoinedAt}
+}
+
+func newHouseholdMemberEntry(m db.ListHouseholdMembersRow) apitypes.HouseholdMemberEntry {
+	return apitypes.HouseholdMemberEntry{Username: m.Username, Name: m.Name, Role: m.Role, JoinedAt: m.JoinedAt}
+}
+
+func newHouseholdFinancial(f db.HouseholdFinancialsRow) apitypes.HouseholdFinancial {
+	return apitypes.HouseholdFinancial{
+		ID:        f.ID,
+		UserID:    f.UserID,
+		Amount:    f.Amount,
+		Direction: f.Direction,
+		Type:      textPtr(f.Type),
+		CreatedAt: f.CreatedAt,
+	}
+}
+
+func newPeriodClosing(p db.PeriodClosing) apitypes.PeriodClosing {
+	return apitypes.PeriodClosing{
+		ID:           p.ID,
+		UserID:       p.UserID,
+		Month:        p.Month,
+		Year:         p.Year,
+		TotalIncome:  p.TotalIncome,
+		TotalExpense: p.TotalExpense,
+		Status:       p.Status,
+		ClosedAt:     p.ClosedAt,
+		ReopenedAt:   timePtr(p.ReopenedAt),
+	}
+}
+
+func newReconciliationCandidate(f db.ListReconciliationCandidatesRow) apitypes.ReconciliationCandidate {
+	return apitypes.ReconciliationCandidate{
+		ID:              f.ID,
+		Amount:          f.Amount,
+		Direction:       f.Direction,
+		Type:            textPtr(f.Type),
+		CreatedAt:       f.CreatedAt,
+		ReconcileStatus: f.ReconcileStatus,
+	}
+}
+
+func newContact(c db.Contact) apitypes.Contact {
+	return apitypes.Contact{ID: c.ID, Owner: c.Owner, Name: c.Name, Email: textPtr(c.Email), CreatedAt: c.CreatedAt}
+}
+
+func newSharedExpense(e db.SharedExpense) apitypes.SharedExpense {
+	return apitypes.SharedExpense{
+		ID:            e.ID,
+		CreatedBy:     e.CreatedBy,
+		Description:   e.Description,
+		Amount:        e.Amount,
+		PaidByUser:    textPtr(e.PaidByUser),
+		PaidByContact: int8Ptr(e.PaidByContact),
+		SplitMethod:   e.SplitMethod,
+		CreatedAt:     e.CreatedAt,
+	}
+}
+
+func newSharedExpenseShare(s db.SharedExpenseShare) apitypes.SharedExpenseShare {
+	return apitypes.SharedExpenseShare{
+		ID:        s.ID,
+		ExpenseID: s.ExpenseID,
+		Username:  textPtr(s.Username),
+		ContactID: int8Ptr(s.ContactID),
+		Amount:    s.Amount,
+	}
+}
+
+func newSharedExpenseResult(r db.SharedExpenseTxResult) apitypes.SharedExpenseResult {
+	return apitypes.SharedExpenseResult{
+		Expense: newSharedExpense(r.Expense),
+		Shares:  convertAll(r.Shares, newSharedExpenseShare),
+	}
+}
+
+func newSettlement(s db.Settlement) apitypes.Settlement {
+	return apitypes.Settlement{
+		ID:              s.ID,
+		CreatedBy:       s.CreatedBy,
+		FromUser:        textPtr(s.FromUser),
+		FromContact:     int8Ptr(s.FromContact),
+		ToUser:          textPtr(s.ToUser),
+		ToContact:       int8Ptr(s.ToContact),
+		Amount:          s.Amount,
+		FromFinancialID: int8Ptr(s.FromFinancialID),
+		ToFinancialID:   int8Ptr(s.ToFinancialID),
+		CreatedAt:       s.CreatedAt,
+		Status:          s.Status,
+		RespondedAt:     timePtr(s.RespondedAt),
+	}
+}
+
+func newTransfer(t util.Transfer) apitypes.Transfer {
+	return apitypes.Transfer{From: t.From, To: t.To, Amount: t.Amount}
+}
+
+func newForecastPoint(p util.ForecastPoint) apitypes.ForecastPoint {
+	return apitypes.ForecastPoint{
+		Start:    p.Start,
+		Income:   p.Income,
+		Expense:  p.Expense,
+		Net:      p.Net,
+		Balance:  p.Balance,
+		Negative: p.Negative,
+	}
+}
+
+func newAdminAudit(a db.AdminAudit) apitypes.AdminAudit {
+	return apitypes.AdminAudit{
+		ID:        a.ID,
+		Admin:     a.Admin,
+		Action:    a.Action,
+		Target:    a.Target,
+		Details:   a.Details,
+		ClientIp:  a.ClientIp,
+		CreatedAt: a.CreatedAt,
+	}
+}
+
+func newSystemStats(s db.GetSystemStatsRow) apitypes.SystemStats {
+	return apitypes.SystemStats{
+		TotalUsers:           s.TotalUsers,
+		DisabledUsers:        s.DisabledUsers,
+		DeletedUsers:         s.DeletedUsers,
+		TotalFinancials:      s.TotalFinancials,
+		FinancialsLast30Days: s.FinancialsLast30Days,
+		TotalBudgets:         s.TotalBudgets,
+		TotalHouseholds:      s.TotalHouseholds,
+	}
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"errors"
+	"log/slog"
 	"net/http"
 	"strings"
 	"time"
@@ -9,40 +10,25 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/sangketkit01/personal-financial/apierror"
+	"github.com/sangketkit01/personal-financial/apitypes"
 	db "github.com/sangketkit01/personal-financial/db/sqlc"
 	"github.com/sangketkit01/personal-financial/token"
 	"github.com/sangketkit01/personal-financial/util"
 	"golang.org/x/crypto/bcrypt"
 )
 
-type CreateUserRequest struct {
-	Username string `json:"username" binding:"required"`
-	Name     string `json:"name" binding:"required"`
-	Email    string `json:"email" binding:"required,email"`
-	Phone    string `json:"phone" binding:"required,min=10,max=10"`
-	Password string `json:"password" binding:"required,min=8"`
-}
-
-type CreateUserResponse struct {
-	Username  string    `json:"username"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	Phone     string    `json:"phone"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-}
-
 func (server *Server) createUser(ctx *gin.Context) {
-	var req CreateUserRequest
+	var req apitypes.CreateUserRequest
 	err := ctx.ShouldBindJSON(&req)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, badRequest(err))
 		return
 	}
 
 	hashedPassword, err := util.HashPassword(req.Password)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "hashed password error"})
+		respondError(ctx, apierror.Internal(err, "hashed password error"))
 		return
 	}
 
@@ -55,17 +41,18 @@ func (server *Server) createUser(ctx *gin.Context) {
 	}
 
 	user, err := server.store.CreateUser(ctx, arg)
-	var pgErr *pgconn.PgError
-	if errors.As(err, &pgErr) {
-		if pgErr.Code == "23505" {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": "username or email already exists"})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			respondError(ctx, apierror.New(http.StatusForbidden, "username or email already exists"))
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "cannot create user"})
+		respondError(ctx, apierror.Internal(err, "cannot create user"))
+		return
 	}
 
-	response := CreateUserResponse{
+	response := apitypes.CreateUserResponse{
 		Username:  user.Username,
 		Name:      user.Name,
 		Email:     user.Email,
@@ -77,67 +64,98 @@ func (server *Server) createUser(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, response)
 }
 
-type LoginUserRequest struct {
-	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required,min=8"`
-}
-
-type LoginUserRespose struct {
-	Username    string    `json:"username"`
-	Name        string    `json:"name"`
-	Email       string    `json:"email"`
-	Phone       string    `json:"phone"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	TokenID     string    `json:"token_id"`
-	AccessToken string    `json:"access_token"`
-	IssuedAt    time.Time `json:"issued_at"`
-	ExpiredAt   time.Time `json:"expired_at"`
-}
-
 func (server *Server) LoginUser(ctx *gin.Context) {
-	var req LoginUserRequest
+	var req apitypes.LoginUserRequest
 	err := ctx.ShouldBindJSON(&req)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, badRequest(err))
+		return
+	}
+
+	retryAfter, err := server.loginRetryAfter(ctx, req.Username)
+	if err != nil {
+		respondError(ctx, apierror.Internal(err, "cannot check login attempts"))
+		return
+	}
+
+	if retryAfter > 0 {
+		tooManyLoginAttempts(ctx, retryAfter)
 		return
 	}
 
 	user, err := server.store.GetUser(ctx, req.Username)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			server.failLogin(ctx, req.Username, "user not found")
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "cannot get user"})
+		respondError(ctx, apierror.Internal(err, "cannot get user"))
 		return
 	}
 
 	err = util.CheckPassword(req.Password, user.Password)
 	if err != nil {
 		if err == bcrypt.ErrMismatchedHashAndPassword {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid password"})
+			server.failLogin(ctx, req.Username, "invalid password")
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, err)
 		return
 	}
 
-	payload, err := token.NewPayload(user.Username, 24*time.Hour)
+	// a parallel attempt may have locked the username or the ip while the password was checked
+	retryAfter, err = server.loginRetryAfter(ctx, req.Username)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, apierror.Internal(err, "cannot check login attempts"))
 		return
 	}
 
-	accessToken, err := server.tokenMaker.CreateToken(user.Username, 24*time.Hour)
+	if retryAfter > 0 {
+		tooManyLoginAttempts(ctx, retryAfter)
+		return
+	}
+
+	if user.DisabledAt.Valid {
+		respondError(ctx, apierror.New(http.StatusForbidden, "account has been disabled"))
+		return
+	}
+
+	if user.DeletedAt.Valid {
+		// logging in during the grace period cancels the account deletion
+		if err := server.store.CancelUserDeletion(ctx, user.Username); err != nil {
+			respondError(ctx, apierror.Internal(err, "cannot restore account"))
+			return
+		}
+	}
+
+	if err := server.resetLoginAttempts(ctx, user.Username); err != nil {
+		slog.WarnContext(ctx, "cannot reset login attempts", "username", user.Username, "err", err)
+	}
+
+	response, err := server.newLoginResponse(user)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, err)
 		return
 	}
 
-	response := LoginUserRespose{
+	ctx.JSON(http.StatusOK, response)
+}
+
+// newLoginResponse issues a session token for a user who has been authenticated.
+func (server *Server) newLoginResponse(user db.User) (apitypes.LoginUserRespose, error) {
+	payload, err := token.NewPayload(user.Username, 24*time.Hour)
+	if err != nil {
+		return apitypes.LoginUserRespose{}, err
+	}
+
+	accessToken, err := server.tokenMaker.CreateToken(user.Username, 24*time.Hour)
+	if err != nil {
+		return apitypes.LoginUserRespose{}, err
+	}
+
+	return apitypes.LoginUserRespose{
 		Username:    user.Username,
 		Email:       user.Email,
 		Name:        user.Name,
@@ -148,32 +166,24 @@ func (server *Server) LoginUser(ctx *gin.Context) {
 		AccessToken: accessToken,
 		IssuedAt:    payload.IssuedAt,
 		ExpiredAt:   payload.ExpiredAt,
-	}
-
-	ctx.JSON(http.StatusOK, response)
-}
-
-type UpdateUserPasswordRequest struct {
-	CurrentPassword string `json:"current_password" binding:"required"`
-	NewPassword     string `json:"new_password" binding:"required,min=8,alphanum"`
-	ConfirmPassword string `json:"confirm_password" binding:"required,eqField=NewPassword"`
+	}, nil
 }
 
 func (server *Server) UpdateUserPassword(ctx *gin.Context) {
 	u, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, newErrorResponse("unauthorized"))
+		respondError(ctx, apierror.New(http.StatusUnauthorized, "unauthorized"))
 		return
 	}
 	user, ok := u.(db.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, newErrorResponse("invalid user type"))
+		respondError(ctx, apierror.Internal(nil, "invalid user type"))
 		return
 	}
 
-	var req UpdateUserPasswordRequest
+	var req apitypes.UpdateUserPasswordRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(ctx, badRequest(err))
 		return
 	}
 
@@ -182,18 +192,18 @@ func (server *Server) UpdateUserPassword(ctx *gin.Context) {
 	req.ConfirmPassword = strings.TrimSpace(req.ConfirmPassword)
 
 	if req.CurrentPassword == req.NewPassword {
-		ctx.JSON(http.StatusBadRequest, newErrorResponse("new password must be different from current password"))
+		respondError(ctx, apierror.New(http.StatusBadRequest, "new password must be different from current password"))
 		return
 	}
 
 	if err := util.CheckPassword(req.CurrentPassword, user.Password); err != nil {
-		ctx.JSON(http.StatusForbidden, newErrorResponse("invalid credentials"))
+		respondError(ctx, apierror.New(http.StatusForbidden, "invalid credentials"))
 		return
 	}
 
 	newHashedPassword, err := util.HashPassword(req.NewPassword)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(ctx, err)
 		return
 	}
 
@@ -203,9 +213,9 @@ func (server *Server) UpdateUserPassword(ctx *gin.Context) {
 	})
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, newErrorResponse("update password failed."))
+		respondError(ctx, apierror.Internal(err, "update password failed."))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"message": "update password successfully."})
+	ctx.JSON(http.StatusOK, apitypes.MessageResponse{Message: "update password successfully."})
 }
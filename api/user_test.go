@@ -0,0 +1,38 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/sangketkit01/personal-financial/apierror"
+	"github.com/sangketkit01/personal-financial/util"
+	"github.com/stretchr/testify/require"
+)
+
+const testCreateUserBody = `{"username":"alice","name":"Alice","email":"alice@example.com","phone":"0812345678","password":"secret-password"}`
+
+func TestCreateUser(t *testing.T) {
+	server := newTestServer(t, newFakeStore(), util.Config{})
+
+	recorder := serveJSON(server.createUser, nil, http.MethodPost, "/users", testCreateUserBody)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `"username":"alice"`)
+}
+
+func TestCreateUserStoreFailure(t *testing.T) {
+	store := newFakeStore()
+	store.createUserErr = errors.New("connection reset")
+	server := newTestServer(t, store, util.Config{})
+
+	recorder := serveJSON(server.createUser, nil, http.MethodPost, "/users", testCreateUserBody)
+	require.Equal(t, http.StatusInternalServerError, recorder.Code)
+
+	// one problem and nothing written after it
+	var problem apierror.Problem
+	decoder := json.NewDecoder(recorder.Body)
+	require.NoError(t, decoder.Decode(&problem))
+	require.Equal(t, apierror.CodeInternal, problem.Code)
+	require.False(t, decoder.More())
+}
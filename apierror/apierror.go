@@ -0,0 +1,135 @@
+// Package apierror holds the error codes of the API and the RFC 7807 problem details they are written as.
+// It only depends on the standard library, the server and the client share it.
+package apierror
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// ContentType is the media type of problem details.
+const ContentType = "application/problem+json"
+
+// Code is the stable, machine readable name of an error. Clients switch on it, the detail text may change.
+type Code string
+
+const (
+	CodeBadRequest          Code = "bad_request"
+	CodeInvalidBody         Code = "invalid_body"
+	CodeValidation          Code = "validation_failed"
+	CodeUnauthorized        Code = "unauthorized"
+	CodeInvalidToken        Code = "invalid_token"
+	CodeTokenExpired        Code = "token_expired"
+	CodeForbidden           Code = "forbidden"
+	CodeNotFound            Code = "not_found"
+	CodeConflict            Code = "conflict"
+	CodeTooManyRequests     Code = "too_many_requests"
+	CodeInternal            Code = "internal_error"
+	CodeBadGateway          Code = "bad_gateway"
+	CodeUnavailable         Code = "service_unavailable"
+	CodePeriodClosed        Code = "period_closed"
+	CodeReconciled          Code = "financial_reconciled"
+	CodeReconcileLocked     Code = "reconciliation_locked"
+	CodeReconcileUnbalanced Code = "reconciliation_unbalanced"
+	CodeSettlementAnswered  Code = "settlement_not_pending"
+)
+
+// Error is an error with the response it turns into. Err is the cause, it is logged but never sent.
+type Error struct {
+	Status int
+	Code   Code
+	Detail string
+	Fields []FieldError
+	Err    error
+}
+
+// FieldError tells what is wrong with one field of the request.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Detail, e.Err)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Detail)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New makes an error with the generic code of the status.
+func New(status int, detail string) *Error {
+	return &Error{Status: status, Code: statusCode(status), Detail: detail}
+}
+
+// Internal is a server side failure, detail is shown and the cause only logged.
+func Internal(cause error, detail string) *Error {
+	return &Error{Status: http.StatusInternalServerError, Code: CodeInternal, Detail: detail, Err: cause}
+}
+
+// WithCode replaces the code of the error.
+func (e *Error) WithCode(code Code) *Error {
+	e.Code = code
+	return e
+}
+
+func statusCode(status int) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusTooManyRequests:
+		return CodeTooManyRequests
+	case http.StatusBadGateway:
+		return CodeBadGateway
+	case http.StatusServiceUnavailable:
+		return CodeUnavailable
+	}
+
+	if status >= http.StatusInternalServerError {
+		return CodeInternal
+	}
+	return CodeBadRequest
+}
+
+// Problem is an RFC 7807 problem details object, with the error code, request id and field errors as extensions.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      Code         `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// TypeURI identifies the kind of problem, one per code.
+func TypeURI(code Code) string {
+	return "urn:personal-financial:problem:" + string(code)
+}
+
+// Problem builds the response body, instance is the path of the request.
+func (e *Error) Problem(instance string, requestID string) Problem {
+	return Problem{
+		Type:      TypeURI(e.Code),
+		Title:     http.StatusText(e.Status),
+		Status:    e.Status,
+		Detail:    e.Detail,
+		Instance:  instance,
+		Code:      e.Code,
+		RequestID: requestID,
+		Errors:    e.Fields,
+	}
+}
@@ -0,0 +1,26 @@
+package apitypes
+
+import "time"
+
+type PersonalAccessTokenResponse struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiredAt  *time.Time `json:"expired_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type CreatePersonalAccessTokenRequest struct {
+	Name          string   `json:"name" binding:"required"`
+	Scopes        []string `json:"scopes" binding:"required,min=1,dive,oneof=read:financial write:financial read:budget write:budget admin"`
+	ExpiresInDays int      `json:"expires_in_days" binding:"omitempty,min=1,max=3650"`
+}
+
+// CreatePersonalAccessTokenResponse holds the only copy of the token, it cannot be shown again.
+type CreatePersonalAccessTokenResponse struct {
+	Token   string                      `json:"token"`
+	Details PersonalAccessTokenResponse `json:"details"`
+}
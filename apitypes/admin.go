@@ -0,0 +1,50 @@
+package apitypes
+
+import "time"
+
+type AdminUserResponse struct {
+	ProfileResponse
+	Role      string     `json:"role"`
+	Disabled  bool       `json:"disabled"`
+	RevokedAt *time.Time `json:"tokens_revoked_at,omitempty"`
+}
+
+type FinancialTypeRequest struct {
+	Type string `json:"type" binding:"required,alpha"`
+}
+
+type SearchUsersRequest struct {
+	Query  string `form:"q"`
+	Limit  int32  `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int32  `form:"offset" binding:"omitempty,min=0"`
+}
+
+type SetUserRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user admin"`
+}
+
+type ListAdminAuditsRequest struct {
+	Limit  int32 `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int32 `form:"offset" binding:"omitempty,min=0"`
+}
+
+// AdminAudit is an action of an admin, Details is the JSON the action was done with.
+type AdminAudit struct {
+	ID        int64     `json:"id"`
+	Admin     string    `json:"admin"`
+	Action    string    `json:"action"`
+	Target    string    `json:"target"`
+	Details   []byte    `json:"details"`
+	ClientIp  string    `json:"client_ip"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SystemStats struct {
+	TotalUsers           int64 `json:"total_users"`
+	DisabledUsers        int64 `json:"disabled_users"`
+	DeletedUsers         int64 `json:"deleted_users"`
+	TotalFinancials      int64 `json:"total_financials"`
+	FinancialsLast30Days int64 `json:"financials_last_30_days"`
+	TotalBudgets         int64 `json:"total_budgets"`
+	TotalHouseholds      int64 `json:"total_households"`
+}
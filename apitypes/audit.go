@@ -0,0 +1,17 @@
+package apitypes
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditEventResponse struct {
+	ID        int64           `json:"id"`
+	Actor     string          `json:"actor"`
+	Action    string          `json:"action"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	IP        string          `json:"ip"`
+	UserAgent string          `json:"user_agent"`
+	CreatedAt time.Time       `json:"created_at"`
+}
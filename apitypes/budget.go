@@ -0,0 +1,32 @@
+package apitypes
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type BudgetRequest struct {
+	Amount int64 `json:"amount" binding:"required,min=1"`
+}
+
+type BudgetHistoryRequest struct {
+	Year int `json:"year" uri:"year" binding:"required,min=2000"`
+}
+
+type CheckBudgetUsageResponse struct {
+	Budget       float64 `json:"budget"`
+	Spent        int     `json:"spent"`
+	UsagePercent string  `json:"usage"`
+}
+
+// Budget is the budget of a month, HouseholdID is set for a household budget.
+type Budget struct {
+	ID          int32       `json:"id"`
+	UserID      string      `json:"user_id"`
+	Month       int32       `json:"month"`
+	Year        int32       `json:"year"`
+	Amount      json.Number `json:"amount"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+	HouseholdID *int64      `json:"household_id"`
+}
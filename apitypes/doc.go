@@ -0,0 +1,4 @@
+// Package apitypes holds the request and response bodies of the HTTP API.
+// It only uses the standard library, so clients can share the types with the server
+// without pulling in its dependencies. Nullable columns are pointers, nil is sent as null.
+package apitypes
@@ -0,0 +1,118 @@
+package apitypes
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type NewFinancialRequest struct {
+	Amount      int64  `json:"amount" binding:"required"`
+	Type        string `json:"type" binding:"required,alpha"`
+	HouseholdID int64  `json:"household_id" binding:"omitempty,min=1"`
+}
+
+// NewFinancialResponse tells how much of the budget is used after the record and whether it looks unusual.
+type NewFinancialResponse struct {
+	Message   string    `json:"message"`
+	Financial Financial `json:"financial"`
+	Usage     string    `json:"usage"`
+	Anomalies []Anomaly `json:"anomalies"`
+}
+
+type UpdateFinancialResponse struct {
+	Message          string    `json:"message"`
+	UpdatedFinancial Financial `json:"updated_financial"`
+}
+
+type UpdateFinancialRequest struct {
+	Amount int64  `json:"amount" binding:"required"`
+	Type   string `json:"type" binding:"required,alpha"`
+}
+
+type DeleteFinancialResponse struct {
+	Message          string    `json:"message"`
+	DeletedFinancial Financial `json:"deleted_financial"`
+}
+
+type YearMonthRequest struct {
+	Year  int `json:"year" uri:"year" binding:"required,min=2020"`
+	Month int `json:"month" uri:"month" binding:"required,min=1,max=12"`
+}
+
+type YearRequest struct {
+	Year int `json:"year" uri:"year" binding:"required,min=2020"`
+}
+
+// Financial is an income or expense record as it is stored.
+type Financial struct {
+	ID               int64      `json:"id"`
+	UserID           string     `json:"user_id"`
+	Amount           int64      `json:"amount"`
+	Direction        string     `json:"direction"`
+	TypeID           int64      `json:"type_id"`
+	CreatedAt        time.Time  `json:"created_at"`
+	HouseholdID      *int64     `json:"household_id"`
+	DeletedAt        *time.Time `json:"deleted_at"`
+	ReconcileStatus  string     `json:"reconcile_status"`
+	ReconciliationID *int64     `json:"reconciliation_id"`
+}
+
+// FinancialEntry is a record in a listing, with the name of its type.
+type FinancialEntry struct {
+	ID        int64     `json:"id"`
+	Amount    int64     `json:"amount"`
+	Direction string    `json:"direction"`
+	Type      *string   `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type FinancialType struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"`
+}
+
+// FinancialSummary is the total of a month or year, Status is in, out or equal.
+type FinancialSummary struct {
+	TotalIncome  int64  `json:"total_income"`
+	TotalExpense int64  `json:"total_expense"`
+	Status       string `json:"status"`
+}
+
+// TypeSummary is the total of a type in a month or year.
+type TypeSummary struct {
+	Type         string `json:"type"`
+	TotalIncome  int64  `json:"total_income"`
+	TotalExpense int64  `json:"total_expense"`
+	Status       string `json:"status"`
+}
+
+// YearSummary is the total of a fiscal year.
+type YearSummary struct {
+	Year      int32       `json:"year"`
+	InAmount  json.Number `json:"in_amount"`
+	OutAmount json.Number `json:"out_amount"`
+	Status    string      `json:"status"`
+}
+
+type Anomaly struct {
+	ID          int64     `json:"id"`
+	UserID      string    `json:"user_id"`
+	FinancialID *int64    `json:"financial_id"`
+	TypeID      *int64    `json:"type_id"`
+	Kind        string    `json:"kind"`
+	Reason      string    `json:"reason"`
+	DedupeKey   string    `json:"dedupe_key"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// AnomalyEntry is an anomaly in a listing, with the record it was found on when it still exists.
+type AnomalyEntry struct {
+	ID          int64     `json:"id"`
+	FinancialID *int64    `json:"financial_id"`
+	Kind        string    `json:"kind"`
+	Reason      string    `json:"reason"`
+	CreatedAt   time.Time `json:"created_at"`
+	Amount      *int64    `json:"amount"`
+	Direction   *string   `json:"direction"`
+	Type        *string   `json:"type"`
+}
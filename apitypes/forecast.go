@@ -0,0 +1,34 @@
+package apitypes
+
+import "time"
+
+type ForecastRequest struct {
+	Months      int    `form:"months" binding:"omitempty,min=1,max=24"`
+	History     int    `form:"history" binding:"omitempty,min=6,max=12"`
+	Granularity string `form:"granularity" binding:"omitempty,oneof=daily monthly"`
+}
+
+// ForecastResponse is the projected balance, NegativePeriods are the starts of the points below zero.
+type ForecastResponse struct {
+	OpeningBalance  int64              `json:"opening_balance"`
+	Granularity     string             `json:"granularity"`
+	HistoryMonths   int                `json:"history_months"`
+	Baseline        []CategoryBaseline `json:"baseline"`
+	Points          []ForecastPoint    `json:"points"`
+	NegativePeriods []time.Time        `json:"negative_periods"`
+}
+
+type CategoryBaseline struct {
+	Type    string `json:"type"`
+	Income  int64  `json:"income"`
+	Expense int64  `json:"expense"`
+}
+
+type ForecastPoint struct {
+	Start    time.Time `json:"start"`
+	Income   int64     `json:"income"`
+	Expense  int64     `json:"expense"`
+	Net      int64     `json:"net"`
+	Balance  int64     `json:"balance"`
+	Negative bool      `json:"negative"`
+}
@@ -0,0 +1,9 @@
+package apitypes
+
+type ReadyResponse struct {
+	Status           string `json:"status"`
+	Database         string `json:"database"`
+	MigrationVersion int64  `json:"migration_version"`
+	ExpectedVersion  int64  `json:"expected_version"`
+	Dirty            bool   `json:"dirty"`
+}
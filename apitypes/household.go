@@ -0,0 +1,88 @@
+package apitypes
+
+import "time"
+
+type CreateHouseholdRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type HouseholdResponse struct {
+	Household Household              `json:"household"`
+	Members   []HouseholdMemberEntry `json:"members"`
+}
+
+type InviteHouseholdMemberRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=editor viewer"`
+}
+
+type InvitationResponse struct {
+	Message   string    `json:"message"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	ExpiredAt time.Time `json:"expired_at"`
+}
+
+type JoinHouseholdRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type UpdateHouseholdMemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=editor viewer"`
+}
+
+type HouseholdSummaryResponse struct {
+	Month        int                      `json:"month"`
+	Year         int                      `json:"year"`
+	TotalExpense int64                    `json:"total_expense"`
+	Members      []HouseholdMemberSummary `json:"members"`
+}
+
+type HouseholdMemberSummary struct {
+	Username     string  `json:"username"`
+	TotalIncome  int64   `json:"total_income"`
+	TotalExpense int64   `json:"total_expense"`
+	FairShare    float64 `json:"fair_share"`
+	Balance      float64 `json:"balance"`
+}
+
+type Household struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Owner     string    `json:"owner"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MyHousehold is a household of the caller with the caller's role in it.
+type MyHousehold struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Owner     string    `json:"owner"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type HouseholdMember struct {
+	HouseholdID int64     `json:"household_id"`
+	Username    string    `json:"username"`
+	Role        string    `json:"role"`
+	JoinedAt    time.Time `json:"joined_at"`
+}
+
+// HouseholdMemberEntry is a member in a listing, with the member's name.
+type HouseholdMemberEntry struct {
+	Username string    `json:"username"`
+	Name     string    `json:"name"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// HouseholdFinancial is a record of the household, UserID is the member who made it.
+type HouseholdFinancial struct {
+	ID        int64     `json:"id"`
+	UserID    string    `json:"user_id"`
+	Amount    int64     `json:"amount"`
+	Direction string    `json:"direction"`
+	Type      *string   `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+}
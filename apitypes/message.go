@@ -0,0 +1,6 @@
+package apitypes
+
+// MessageResponse is the answer of requests that only report that they succeeded.
+type MessageResponse struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,29 @@
+package apitypes
+
+import "time"
+
+type PeriodRequest struct {
+	Month int32 `json:"month" uri:"month" binding:"required,min=1,max=12"`
+	Year  int32 `json:"year" uri:"year" binding:"required,min=1970"`
+}
+
+type ClosedPeriodResponse struct {
+	PeriodClosing
+	CurrentIncome  int64 `json:"current_income"`
+	CurrentExpense int64 `json:"current_expense"`
+	IncomeDrift    int64 `json:"income_drift"`
+	ExpenseDrift   int64 `json:"expense_drift"`
+}
+
+// PeriodClosing keeps the totals of a month as they were when it was closed.
+type PeriodClosing struct {
+	ID           int64      `json:"id"`
+	UserID       string     `json:"user_id"`
+	Month        int32      `json:"month"`
+	Year         int32      `json:"year"`
+	TotalIncome  int64      `json:"total_income"`
+	TotalExpense int64      `json:"total_expense"`
+	Status       string     `json:"status"`
+	ClosedAt     time.Time  `json:"closed_at"`
+	ReopenedAt   *time.Time `json:"reopened_at"`
+}
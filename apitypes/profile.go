@@ -0,0 +1,55 @@
+package apitypes
+
+import "time"
+
+type ProfileResponse struct {
+	Username          string     `json:"username"`
+	Name              string     `json:"name"`
+	Email             string     `json:"email"`
+	EmailVerified     bool       `json:"email_verified"`
+	Phone             string     `json:"phone"`
+	TimeZone          string     `json:"time_zone"`
+	Locale            string     `json:"locale"`
+	PeriodStartDay    int32      `json:"period_start_day"`
+	FiscalStartMonth  int32      `json:"fiscal_year_start_month"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	DeletionScheduled *time.Time `json:"deletion_scheduled_at,omitempty"`
+}
+
+type UpdateProfileRequest struct {
+	Name *string `json:"name" binding:"omitempty,min=1"`
+	// Email sends a code to the address, it replaces the current one once verified.
+	// Sending the current address verifies it when it is not yet.
+	Email *string `json:"email" binding:"omitempty,email"`
+	Phone *string `json:"phone" binding:"omitempty,min=10,max=10"`
+	// TimeZone is an IANA name like Asia/Bangkok, months and years of the user are cut in it
+	TimeZone *string `json:"time_zone"`
+	// Locale is a BCP 47 tag like th-TH, used to format numbers and dates in notifications
+	Locale *string `json:"locale"`
+	// PeriodStartDay is the day budgeting months start on, e.g. 25 when paid on the 25th
+	PeriodStartDay *int32 `json:"period_start_day" binding:"omitempty,min=1,max=28"`
+	// FiscalStartMonth is the month fiscal years start with
+	FiscalStartMonth *int32 `json:"fiscal_year_start_month" binding:"omitempty,min=1,max=12"`
+}
+
+// UpdateProfileResponse lists the changed fields that wait for a verification code.
+type UpdateProfileResponse struct {
+	Profile             ProfileResponse `json:"profile"`
+	PendingVerification []string        `json:"pending_verification"`
+}
+
+type VerifyContactRequest struct {
+	Code string `json:"code" binding:"required,numeric,len=6"`
+}
+
+// DeleteAccountResponse tells when the account is purged unless the user logs in again before.
+type DeleteAccountResponse struct {
+	Message string          `json:"message"`
+	PurgeAt time.Time       `json:"purge_at"`
+	Profile ProfileResponse `json:"profile"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
@@ -0,0 +1,30 @@
+package apitypes
+
+// SummaryQuery is the query of the summary, from and to are dates in YYYY-MM-DD.
+type SummaryQuery struct {
+	Bucket  string `form:"bucket" binding:"omitempty,oneof=day week month quarter year"`
+	GroupBy string `form:"group_by" binding:"omitempty,oneof=type"`
+	TZ      string `form:"tz"`
+	From    string `form:"from"`
+	To      string `form:"to"`
+}
+
+// CompareQuery is the query of the period comparison, dates in YYYY-MM-DD.
+type CompareQuery struct {
+	From        string `form:"from"`
+	To          string `form:"to"`
+	CompareFrom string `form:"compare_from"`
+	CompareTo   string `form:"compare_to"`
+}
+
+// TrendQuery is the query of the trend, months in YYYY-MM.
+type TrendQuery struct {
+	From string `form:"from"`
+	To   string `form:"to"`
+}
+
+// MonthYearQuery picks the month of the household summary and budget, the current one by default.
+type MonthYearQuery struct {
+	Month int `form:"month" binding:"omitempty,min=1,max=12"`
+	Year  int `form:"year" binding:"omitempty,min=2000"`
+}
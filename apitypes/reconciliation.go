@@ -0,0 +1,47 @@
+package apitypes
+
+import "time"
+
+type ReconciliationResponse struct {
+	ID                int64      `json:"id"`
+	StatementDate     string     `json:"statement_date"`
+	StatementBalance  int64      `json:"statement_balance"`
+	ReconciledBalance int64      `json:"reconciled_balance"`
+	ClearedBalance    int64      `json:"cleared_balance"`
+	ClearedCount      int64      `json:"cleared_count"`
+	Difference        int64      `json:"difference"`
+	Locked            bool       `json:"locked"`
+	LockedAt          *time.Time `json:"locked_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+type CreateReconciliationRequest struct {
+	StatementDate    string `json:"statement_date" binding:"required"`
+	StatementBalance *int64 `json:"statement_balance" binding:"required"`
+}
+
+// ReconciliationDetailResponse has the records that can still be cleared, none once it is locked.
+type ReconciliationDetailResponse struct {
+	Reconciliation ReconciliationResponse    `json:"reconciliation"`
+	Financials     []ReconciliationCandidate `json:"financials"`
+}
+
+type ClearFinancialsRequest struct {
+	FinancialIDs []int64 `json:"financial_ids" binding:"required,min=1,dive,min=1"`
+}
+
+// ClearFinancialsResponse lists the ids of the records that changed.
+type ClearFinancialsResponse struct {
+	Updated        []int64                `json:"updated"`
+	Reconciliation ReconciliationResponse `json:"reconciliation"`
+}
+
+// ReconciliationCandidate is a record that can be cleared in a reconciliation.
+type ReconciliationCandidate struct {
+	ID              int64     `json:"id"`
+	Amount          int64     `json:"amount"`
+	Direction       string    `json:"direction"`
+	Type            *string   `json:"type"`
+	CreatedAt       time.Time `json:"created_at"`
+	ReconcileStatus string    `json:"reconcile_status"`
+}
@@ -0,0 +1,50 @@
+package apitypes
+
+type Totals struct {
+	Income  int64 `json:"income"`
+	Expense int64 `json:"expense"`
+	Net     int64 `json:"net"`
+}
+
+type Delta struct {
+	Absolute int64 `json:"absolute"`
+	// Percent is nil when the previous value is zero
+	Percent *float64 `json:"percent"`
+}
+
+type CategoryComparison struct {
+	Type         string `json:"type"`
+	Current      Totals `json:"current"`
+	Previous     Totals `json:"previous"`
+	IncomeDelta  Delta  `json:"income_delta"`
+	ExpenseDelta Delta  `json:"expense_delta"`
+	NetDelta     Delta  `json:"net_delta"`
+}
+
+type Period struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type CompareResponse struct {
+	Current    Period               `json:"current"`
+	Previous   Period               `json:"previous"`
+	Categories []CategoryComparison `json:"categories"`
+	Total      CategoryComparison   `json:"total"`
+}
+
+type TrendPoint struct {
+	Month string `json:"month"`
+	Totals
+}
+
+type CategoryTrend struct {
+	Type   string       `json:"type"`
+	Points []TrendPoint `json:"points"`
+}
+
+type TrendResponse struct {
+	From       string          `json:"from"`
+	To         string          `json:"to"`
+	Categories []CategoryTrend `json:"categories"`
+}
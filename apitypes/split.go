@@ -0,0 +1,111 @@
+package apitypes
+
+import "time"
+
+// Participant is either a registered user or one of the caller's named contacts.
+type Participant struct {
+	Username  string `json:"username,omitempty"`
+	ContactID int64  `json:"contact_id,omitempty"`
+}
+
+type CreateContactRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Email string `json:"email" binding:"omitempty,email"`
+}
+
+type SplitParticipantRequest struct {
+	Participant
+	Shares int64 `json:"shares" binding:"omitempty,min=0"`
+	Amount int64 `json:"amount" binding:"omitempty,min=0"`
+}
+
+type CreateSharedExpenseRequest struct {
+	Description  string                    `json:"description" binding:"required"`
+	Amount       int64                     `json:"amount" binding:"required,min=1"`
+	PaidBy       Participant               `json:"paid_by"`
+	SplitMethod  string                    `json:"split_method" binding:"required,oneof=equal shares exact"`
+	Participants []SplitParticipantRequest `json:"participants" binding:"required,min=1,dive"`
+}
+
+type SharedExpenseResponse struct {
+	SharedExpense
+	Shares []SharedExpenseShare `json:"shares"`
+}
+
+// SharedExpenseResult is a new shared expense with the share of every participant.
+type SharedExpenseResult struct {
+	Expense SharedExpense        `json:"expense"`
+	Shares  []SharedExpenseShare `json:"shares"`
+}
+
+type PairBalance struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Amount int64  `json:"amount"`
+}
+
+// SplitBalancesResponse has the balance of every pair, the net balance by person and the payments that settle them.
+type SplitBalancesResponse struct {
+	Balances []PairBalance    `json:"balances"`
+	Net      map[string]int64 `json:"net"`
+	SettleUp []Transfer       `json:"settle_up"`
+}
+
+// Transfer is a payment that settles balances.
+type Transfer struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Amount int64  `json:"amount"`
+}
+
+type CreateSettlementRequest struct {
+	From   Participant `json:"from"`
+	To     Participant `json:"to"`
+	Amount int64       `json:"amount" binding:"required,min=1"`
+}
+
+// Contact is a named person of the owner who is not registered, or whose account is not used.
+type Contact struct {
+	ID        int64     `json:"id"`
+	Owner     string    `json:"owner"`
+	Name      string    `json:"name"`
+	Email     *string   `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SharedExpense was paid by either a user or a contact.
+type SharedExpense struct {
+	ID            int64     `json:"id"`
+	CreatedBy     string    `json:"created_by"`
+	Description   string    `json:"description"`
+	Amount        int64     `json:"amount"`
+	PaidByUser    *string   `json:"paid_by_user"`
+	PaidByContact *int64    `json:"paid_by_contact"`
+	SplitMethod   string    `json:"split_method"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// SharedExpenseShare is what one participant owes of an expense.
+type SharedExpenseShare struct {
+	ID        int64   `json:"id"`
+	ExpenseID int64   `json:"expense_id"`
+	Username  *string `json:"username"`
+	ContactID *int64  `json:"contact_id"`
+	Amount    int64   `json:"amount"`
+}
+
+// Settlement is a settle-up payment, Status is pending until a registered counterparty confirms it.
+type Settlement struct {
+	ID              int64      `json:"id"`
+	CreatedBy       string     `json:"created_by"`
+	FromUser        *string    `json:"from_user"`
+	FromContact     *int64     `json:"from_contact"`
+	ToUser          *string    `json:"to_user"`
+	ToContact       *int64     `json:"to_contact"`
+	Amount          int64      `json:"amount"`
+	FromFinancialID *int64     `json:"from_financial_id"`
+	ToFinancialID   *int64     `json:"to_financial_id"`
+	CreatedAt       time.Time  `json:"created_at"`
+	Status          string     `json:"status"`
+	RespondedAt     *time.Time `json:"responded_at"`
+}
@@ -0,0 +1,23 @@
+package apitypes
+
+import "time"
+
+type SummaryResponse struct {
+	From     string          `json:"from"`
+	To       string          `json:"to"`
+	Bucket   string          `json:"bucket"`
+	TimeZone string          `json:"time_zone"`
+	GroupBy  string          `json:"group_by"`
+	Series   []SummarySeries `json:"series"`
+}
+
+type SummaryPoint struct {
+	Bucket string    `json:"bucket"`
+	Start  time.Time `json:"start"`
+	Totals
+}
+
+type SummarySeries struct {
+	Group  string         `json:"group"`
+	Points []SummaryPoint `json:"points"`
+}
@@ -0,0 +1,23 @@
+package apitypes
+
+import "time"
+
+// TrashResponse lists the deleted records, they are purged RetentionDays after their deletion.
+type TrashResponse struct {
+	RetentionDays int                `json:"retention_days"`
+	Financials    []DeletedFinancial `json:"financials"`
+}
+
+type RestoreFinancialResponse struct {
+	Message           string    `json:"message"`
+	RestoredFinancial Financial `json:"restored_financial"`
+}
+
+type DeletedFinancial struct {
+	ID        int64      `json:"id"`
+	Amount    int64      `json:"amount"`
+	Direction string     `json:"direction"`
+	Type      *string    `json:"type"`
+	CreatedAt time.Time  `json:"created_at"`
+	DeletedAt *time.Time `json:"deleted_at"`
+}
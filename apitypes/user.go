@@ -0,0 +1,52 @@
+package apitypes
+
+import "time"
+
+type CreateUserRequest struct {
+	Username string `json:"username" binding:"required"`
+	Name     string `json:"name" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
+	Phone    string `json:"phone" binding:"required,min=10,max=10"`
+	Password string `json:"password" binding:"required,min=8"`
+}
+
+type CreateUserResponse struct {
+	Username  string    `json:"username"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Phone     string    `json:"phone"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type LoginUserRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required,min=8"`
+}
+
+type LoginUserRespose struct {
+	Username    string    `json:"username"`
+	Name        string    `json:"name"`
+	Email       string    `json:"email"`
+	Phone       string    `json:"phone"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	TokenID     string    `json:"token_id"`
+	AccessToken string    `json:"access_token"`
+	IssuedAt    time.Time `json:"issued_at"`
+	ExpiredAt   time.Time `json:"expired_at"`
+}
+
+type UpdateUserPasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=8,alphanum"`
+	ConfirmPassword string `json:"confirm_password" binding:"required,eqField=NewPassword"`
+}
+
+type RequestUnlockRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type UnlockAccountRequest struct {
+	Token string `json:"token" binding:"required"`
+}
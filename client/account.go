@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/sangketkit01/personal-financial/apitypes"
+)
+
+func (client *Client) GetProfile(ctx context.Context) (apitypes.ProfileResponse, error) {
+	var profile apitypes.ProfileResponse
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/me"}, &profile)
+	return profile, err
+}
+
+func (client *Client) UpdateProfile(ctx context.Context, req apitypes.UpdateProfileRequest) (apitypes.UpdateProfileResponse, error) {
+	var response apitypes.UpdateProfileResponse
+	err := client.do(ctx, request{method: http.MethodPatch, path: APIPrefix + "/me", body: req}, &response)
+	return response, err
+}
+
+func (client *Client) DeleteAccount(ctx context.Context, req apitypes.DeleteAccountRequest) (apitypes.DeleteAccountResponse, error) {
+	var response apitypes.DeleteAccountResponse
+	err := client.do(ctx, request{method: http.MethodDelete, path: APIPrefix + "/me", body: req}, &response)
+	return response, err
+}
+
+func (client *Client) VerifyContact(ctx context.Context, req apitypes.VerifyContactRequest) (apitypes.ProfileResponse, error) {
+	var profile apitypes.ProfileResponse
+	err := client.do(ctx, request{method: http.MethodPost, path: APIPrefix + "/me/verify", body: req}, &profile)
+	return profile, err
+}
+
+// UpdatePassword changes the password. A client that logs in by itself keeps the old one, create a new client afterwards.
+func (client *Client) UpdatePassword(ctx context.Context, req apitypes.UpdateUserPasswordRequest) (apitypes.MessageResponse, error) {
+	var response apitypes.MessageResponse
+	err := client.do(ctx, request{method: http.MethodPut, path: APIPrefix + "/me/password", body: req}, &response)
+	return response, err
+}
+
+func (client *Client) CreatePersonalAccessToken(ctx context.Context, req apitypes.CreatePersonalAccessTokenRequest) (apitypes.CreatePersonalAccessTokenResponse, error) {
+	var response apitypes.CreatePersonalAccessTokenResponse
+	err := client.do(ctx, request{method: http.MethodPost, path: APIPrefix + "/me/tokens", body: req}, &response)
+	return response, err
+}
+
+func (client *Client) ListPersonalAccessTokens(ctx context.Context) ([]apitypes.PersonalAccessTokenResponse, error) {
+	var tokens []apitypes.PersonalAccessTokenResponse
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/me/tokens"}, &tokens)
+	return tokens, err
+}
+
+func (client *Client) RevokePersonalAccessToken(ctx context.Context, id int64) (apitypes.PersonalAccessTokenResponse, error) {
+	var token apitypes.PersonalAccessTokenResponse
+	err := client.do(ctx, request{method: http.MethodDelete, path: APIPrefix + "/me/tokens/" + pathID(id)}, &token)
+	return token, err
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/sangketkit01/personal-financial/apitypes"
+)
+
+func adminUserPath(username string) string {
+	return APIPrefix + "/admin/users/" + url.PathEscape(username)
+}
+
+func (client *Client) AdminListFinancialTypes(ctx context.Context) ([]apitypes.FinancialType, error) {
+	var types []apitypes.FinancialType
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/admin/financial-types"}, &types)
+	return types, err
+}
+
+func (client *Client) AdminCreateFinancialType(ctx context.Context, req apitypes.FinancialTypeRequest) (apitypes.FinancialType, error) {
+	var financialType apitypes.FinancialType
+	err := client.do(ctx, request{method: http.MethodPost, path: APIPrefix + "/admin/financial-types", body: req}, &financialType)
+	return financialType, err
+}
+
+func (client *Client) AdminUpdateFinancialType(ctx context.Context, id int64, req apitypes.FinancialTypeRequest) (apitypes.FinancialType, error) {
+	var financialType apitypes.FinancialType
+	err := client.do(ctx, request{method: http.MethodPut, path: APIPrefix + "/admin/financial-types/" + pathID(id), body: req}, &financialType)
+	return financialType, err
+}
+
+func (client *Client) AdminDeleteFinancialType(ctx context.Context, id int64) (apitypes.FinancialType, error) {
+	var financialType apitypes.FinancialType
+	err := client.do(ctx, request{method: http.MethodDelete, path: APIPrefix + "/admin/financial-types/" + pathID(id)}, &financialType)
+	return financialType, err
+}
+
+func (client *Client) AdminSearchUsers(ctx context.Context, query apitypes.SearchUsersRequest) ([]apitypes.AdminUserResponse, error) {
+	var users []apitypes.AdminUserResponse
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/admin/users", query: queryValues(query)}, &users)
+	return users, err
+}
+
+func (client *Client) AdminGetUser(ctx context.Context, username string) (apitypes.AdminUserResponse, error) {
+	var user apitypes.AdminUserResponse
+	err := client.do(ctx, request{method: http.MethodGet, path: adminUserPath(username)}, &user)
+	return user, err
+}
+
+func (client *Client) AdminSetUserRole(ctx context.Context, username string, req apitypes.SetUserRoleRequest) (apitypes.AdminUserResponse, error) {
+	var user apitypes.AdminUserResponse
+	err := client.do(ctx, request{method: http.MethodPut, path: adminUserPath(username) + "/role", body: req}, &user)
+	return user, err
+}
+
+func (client *Client) AdminDisableUser(ctx context.Context, username string) (apitypes.AdminUserResponse, error) {
+	var user apitypes.AdminUserResponse
+	err := client.do(ctx, request{method: http.MethodPost, path: adminUserPath(username) + "/disable"}, &user)
+	return user, err
+}
+
+func (client *Client) AdminEnableUser(ctx context.Context, username string) (apitypes.AdminUserResponse, error) {
+	var user apitypes.AdminUserResponse
+	err := client.do(ctx, request{method: http.MethodPost, path: adminUserPath(username) + "/enable"}, &user)
+	return user, err
+}
+
+func (client *Client) AdminRevokeSessions(ctx context.Context, username string) (apitypes.AdminUserResponse, error) {
+	var user apitypes.AdminUserResponse
+	err := client.do(ctx, request{method: http.MethodPost, path: adminUserPath(username) + "/revoke-sessions"}, &user)
+	return user, err
+}
+
+func (client *Client) AdminStats(ctx context.Context) (apitypes.SystemStats, error) {
+	var stats apitypes.SystemStats
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/admin/stats"}, &stats)
+	return stats, err
+}
+
+func (client *Client) AdminListAudits(ctx context.Context, query apitypes.ListAdminAuditsRequest) ([]apitypes.AdminAudit, error) {
+	var audits []apitypes.AdminAudit
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/admin/audits", query: queryValues(query)}, &audits)
+	return audits, err
+}
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/sangketkit01/personal-financial/apitypes"
+)
+
+func (client *Client) CreateUser(ctx context.Context, req apitypes.CreateUserRequest) (apitypes.CreateUserResponse, error) {
+	var user apitypes.CreateUserResponse
+	err := client.do(ctx, request{method: http.MethodPost, path: APIPrefix + "/users", body: req, public: true}, &user)
+	return user, err
+}
+
+// Login opens a session. Later calls use its token, and the credentials are kept to log in again when it expires.
+func (client *Client) Login(ctx context.Context, req apitypes.LoginUserRequest) (apitypes.LoginUserRespose, error) {
+	session, err := client.login(ctx, req.Username, req.Password)
+	if err != nil {
+		return session, err
+	}
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	client.username = req.Username
+	client.password = req.Password
+	client.token = session.AccessToken
+	client.expiredAt = session.ExpiredAt
+	return session, nil
+}
+
+func (client *Client) login(ctx context.Context, username string, password string) (apitypes.LoginUserRespose, error) {
+	var session apitypes.LoginUserRespose
+	err := client.do(ctx, request{
+		method: http.MethodPost,
+		path:   APIPrefix + "/sessions",
+		body:   apitypes.LoginUserRequest{Username: username, Password: password},
+		public: true,
+	}, &session)
+	return session, err
+}
+
+func (client *Client) RequestUnlock(ctx context.Context, req apitypes.RequestUnlockRequest) (apitypes.MessageResponse, error) {
+	var response apitypes.MessageResponse
+	err := client.do(ctx, request{method: http.MethodPost, path: APIPrefix + "/unlock-requests", body: req, public: true}, &response)
+	return response, err
+}
+
+func (client *Client) UnlockAccount(ctx context.Context, req apitypes.UnlockAccountRequest) (apitypes.MessageResponse, error) {
+	var response apitypes.MessageResponse
+	err := client.do(ctx, request{method: http.MethodPost, path: APIPrefix + "/unlocks", body: req, public: true}, &response)
+	return response, err
+}
+
+// OAuthLoginURL is where to send the browser to log in with the identity provider, the server redirects from there.
+func (client *Client) OAuthLoginURL() string {
+	return client.baseURL.JoinPath(APIPrefix, "/oauth/login").String()
+}
+
+// OAuthCallback finishes the identity provider login with the code and state it redirected with,
+// stateCookie is the oidc_state cookie the login set in the browser that started it.
+// The state is used up by the first try, so the call is never retried. Later calls use the token of the session,
+// it cannot be refreshed without logging in again.
+func (client *Client) OAuthCallback(ctx context.Context, code string, state string, stateCookie string) (apitypes.LoginUserRespose, error) {
+	var session apitypes.LoginUserRespose
+	err := client.do(ctx, request{
+		method:  http.MethodGet,
+		path:    APIPrefix + "/oauth/callback",
+		query:   url.Values{"code": {code}, "state": {state}},
+		cookies: []*http.Cookie{{Name: "oidc_state", Value: stateCookie}},
+		public:  true,
+		noRetry: true,
+	}, &session)
+	if err != nil {
+		return session, err
+	}
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	client.token = session.AccessToken
+	client.expiredAt = session.ExpiredAt
+	return session, nil
+}
@@ -0,0 +1,51 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/sangketkit01/personal-financial/apitypes"
+)
+
+func (client *Client) AddBudget(ctx context.Context, req apitypes.BudgetRequest) (apitypes.Budget, error) {
+	var budget apitypes.Budget
+	err := client.do(ctx, request{method: http.MethodPost, path: APIPrefix + "/budgets", body: req}, &budget)
+	return budget, err
+}
+
+func (client *Client) GetHistoryBudget(ctx context.Context) ([]apitypes.Budget, error) {
+	var budgets []apitypes.Budget
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/budgets"}, &budgets)
+	return budgets, err
+}
+
+func (client *Client) GetCurrentBudget(ctx context.Context) (apitypes.Budget, error) {
+	var budget apitypes.Budget
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/budgets/current"}, &budget)
+	return budget, err
+}
+
+func (client *Client) UpdateBudget(ctx context.Context, req apitypes.BudgetRequest) (apitypes.Budget, error) {
+	var budget apitypes.Budget
+	err := client.do(ctx, request{method: http.MethodPut, path: APIPrefix + "/budgets/current", body: req}, &budget)
+	return budget, err
+}
+
+func (client *Client) CheckBudgetUsage(ctx context.Context) (apitypes.CheckBudgetUsageResponse, error) {
+	var usage apitypes.CheckBudgetUsageResponse
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/budgets/current/usage"}, &usage)
+	return usage, err
+}
+
+func (client *Client) GetBudgetHistoryByYear(ctx context.Context, req apitypes.BudgetHistoryRequest) (apitypes.Budget, error) {
+	var budget apitypes.Budget
+	err := client.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("%s/budgets/%d", APIPrefix, req.Year)}, &budget)
+	return budget, err
+}
+
+func (client *Client) GetBudgetOfMonth(ctx context.Context, req apitypes.PeriodRequest) (apitypes.Budget, error) {
+	var budget apitypes.Budget
+	err := client.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("%s/budgets/%d/%d", APIPrefix, req.Year, req.Month)}, &budget)
+	return budget, err
+}
@@ -0,0 +1,370 @@
+// Package client is the Go SDK of the /api/v1 routes. Requests and responses are the structs of the apitypes package.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sangketkit01/personal-financial/apierror"
+)
+
+// APIPrefix is the path of the routes the client talks to.
+const APIPrefix = "/api/v1"
+
+const (
+	defaultTimeout      = 30 * time.Second
+	defaultMaxRetries   = 3
+	defaultRetryBackoff = 200 * time.Millisecond
+	defaultMaxBackoff   = 5 * time.Second
+	// refreshMargin is how long before it expires a session token is replaced.
+	refreshMargin = time.Minute
+)
+
+// Config describes how to reach and authenticate to the server.
+type Config struct {
+	// BaseURL is the address of the server without the /api/v1 prefix, e.g. http://localhost:8080.
+	BaseURL    string
+	HTTPClient *http.Client
+
+	// Token is a personal access token or an access token. It is sent as is and never refreshed.
+	Token string
+	// Username and Password log in on first use and again whenever the session token expires.
+	Username string
+	Password string
+
+	// MaxRetries is how many times a failed request is sent again, 3 when zero and none when negative.
+	// Server errors and 429 Too Many Requests are retried, a 429 only when its Retry-After is not longer than MaxBackoff.
+	MaxRetries int
+	// RetryBackoff is the wait before the first retry, doubled for each next one up to MaxBackoff.
+	RetryBackoff time.Duration
+	MaxBackoff   time.Duration
+}
+
+// Client calls the API. It is safe for concurrent use.
+type Client struct {
+	baseURL      *url.URL
+	httpClient   *http.Client
+	maxRetries   int
+	retryBackoff time.Duration
+	maxBackoff   time.Duration
+
+	mu        sync.Mutex
+	username  string
+	password  string
+	token     string
+	expiredAt time.Time
+}
+
+func New(config Config) (*Client, error) {
+	baseURL, err := url.Parse(strings.TrimSuffix(config.BaseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base url: %w", err)
+	}
+	if baseURL.Scheme != "http" && baseURL.Scheme != "https" {
+		return nil, fmt.Errorf("base url must be http or https, got %q", config.BaseURL)
+	}
+
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: defaultTimeout}
+	}
+
+	switch {
+	case config.MaxRetries == 0:
+		config.MaxRetries = defaultMaxRetries
+	case config.MaxRetries < 0:
+		config.MaxRetries = 0
+	}
+
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = defaultRetryBackoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = defaultMaxBackoff
+	}
+
+	return &Client{
+		baseURL:      baseURL,
+		httpClient:   config.HTTPClient,
+		maxRetries:   config.MaxRetries,
+		retryBackoff: config.RetryBackoff,
+		maxBackoff:   config.MaxBackoff,
+		username:     config.Username,
+		password:     config.Password,
+		token:        config.Token,
+	}, nil
+}
+
+// request is one call to the API. Public calls are sent without a token.
+type request struct {
+	method  string
+	path    string
+	query   url.Values
+	body    any
+	cookies []*http.Cookie
+	public  bool
+	noRetry bool
+}
+
+// do sends the request, retrying server errors with backoff, and decodes the JSON response into out.
+// A rejected session token is replaced by logging in again, once per call.
+func (client *Client) do(ctx context.Context, req request, out any) error {
+	var payload []byte
+	if req.body != nil {
+		var err error
+		payload, err = json.Marshal(req.body)
+		if err != nil {
+			return fmt.Errorf("cannot encode request body: %w", err)
+		}
+	}
+
+	relogged := false
+	for attempt := 0; ; attempt++ {
+		var accessToken string
+		if !req.public {
+			var err error
+			accessToken, err = client.accessToken(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		resp, err := client.send(ctx, req, payload, accessToken)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			// the server may have handled a request that failed in transit, only requests that are safe to repeat are sent again
+			if attempt < client.maxRetries && !req.noRetry && idempotent(req.method) {
+				if err := client.wait(ctx, attempt, 0); err != nil {
+					return err
+				}
+				continue
+			}
+			return err
+		}
+
+		if attempt < client.maxRetries && !req.noRetry && retryable(req.method, resp.StatusCode) {
+			retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+			// a request sent again before the rate limit lifts is refused again, it is better to return
+			if resp.StatusCode != http.StatusTooManyRequests || retryAfter <= client.maxBackoff {
+				discard(resp)
+				if err := client.wait(ctx, attempt, retryAfter); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if resp.StatusCode >= http.StatusBadRequest {
+			apiErr := decodeError(resp)
+			discard(resp)
+
+			if resp.StatusCode == http.StatusUnauthorized && !req.public && !relogged && client.canLogin() &&
+				(apiErr.Code == apierror.CodeInvalidToken || apiErr.Code == apierror.CodeTokenExpired) {
+				relogged = true
+				client.forgetToken(accessToken)
+				attempt--
+				continue
+			}
+			return apiErr
+		}
+
+		err = decodeResponse(resp, out)
+		discard(resp)
+		return err
+	}
+}
+
+func (client *Client) send(ctx context.Context, req request, payload []byte, accessToken string) (*http.Response, error) {
+	endpoint := client.baseURL.JoinPath(req.path)
+	if len(req.query) > 0 {
+		endpoint.RawQuery = req.query.Encode()
+	}
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, endpoint.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq.Header.Set("Accept", "application/json")
+	if payload != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if accessToken != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	for _, cookie := range req.cookies {
+		httpReq.AddCookie(cookie)
+	}
+
+	return client.httpClient.Do(httpReq)
+}
+
+// wait sleeps before a retry: the Retry-After of the server when given, otherwise exponential backoff with jitter.
+func (client *Client) wait(ctx context.Context, attempt int, retryAfter time.Duration) error {
+	delay := retryAfter
+	if delay <= 0 {
+		delay = client.retryBackoff << attempt
+		if delay <= 0 || delay > client.maxBackoff {
+			delay = client.maxBackoff
+		}
+		// random between half and the full delay, so clients that failed together do not retry together
+		delay = delay/2 + rand.N(delay/2+1)
+	}
+	if delay > client.maxBackoff {
+		delay = client.maxBackoff
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// retryable tells whether a response is worth sending the request again. Other methods than the idempotent ones
+// are only retried when the server did not handle them: it was rate limited, a gateway could not reach it or it was unavailable.
+func retryable(method string, status int) bool {
+	if status == http.StatusTooManyRequests {
+		return true
+	}
+	if status < http.StatusInternalServerError {
+		return false
+	}
+	if idempotent(method) {
+		return true
+	}
+	return status == http.StatusBadGateway || status == http.StatusServiceUnavailable
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at)
+	}
+	return 0
+}
+
+func decodeResponse(resp *http.Response, out any) error {
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	if text, ok := out.(*string); ok {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("cannot read response: %w", err)
+		}
+		*text = string(body)
+		return nil
+	}
+
+	if raw, ok := out.(*json.RawMessage); ok {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("cannot read response: %w", err)
+		}
+		*raw = body
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("cannot decode response of %s %s: %w", resp.Request.Method, resp.Request.URL.Path, err)
+	}
+	return nil
+}
+
+// discard reads the rest of the body so the connection can be reused.
+func discard(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+}
+
+// accessToken returns the token to send, logging in first when there is none or the session token is about to expire.
+func (client *Client) accessToken(ctx context.Context) (string, error) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	if client.token != "" && (client.expiredAt.IsZero() || time.Until(client.expiredAt) > refreshMargin) {
+		return client.token, nil
+	}
+
+	if client.username == "" || client.password == "" {
+		if client.token != "" {
+			return client.token, nil
+		}
+		return "", errors.New("client has no token and no credentials to log in")
+	}
+
+	session, err := client.login(ctx, client.username, client.password)
+	if err != nil {
+		return "", fmt.Errorf("cannot log in: %w", err)
+	}
+
+	client.token = session.AccessToken
+	client.expiredAt = session.ExpiredAt
+	return client.token, nil
+}
+
+func (client *Client) canLogin() bool {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	return client.username != "" && client.password != ""
+}
+
+// forgetToken drops a rejected token, unless another call already replaced it.
+func (client *Client) forgetToken(rejected string) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	if client.token == rejected {
+		client.token = ""
+		client.expiredAt = time.Time{}
+	}
+}
+
+// SetToken replaces the token sent with every request, e.g. a personal access token.
+func (client *Client) SetToken(accessToken string) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	client.token = accessToken
+	client.expiredAt = time.Time{}
+}
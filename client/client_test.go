@@ -0,0 +1,320 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sangketkit01/personal-financial/apierror"
+	"github.com/sangketkit01/personal-financial/apitypes"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServer answers with handlers set per test and counts the requests of every route.
+type fakeServer struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func newFakeServer(t *testing.T, routes map[string]http.HandlerFunc) *fakeServer {
+	t.Helper()
+
+	server := &fakeServer{counts: map[string]int{}}
+	mux := http.NewServeMux()
+	for pattern, handler := range routes {
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			server.mu.Lock()
+			server.counts[pattern]++
+			server.mu.Unlock()
+
+			handler(w, r)
+		})
+	}
+
+	server.Server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (server *fakeServer) count(pattern string) int {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	return server.counts[pattern]
+}
+
+func newTestClient(t *testing.T, server *fakeServer, config Config) *Client {
+	t.Helper()
+
+	config.BaseURL = server.URL
+	if config.RetryBackoff == 0 {
+		config.RetryBackoff = time.Millisecond
+	}
+	if config.MaxBackoff == 0 {
+		config.MaxBackoff = 10 * time.Millisecond
+	}
+
+	client, err := New(config)
+	require.NoError(t, err)
+	return client
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeProblem(w http.ResponseWriter, r *http.Request, err *apierror.Error) {
+	w.Header().Set("Content-Type", apierror.ContentType)
+	w.WriteHeader(err.Status)
+	json.NewEncoder(w).Encode(err.Problem(r.URL.Path, "request-1"))
+}
+
+// failing answers with status the first failures times and with the profile afterwards.
+func failing(failures int, status int, header http.Header) http.HandlerFunc {
+	var mu sync.Mutex
+	return func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		failures--
+		fail := failures >= 0
+		mu.Unlock()
+
+		if fail {
+			for key, values := range header {
+				w.Header()[key] = values
+			}
+			writeProblem(w, r, apierror.New(status, "try again"))
+			return
+		}
+		writeJSON(w, http.StatusOK, apitypes.ProfileResponse{Username: "alice"})
+	}
+}
+
+func TestRetryServerErrors(t *testing.T) {
+	server := newFakeServer(t, map[string]http.HandlerFunc{
+		"GET /api/v1/me": failing(2, http.StatusInternalServerError, nil),
+	})
+	client := newTestClient(t, server, Config{Token: "token"})
+
+	profile, err := client.GetProfile(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "alice", profile.Username)
+	require.Equal(t, 3, server.count("GET /api/v1/me"))
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	server := newFakeServer(t, map[string]http.HandlerFunc{
+		"GET /api/v1/me": failing(10, http.StatusServiceUnavailable, nil),
+	})
+	client := newTestClient(t, server, Config{Token: "token", MaxRetries: 2})
+
+	_, err := client.GetProfile(context.Background())
+	require.Error(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, StatusCode(err))
+	require.Equal(t, 3, server.count("GET /api/v1/me"))
+}
+
+func TestNoRetryForPost(t *testing.T) {
+	server := newFakeServer(t, map[string]http.HandlerFunc{
+		"POST /api/v1/budgets": failing(1, http.StatusInternalServerError, nil),
+	})
+	client := newTestClient(t, server, Config{Token: "token"})
+
+	_, err := client.AddBudget(context.Background(), apitypes.BudgetRequest{Amount: 100})
+	require.Error(t, err)
+	require.Equal(t, http.StatusInternalServerError, StatusCode(err))
+	require.Equal(t, 1, server.count("POST /api/v1/budgets"))
+}
+
+func TestRetryPostWhenNotHandled(t *testing.T) {
+	server := newFakeServer(t, map[string]http.HandlerFunc{
+		"POST /api/v1/budgets": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, http.StatusOK, apitypes.Budget{ID: 1, Amount: "100"})
+		},
+		"POST /api/v1/contacts": failing(1, http.StatusBadGateway, nil),
+	})
+	client := newTestClient(t, server, Config{Token: "token"})
+
+	budget, err := client.AddBudget(context.Background(), apitypes.BudgetRequest{Amount: 100})
+	require.NoError(t, err)
+	require.Equal(t, "100", budget.Amount.String())
+
+	// a gateway error means the server never saw the request, it is safe to send it again
+	_, err = client.CreateContact(context.Background(), apitypes.CreateContactRequest{Name: "Bob"})
+	require.NoError(t, err)
+	require.Equal(t, 2, server.count("POST /api/v1/contacts"))
+}
+
+func TestRetryTooManyRequests(t *testing.T) {
+	server := newFakeServer(t, map[string]http.HandlerFunc{
+		"POST /api/v1/contacts": failing(1, http.StatusTooManyRequests, http.Header{"Retry-After": {"1"}}),
+	})
+	client := newTestClient(t, server, Config{Token: "token", MaxBackoff: 2 * time.Second})
+
+	start := time.Now()
+	_, err := client.CreateContact(context.Background(), apitypes.CreateContactRequest{Name: "Bob"})
+	require.NoError(t, err)
+	require.Equal(t, 2, server.count("POST /api/v1/contacts"))
+	require.GreaterOrEqual(t, time.Since(start), time.Second, "the retry waits for Retry-After")
+}
+
+func TestTooManyRequestsLongerThanMaxBackoff(t *testing.T) {
+	server := newFakeServer(t, map[string]http.HandlerFunc{
+		"GET /api/v1/me": failing(1, http.StatusTooManyRequests, http.Header{"Retry-After": {"600"}}),
+	})
+	client := newTestClient(t, server, Config{Token: "token"})
+
+	_, err := client.GetProfile(context.Background())
+	require.Equal(t, http.StatusTooManyRequests, StatusCode(err))
+	require.Equal(t, 1, server.count("GET /api/v1/me"))
+}
+
+func TestBackoff(t *testing.T) {
+	client := &Client{retryBackoff: 20 * time.Millisecond, maxBackoff: 50 * time.Millisecond}
+
+	for attempt, want := range []time.Duration{20 * time.Millisecond, 40 * time.Millisecond, 50 * time.Millisecond, 50 * time.Millisecond} {
+		start := time.Now()
+		require.NoError(t, client.wait(context.Background(), attempt, 0))
+		elapsed := time.Since(start)
+
+		require.GreaterOrEqual(t, elapsed, want/2, "attempt %d", attempt)
+		require.Less(t, elapsed, want+50*time.Millisecond, "attempt %d", attempt)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, client.wait(ctx, 0, time.Hour), context.Canceled)
+}
+
+// sessionServer issues a new token on every login and rejects the tokens in rejected with code.
+func sessionServer(t *testing.T, code apierror.Code, rejected func(token string) bool, expiresIn time.Duration) *fakeServer {
+	var mu sync.Mutex
+	logins := 0
+
+	return newFakeServer(t, map[string]http.HandlerFunc{
+		"POST /api/v1/sessions": func(w http.ResponseWriter, r *http.Request) {
+			var req apitypes.LoginUserRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			if req.Username != "alice" || req.Password != "secret-password" {
+				writeProblem(w, r, apierror.New(http.StatusUnauthorized, "wrong password"))
+				return
+			}
+
+			mu.Lock()
+			logins++
+			token := "token-" + string(rune('0'+logins))
+			mu.Unlock()
+
+			writeJSON(w, http.StatusOK, apitypes.LoginUserRespose{Username: "alice", AccessToken: token, ExpiredAt: time.Now().Add(expiresIn)})
+		},
+		"GET /api/v1/me": func(w http.ResponseWriter, r *http.Request) {
+			token := r.Header.Get("Authorization")[len("Bearer "):]
+			if rejected(token) {
+				writeProblem(w, r, apierror.New(http.StatusUnauthorized, "token rejected").WithCode(code))
+				return
+			}
+			writeJSON(w, http.StatusOK, apitypes.ProfileResponse{Username: "alice"})
+		},
+	})
+}
+
+func TestReloginOnRejectedToken(t *testing.T) {
+	for _, code := range []apierror.Code{apierror.CodeTokenExpired, apierror.CodeInvalidToken} {
+		t.Run(string(code), func(t *testing.T) {
+			server := sessionServer(t, code, func(token string) bool { return token == "token-1" }, time.Hour)
+			client := newTestClient(t, server, Config{Username: "alice", Password: "secret-password"})
+
+			_, err := client.GetProfile(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 2, server.count("POST /api/v1/sessions"))
+			require.Equal(t, 2, server.count("GET /api/v1/me"))
+
+			// the new token is kept
+			_, err = client.GetProfile(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 2, server.count("POST /api/v1/sessions"))
+		})
+	}
+}
+
+func TestReloginOnce(t *testing.T) {
+	server := sessionServer(t, apierror.CodeTokenExpired, func(string) bool { return true }, time.Hour)
+	client := newTestClient(t, server, Config{Username: "alice", Password: "secret-password"})
+
+	_, err := client.GetProfile(context.Background())
+	require.True(t, HasCode(err, apierror.CodeTokenExpired))
+	require.Equal(t, 2, server.count("POST /api/v1/sessions"))
+	require.Equal(t, 2, server.count("GET /api/v1/me"))
+}
+
+func TestNoReloginForOtherErrors(t *testing.T) {
+	server := sessionServer(t, apierror.CodeUnauthorized, func(string) bool { return true }, time.Hour)
+	client := newTestClient(t, server, Config{Username: "alice", Password: "secret-password"})
+
+	_, err := client.GetProfile(context.Background())
+	require.Equal(t, http.StatusUnauthorized, StatusCode(err))
+	require.Equal(t, 1, server.count("POST /api/v1/sessions"))
+	require.Equal(t, 1, server.count("GET /api/v1/me"))
+
+	// a personal access token cannot be replaced, it is returned as it is
+	server = sessionServer(t, apierror.CodeTokenExpired, func(string) bool { return true }, time.Hour)
+	client = newTestClient(t, server, Config{Token: "token-pat"})
+
+	_, err = client.GetProfile(context.Background())
+	require.True(t, HasCode(err, apierror.CodeTokenExpired))
+	require.Equal(t, 0, server.count("POST /api/v1/sessions"))
+	require.Equal(t, 1, server.count("GET /api/v1/me"))
+}
+
+func TestRefreshBeforeExpiry(t *testing.T) {
+	// every session expires within the refresh margin, so each call logs in first
+	server := sessionServer(t, apierror.CodeTokenExpired, func(string) bool { return false }, refreshMargin/2)
+	client := newTestClient(t, server, Config{Username: "alice", Password: "secret-password"})
+
+	for range 2 {
+		_, err := client.GetProfile(context.Background())
+		require.NoError(t, err)
+	}
+	require.Equal(t, 2, server.count("POST /api/v1/sessions"))
+	require.Equal(t, 2, server.count("GET /api/v1/me"))
+}
+
+func TestDecodeProblem(t *testing.T) {
+	server := newFakeServer(t, map[string]http.HandlerFunc{
+		"POST /api/v1/splits/settlements/{id}/confirm": func(w http.ResponseWriter, r *http.Request) {
+			writeProblem(w, r, apierror.New(http.StatusConflict, "settlement has been answered").WithCode(apierror.CodeSettlementAnswered))
+		},
+		"GET /api/v1/me": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			w.Header().Set("X-Request-ID", "proxy-1")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("<html>forbidden</html>"))
+		},
+	})
+	client := newTestClient(t, server, Config{Token: "token"})
+
+	_, err := client.ConfirmSettlement(context.Background(), 7)
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusConflict, apiErr.Status)
+	require.Equal(t, apierror.CodeSettlementAnswered, apiErr.Code)
+	require.Equal(t, "settlement has been answered", apiErr.Detail)
+	require.Equal(t, "/api/v1/splits/settlements/7/confirm", apiErr.Instance)
+	require.Equal(t, "request-1", apiErr.RequestID)
+	require.True(t, HasCode(err, apierror.CodeSettlementAnswered))
+
+	// a body that is not problem details keeps the status
+	_, err = client.GetProfile(context.Background())
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.Status)
+	require.Equal(t, apierror.New(http.StatusForbidden, "").Code, apiErr.Code)
+	require.Equal(t, http.StatusText(http.StatusForbidden), apiErr.Title)
+	require.Equal(t, "proxy-1", apiErr.RequestID)
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/sangketkit01/personal-financial/apierror"
+)
+
+// Error is a response with an error status. The server sends problem details, a response that is not one,
+// e.g. from a proxy, keeps only its status.
+type Error struct {
+	apierror.Problem
+}
+
+func (e *Error) Error() string {
+	if e.Detail != "" {
+		return fmt.Sprintf("%d %s: %s", e.Status, e.Code, e.Detail)
+	}
+	return fmt.Sprintf("%d %s", e.Status, e.Code)
+}
+
+// HasCode tells whether err is an error response with the code.
+func HasCode(err error, code apierror.Code) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
+
+// StatusCode is the status of an error response, 0 for other errors.
+func StatusCode(err error) int {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Status
+	}
+	return 0
+}
+
+func decodeError(resp *http.Response) *Error {
+	apiErr := &Error{}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err == nil {
+		_ = json.Unmarshal(body, &apiErr.Problem)
+	}
+
+	apiErr.Status = resp.StatusCode
+	if apiErr.Title == "" {
+		apiErr.Title = http.StatusText(resp.StatusCode)
+	}
+	if apiErr.Code == "" {
+		apiErr.Code = apierror.New(resp.StatusCode, "").Code
+	}
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = resp.Header.Get("X-Request-ID")
+	}
+	return apiErr
+}
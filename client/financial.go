@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/sangketkit01/personal-financial/apitypes"
+)
+
+func (client *Client) AddFinancial(ctx context.Context, req apitypes.NewFinancialRequest) (apitypes.NewFinancialResponse, error) {
+	var response apitypes.NewFinancialResponse
+	err := client.do(ctx, request{method: http.MethodPost, path: APIPrefix + "/transactions", body: req}, &response)
+	return response, err
+}
+
+func (client *Client) MyFinancial(ctx context.Context) ([]apitypes.FinancialEntry, error) {
+	var financials []apitypes.FinancialEntry
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/transactions"}, &financials)
+	return financials, err
+}
+
+func (client *Client) GetFinancial(ctx context.Context, id int64) (apitypes.FinancialEntry, error) {
+	var financial apitypes.FinancialEntry
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/transactions/" + pathID(id)}, &financial)
+	return financial, err
+}
+
+func (client *Client) FinancialHistory(ctx context.Context, id int64) ([]apitypes.AuditEventResponse, error) {
+	var events []apitypes.AuditEventResponse
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/transactions/" + pathID(id) + "/history"}, &events)
+	return events, err
+}
+
+// UpdateFinancial changes a record, override allows changing a reconciled one.
+func (client *Client) UpdateFinancial(ctx context.Context, id int64, req apitypes.UpdateFinancialRequest, override bool) (apitypes.UpdateFinancialResponse, error) {
+	var response apitypes.UpdateFinancialResponse
+	err := client.do(ctx, request{
+		method: http.MethodPut,
+		path:   APIPrefix + "/transactions/" + pathID(id),
+		query:  overrideQuery(override),
+		body:   req,
+	}, &response)
+	return response, err
+}
+
+// DeleteFinancial moves a record to the trash, reconciled records cannot be deleted.
+func (client *Client) DeleteFinancial(ctx context.Context, id int64) (apitypes.DeleteFinancialResponse, error) {
+	var response apitypes.DeleteFinancialResponse
+	err := client.do(ctx, request{method: http.MethodDelete, path: APIPrefix + "/transactions/" + pathID(id)}, &response)
+	return response, err
+}
+
+func (client *Client) RestoreFinancial(ctx context.Context, id int64) (apitypes.RestoreFinancialResponse, error) {
+	var response apitypes.RestoreFinancialResponse
+	err := client.do(ctx, request{method: http.MethodPost, path: APIPrefix + "/transactions/" + pathID(id) + "/restore"}, &response)
+	return response, err
+}
+
+func (client *Client) Trash(ctx context.Context) (apitypes.TrashResponse, error) {
+	var trash apitypes.TrashResponse
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/trash"}, &trash)
+	return trash, err
+}
+
+func overrideQuery(override bool) url.Values {
+	if !override {
+		return nil
+	}
+	return url.Values{"override": {"true"}}
+}
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/sangketkit01/personal-financial/apitypes"
+)
+
+// Healthz checks that the server answers. Health checks are never retried, they report the state as it is.
+func (client *Client) Healthz(ctx context.Context) error {
+	return client.do(ctx, request{method: http.MethodGet, path: "/healthz", public: true, noRetry: true}, nil)
+}
+
+// Readyz returns an Error with status 503 while the server cannot take traffic.
+func (client *Client) Readyz(ctx context.Context) (apitypes.ReadyResponse, error) {
+	var ready apitypes.ReadyResponse
+	err := client.do(ctx, request{method: http.MethodGet, path: "/readyz", public: true, noRetry: true}, &ready)
+	return ready, err
+}
+
+// OpenAPI returns the OpenAPI document of the server.
+func (client *Client) OpenAPI(ctx context.Context) (json.RawMessage, error) {
+	var document json.RawMessage
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/openapi.json", public: true}, &document)
+	return document, err
+}
+
+// Metrics returns the Prometheus metrics in the text format.
+func (client *Client) Metrics(ctx context.Context) (string, error) {
+	var metrics string
+	err := client.do(ctx, request{method: http.MethodGet, path: "/metrics", public: true}, &metrics)
+	return metrics, err
+}
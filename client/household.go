@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/sangketkit01/personal-financial/apitypes"
+)
+
+func householdPath(householdID int64) string {
+	return APIPrefix + "/households/" + pathID(householdID)
+}
+
+func (client *Client) CreateHousehold(ctx context.Context, req apitypes.CreateHouseholdRequest) (apitypes.Household, error) {
+	var household apitypes.Household
+	err := client.do(ctx, request{method: http.MethodPost, path: APIPrefix + "/households", body: req}, &household)
+	return household, err
+}
+
+func (client *Client) MyHouseholds(ctx context.Context) ([]apitypes.MyHousehold, error) {
+	var households []apitypes.MyHousehold
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/households"}, &households)
+	return households, err
+}
+
+func (client *Client) JoinHousehold(ctx context.Context, req apitypes.JoinHouseholdRequest) (apitypes.HouseholdMember, error) {
+	var member apitypes.HouseholdMember
+	err := client.do(ctx, request{method: http.MethodPost, path: APIPrefix + "/households/join", body: req}, &member)
+	return member, err
+}
+
+func (client *Client) GetHousehold(ctx context.Context, householdID int64) (apitypes.HouseholdResponse, error) {
+	var household apitypes.HouseholdResponse
+	err := client.do(ctx, request{method: http.MethodGet, path: householdPath(householdID)}, &household)
+	return household, err
+}
+
+func (client *Client) InviteHouseholdMember(ctx context.Context, householdID int64, req apitypes.InviteHouseholdMemberRequest) (apitypes.InvitationResponse, error) {
+	var invitation apitypes.InvitationResponse
+	err := client.do(ctx, request{method: http.MethodPost, path: householdPath(householdID) + "/invitations", body: req}, &invitation)
+	return invitation, err
+}
+
+func (client *Client) UpdateHouseholdMember(ctx context.Context, householdID int64, username string, req apitypes.UpdateHouseholdMemberRequest) (apitypes.HouseholdMember, error) {
+	var member apitypes.HouseholdMember
+	err := client.do(ctx, request{
+		method: http.MethodPut,
+		path:   householdPath(householdID) + "/members/" + url.PathEscape(username),
+		body:   req,
+	}, &member)
+	return member, err
+}
+
+// RemoveHouseholdMember removes a member, with the username of the caller it leaves the household.
+func (client *Client) RemoveHouseholdMember(ctx context.Context, householdID int64, username string) (apitypes.MessageResponse, error) {
+	var response apitypes.MessageResponse
+	err := client.do(ctx, request{method: http.MethodDelete, path: householdPath(householdID) + "/members/" + url.PathEscape(username)}, &response)
+	return response, err
+}
+
+func (client *Client) HouseholdFinancials(ctx context.Context, householdID int64) ([]apitypes.HouseholdFinancial, error) {
+	var financials []apitypes.HouseholdFinancial
+	err := client.do(ctx, request{method: http.MethodGet, path: householdPath(householdID) + "/transactions"}, &financials)
+	return financials, err
+}
+
+func (client *Client) HouseholdSummary(ctx context.Context, householdID int64, query apitypes.MonthYearQuery) (apitypes.HouseholdSummaryResponse, error) {
+	var summary apitypes.HouseholdSummaryResponse
+	err := client.do(ctx, request{method: http.MethodGet, path: householdPath(householdID) + "/summary", query: queryValues(query)}, &summary)
+	return summary, err
+}
+
+func (client *Client) AddHouseholdBudget(ctx context.Context, householdID int64, req apitypes.BudgetRequest) (apitypes.Budget, error) {
+	var budget apitypes.Budget
+	err := client.do(ctx, request{method: http.MethodPost, path: householdPath(householdID) + "/budgets", body: req}, &budget)
+	return budget, err
+}
+
+func (client *Client) GetHouseholdBudget(ctx context.Context, householdID int64, query apitypes.MonthYearQuery) (apitypes.Budget, error) {
+	var budget apitypes.Budget
+	err := client.do(ctx, request{method: http.MethodGet, path: householdPath(householdID) + "/budgets", query: queryValues(query)}, &budget)
+	return budget, err
+}
@@ -0,0 +1,27 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/sangketkit01/personal-financial/apitypes"
+)
+
+func (client *Client) ListClosedPeriods(ctx context.Context) ([]apitypes.ClosedPeriodResponse, error) {
+	var periods []apitypes.ClosedPeriodResponse
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/periods"}, &periods)
+	return periods, err
+}
+
+func (client *Client) ClosePeriod(ctx context.Context, req apitypes.PeriodRequest) (apitypes.PeriodClosing, error) {
+	var closing apitypes.PeriodClosing
+	err := client.do(ctx, request{method: http.MethodPost, path: APIPrefix + "/periods", body: req}, &closing)
+	return closing, err
+}
+
+func (client *Client) ReopenPeriod(ctx context.Context, req apitypes.PeriodRequest) (apitypes.PeriodClosing, error) {
+	var closing apitypes.PeriodClosing
+	err := client.do(ctx, request{method: http.MethodDelete, path: fmt.Sprintf("%s/periods/%d/%d", APIPrefix, req.Year, req.Month)}, &closing)
+	return closing, err
+}
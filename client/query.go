@@ -0,0 +1,44 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// queryValues encodes the form tagged fields of a query struct of the api package, zero values are left out
+// so the server applies its defaults.
+func queryValues(params any) url.Values {
+	values := url.Values{}
+
+	v := reflect.ValueOf(params)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return values
+		}
+		v = v.Elem()
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("form"), ",")
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+
+		value := v.Field(i)
+		if value.IsZero() {
+			continue
+		}
+		values.Set(name, fmt.Sprint(value.Interface()))
+	}
+
+	return values
+}
+
+func pathID(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
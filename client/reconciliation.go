@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/sangketkit01/personal-financial/apitypes"
+)
+
+func (client *Client) CreateReconciliation(ctx context.Context, req apitypes.CreateReconciliationRequest) (apitypes.ReconciliationResponse, error) {
+	var reconciliation apitypes.ReconciliationResponse
+	err := client.do(ctx, request{method: http.MethodPost, path: APIPrefix + "/reconciliations", body: req}, &reconciliation)
+	return reconciliation, err
+}
+
+func (client *Client) ListReconciliations(ctx context.Context) ([]apitypes.ReconciliationResponse, error) {
+	var reconciliations []apitypes.ReconciliationResponse
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/reconciliations"}, &reconciliations)
+	return reconciliations, err
+}
+
+func (client *Client) GetReconciliation(ctx context.Context, id int64) (apitypes.ReconciliationDetailResponse, error) {
+	var reconciliation apitypes.ReconciliationDetailResponse
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/reconciliations/" + pathID(id)}, &reconciliation)
+	return reconciliation, err
+}
+
+func (client *Client) ClearFinancials(ctx context.Context, id int64, req apitypes.ClearFinancialsRequest) (apitypes.ClearFinancialsResponse, error) {
+	var response apitypes.ClearFinancialsResponse
+	err := client.do(ctx, request{method: http.MethodPost, path: APIPrefix + "/reconciliations/" + pathID(id) + "/clear", body: req}, &response)
+	return response, err
+}
+
+func (client *Client) UnclearFinancials(ctx context.Context, id int64, req apitypes.ClearFinancialsRequest) (apitypes.ClearFinancialsResponse, error) {
+	var response apitypes.ClearFinancialsResponse
+	err := client.do(ctx, request{method: http.MethodPost, path: APIPrefix + "/reconciliations/" + pathID(id) + "/unclear", body: req}, &response)
+	return response, err
+}
+
+func (client *Client) FinishReconciliation(ctx context.Context, id int64) (apitypes.ReconciliationResponse, error) {
+	var reconciliation apitypes.ReconciliationResponse
+	err := client.do(ctx, request{method: http.MethodPost, path: APIPrefix + "/reconciliations/" + pathID(id) + "/finish"}, &reconciliation)
+	return reconciliation, err
+}
+
+func (client *Client) CancelReconciliation(ctx context.Context, id int64) (apitypes.MessageResponse, error) {
+	var response apitypes.MessageResponse
+	err := client.do(ctx, request{method: http.MethodDelete, path: APIPrefix + "/reconciliations/" + pathID(id)}, &response)
+	return response, err
+}
@@ -0,0 +1,32 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/sangketkit01/personal-financial/apitypes"
+)
+
+func (client *Client) Forecast(ctx context.Context, query apitypes.ForecastRequest) (apitypes.ForecastResponse, error) {
+	var forecast apitypes.ForecastResponse
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/forecast", query: queryValues(query)}, &forecast)
+	return forecast, err
+}
+
+func (client *Client) ListAnomalies(ctx context.Context) ([]apitypes.AnomalyEntry, error) {
+	var anomalies []apitypes.AnomalyEntry
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/insights/anomalies"}, &anomalies)
+	return anomalies, err
+}
+
+func (client *Client) ComparePeriods(ctx context.Context, query apitypes.CompareQuery) (apitypes.CompareResponse, error) {
+	var comparison apitypes.CompareResponse
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/reports/compare", query: queryValues(query)}, &comparison)
+	return comparison, err
+}
+
+func (client *Client) Trend(ctx context.Context, query apitypes.TrendQuery) (apitypes.TrendResponse, error) {
+	var trend apitypes.TrendResponse
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/reports/trend", query: queryValues(query)}, &trend)
+	return trend, err
+}
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/sangketkit01/personal-financial/apitypes"
+)
+
+func (client *Client) CreateContact(ctx context.Context, req apitypes.CreateContactRequest) (apitypes.Contact, error) {
+	var contact apitypes.Contact
+	err := client.do(ctx, request{method: http.MethodPost, path: APIPrefix + "/contacts", body: req}, &contact)
+	return contact, err
+}
+
+func (client *Client) ListContacts(ctx context.Context) ([]apitypes.Contact, error) {
+	var contacts []apitypes.Contact
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/contacts"}, &contacts)
+	return contacts, err
+}
+
+func (client *Client) CreateSharedExpense(ctx context.Context, req apitypes.CreateSharedExpenseRequest) (apitypes.SharedExpenseResult, error) {
+	var result apitypes.SharedExpenseResult
+	err := client.do(ctx, request{method: http.MethodPost, path: APIPrefix + "/splits/expenses", body: req}, &result)
+	return result, err
+}
+
+func (client *Client) ListSharedExpenses(ctx context.Context) ([]apitypes.SharedExpenseResponse, error) {
+	var expenses []apitypes.SharedExpenseResponse
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/splits/expenses"}, &expenses)
+	return expenses, err
+}
+
+func (client *Client) SplitBalances(ctx context.Context) (apitypes.SplitBalancesResponse, error) {
+	var balances apitypes.SplitBalancesResponse
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/splits/balances"}, &balances)
+	return balances, err
+}
+
+func (client *Client) CreateSettlement(ctx context.Context, req apitypes.CreateSettlementRequest) (apitypes.Settlement, error) {
+	var settlement apitypes.Settlement
+	err := client.do(ctx, request{method: http.MethodPost, path: APIPrefix + "/splits/settlements", body: req}, &settlement)
+	return settlement, err
+}
+
+func (client *Client) ListSettlements(ctx context.Context) ([]apitypes.Settlement, error) {
+	var settlements []apitypes.Settlement
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/splits/settlements"}, &settlements)
+	return settlements, err
+}
+
+func (client *Client) ConfirmSettlement(ctx context.Context, id int64) (apitypes.Settlement, error) {
+	var settlement apitypes.Settlement
+	err := client.do(ctx, request{method: http.MethodPost, path: APIPrefix + "/splits/settlements/" + pathID(id) + "/confirm"}, &settlement)
+	return settlement, err
+}
+
+func (client *Client) RejectSettlement(ctx context.Context, id int64) (apitypes.Settlement, error) {
+	var settlement apitypes.Settlement
+	err := client.do(ctx, request{method: http.MethodPost, path: APIPrefix + "/splits/settlements/" + pathID(id) + "/reject"}, &settlement)
+	return settlement, err
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/sangketkit01/personal-financial/apitypes"
+)
+
+func (client *Client) Summary(ctx context.Context, query apitypes.SummaryQuery) (apitypes.SummaryResponse, error) {
+	var summary apitypes.SummaryResponse
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/summary", query: queryValues(query)}, &summary)
+	return summary, err
+}
+
+func (client *Client) SummaryCurrentMonth(ctx context.Context) (apitypes.FinancialSummary, error) {
+	var summary apitypes.FinancialSummary
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/summary/current-month"}, &summary)
+	return summary, err
+}
+
+func (client *Client) SummaryCurrentYear(ctx context.Context) (apitypes.FinancialSummary, error) {
+	var summary apitypes.FinancialSummary
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/summary/current-year"}, &summary)
+	return summary, err
+}
+
+func (client *Client) SummaryEachYear(ctx context.Context) ([]apitypes.YearSummary, error) {
+	var summaries []apitypes.YearSummary
+	err := client.do(ctx, request{method: http.MethodGet, path: APIPrefix + "/summary/years"}, &summaries)
+	return summaries, err
+}
+
+func (client *Client) SummaryByYear(ctx context.Context, req apitypes.YearRequest) (apitypes.FinancialSummary, error) {
+	var summary apitypes.FinancialSummary
+	err := client.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("%s/summary/%d", APIPrefix, req.Year)}, &summary)
+	return summary, err
+}
+
+func (client *Client) SummaryTypeByYear(ctx context.Context, req apitypes.YearRequest) ([]apitypes.TypeSummary, error) {
+	var summaries []apitypes.TypeSummary
+	err := client.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("%s/summary/%d/types", APIPrefix, req.Year)}, &summaries)
+	return summaries, err
+}
+
+func (client *Client) SummaryByMonthYear(ctx context.Context, req apitypes.YearMonthRequest) (apitypes.FinancialSummary, error) {
+	var summary apitypes.FinancialSummary
+	err := client.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("%s/summary/%d/%d", APIPrefix, req.Year, req.Month)}, &summary)
+	return summary, err
+}
+
+func (client *Client) SummaryTypeByMonthYear(ctx context.Context, req apitypes.YearMonthRequest) ([]apitypes.TypeSummary, error) {
+	var summaries []apitypes.TypeSummary
+	err := client.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("%s/summary/%d/%d/types", APIPrefix, req.Year, req.Month)}, &summaries)
+	return summaries, err
+}
@@ -0,0 +1,65 @@
+-- Summary benchmark for a user with 1M records, run with `make bench_summary`.
+-- Everything happens in one transaction that is rolled back, the database is left as it was.
+\timing on
+
+BEGIN;
+
+INSERT INTO users (username, name, email, phone, password)
+VALUES ('bench', 'Bench', 'bench@example.com', '0000000000', 'x');
+
+INSERT INTO financial_types (type) VALUES ('bench') ON CONFLICT DO NOTHING;
+
+-- 1M records over five years, the trigger fills monthly_aggregates on the way
+INSERT INTO financials (user_id, amount, direction, type_id, created_at)
+SELECT
+  'bench',
+  CASE WHEN i % 5 = 0 THEN 1000 + i % 9000 ELSE -(10 + i % 2000) END,
+  CASE WHEN i % 5 = 0 THEN 'in' ELSE 'out' END,
+  t.ids[1 + i % array_length(t.ids, 1)],
+  NOW() - make_interval(secs => i * 157)
+FROM generate_series(1, 1000000) AS i,
+  (SELECT array_agg(id ORDER BY id) AS ids FROM financial_types) AS t;
+
+ANALYZE financials;
+ANALYZE monthly_aggregates;
+
+-- before: the records of the month are scanned
+EXPLAIN (ANALYZE, BUFFERS)
+SELECT
+  COALESCE(SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END), 0)::bigint AS total_income,
+  COALESCE(SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END), 0)::bigint AS total_expense
+FROM financials f
+JOIN users u ON u.username = f.user_id
+WHERE f.user_id = 'bench'
+  AND f.deleted_at IS NULL
+  AND EXTRACT(MONTH FROM period_date(f.created_at, u.time_zone, u.period_start_day)) = EXTRACT(MONTH FROM NOW())
+  AND EXTRACT(YEAR FROM period_date(f.created_at, u.time_zone, u.period_start_day)) = EXTRACT(YEAR FROM NOW());
+
+-- after: SummaryFinancialByMonth reads the aggregates
+EXPLAIN (ANALYZE, BUFFERS)
+SELECT
+  COALESCE(SUM(a.total_income), 0)::bigint AS total_income,
+  COALESCE(SUM(a.total_expense), 0)::bigint AS total_expense
+FROM monthly_aggregates a
+WHERE a.user_id = 'bench'
+  AND a.month = EXTRACT(MONTH FROM NOW())::int
+  AND a.year = EXTRACT(YEAR FROM NOW())::int;
+
+-- SummaryFinancialEachYear
+EXPLAIN (ANALYZE, BUFFERS)
+SELECT
+  (a.year - CASE WHEN a.month < u.fiscal_year_start_month THEN 1 ELSE 0 END)::INT AS year,
+  COALESCE(SUM(a.total_income), 0) AS in_amount,
+  COALESCE(SUM(a.total_expense), 0) AS out_amount
+FROM monthly_aggregates a
+JOIN users u ON u.username = a.user_id
+WHERE a.user_id = 'bench'
+GROUP BY 1
+ORDER BY 1;
+
+-- the aggregates match the records
+SELECT
+  (SELECT SUM(amount) FROM financials WHERE user_id = 'bench' AND deleted_at IS NULL) AS records,
+  (SELECT SUM(total_income + total_expense) FROM monthly_aggregates WHERE user_id = 'bench') AS aggregates;
+
+ROLLBACK;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS unlock_tokens;
+DROP TABLE IF EXISTS lockout_audits;
+DROP TABLE IF EXISTS login_attempts;
@@ -0,0 +1,29 @@
+CREATE TABLE login_attempts (
+    scope varchar NOT NULL CHECK (scope IN ('username', 'ip')),
+    identifier varchar NOT NULL,
+    failed_count INT NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (scope, identifier)
+);
+
+CREATE TABLE lockout_audits (
+    id BIGSERIAL PRIMARY KEY,
+    scope varchar NOT NULL,
+    identifier varchar NOT NULL,
+    failed_count INT NOT NULL,
+    client_ip varchar NOT NULL,
+    locked_until TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON lockout_audits (scope, identifier);
+
+CREATE TABLE unlock_tokens (
+    token varchar PRIMARY KEY,
+    username varchar NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+    expired_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
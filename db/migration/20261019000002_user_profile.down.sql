@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS contact_verifications;
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
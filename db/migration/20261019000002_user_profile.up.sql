@@ -0,0 +1,14 @@
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE TABLE contact_verifications (
+    id BIGSERIAL PRIMARY KEY,
+    username varchar NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+    field varchar NOT NULL CHECK (field IN ('email', 'phone')),
+    new_value varchar NOT NULL,
+    code varchar NOT NULL,
+    expired_at TIMESTAMPTZ NOT NULL,
+    verified_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON contact_verifications (username, code);
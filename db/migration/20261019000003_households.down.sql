@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS budgets_household_month_year_key;
+DROP INDEX IF EXISTS budgets_user_month_year_key;
+DELETE FROM budgets WHERE household_id IS NOT NULL;
+ALTER TABLE budgets ADD CONSTRAINT budgets_user_id_month_year_key UNIQUE (user_id, month, year);
+ALTER TABLE budgets DROP COLUMN IF EXISTS household_id;
+ALTER TABLE financials DROP COLUMN IF EXISTS household_id;
+DROP TABLE IF EXISTS household_invitations;
+DROP TABLE IF EXISTS household_members;
+DROP TABLE IF EXISTS households;
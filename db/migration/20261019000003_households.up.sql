@@ -0,0 +1,39 @@
+CREATE TABLE households (
+    id BIGSERIAL PRIMARY KEY,
+    name varchar NOT NULL,
+    owner varchar NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE household_members (
+    household_id BIGINT NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    username varchar NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+    role varchar NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    joined_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (household_id, username)
+);
+
+CREATE TABLE household_invitations (
+    id BIGSERIAL PRIMARY KEY,
+    household_id BIGINT NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    email varchar NOT NULL,
+    role varchar NOT NULL CHECK (role IN ('editor', 'viewer')),
+    token varchar UNIQUE NOT NULL,
+    invited_by varchar NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+    expired_at TIMESTAMPTZ NOT NULL,
+    accepted_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE financials ADD COLUMN household_id BIGINT REFERENCES households(id) ON DELETE SET NULL;
+
+CREATE INDEX ON financials (household_id);
+
+ALTER TABLE budgets ADD COLUMN household_id BIGINT REFERENCES households(id) ON DELETE CASCADE;
+
+ALTER TABLE budgets DROP CONSTRAINT budgets_user_id_month_year_key;
+
+CREATE UNIQUE INDEX budgets_user_month_year_key ON budgets (user_id, month, year) WHERE household_id IS NULL;
+
+CREATE UNIQUE INDEX budgets_household_month_year_key ON budgets (household_id, month, year) WHERE household_id IS NOT NULL;
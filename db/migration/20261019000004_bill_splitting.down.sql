@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS settlements;
+DROP TABLE IF EXISTS shared_expense_shares;
+DROP TABLE IF EXISTS shared_expenses;
+DROP TABLE IF EXISTS contacts;
@@ -0,0 +1,51 @@
+CREATE TABLE contacts (
+    id BIGSERIAL PRIMARY KEY,
+    owner varchar NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+    name varchar NOT NULL,
+    email varchar,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON contacts (owner);
+
+CREATE TABLE shared_expenses (
+    id BIGSERIAL PRIMARY KEY,
+    created_by varchar NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+    description varchar NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    paid_by_user varchar REFERENCES users(username) ON DELETE CASCADE,
+    paid_by_contact BIGINT REFERENCES contacts(id) ON DELETE CASCADE,
+    split_method varchar NOT NULL CHECK (split_method IN ('equal', 'shares', 'exact')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CHECK ((paid_by_user IS NULL) <> (paid_by_contact IS NULL))
+);
+
+CREATE TABLE shared_expense_shares (
+    id BIGSERIAL PRIMARY KEY,
+    expense_id BIGINT NOT NULL REFERENCES shared_expenses(id) ON DELETE CASCADE,
+    username varchar REFERENCES users(username) ON DELETE CASCADE,
+    contact_id BIGINT REFERENCES contacts(id) ON DELETE CASCADE,
+    amount BIGINT NOT NULL CHECK (amount >= 0),
+
+    CHECK ((username IS NULL) <> (contact_id IS NULL))
+);
+
+CREATE INDEX ON shared_expense_shares (expense_id);
+CREATE INDEX ON shared_expense_shares (username);
+
+CREATE TABLE settlements (
+    id BIGSERIAL PRIMARY KEY,
+    created_by varchar NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+    from_user varchar REFERENCES users(username) ON DELETE CASCADE,
+    from_contact BIGINT REFERENCES contacts(id) ON DELETE CASCADE,
+    to_user varchar REFERENCES users(username) ON DELETE CASCADE,
+    to_contact BIGINT REFERENCES contacts(id) ON DELETE CASCADE,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    from_financial_id BIGINT REFERENCES financials(id) ON DELETE SET NULL,
+    to_financial_id BIGINT REFERENCES financials(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CHECK ((from_user IS NULL) <> (from_contact IS NULL)),
+    CHECK ((to_user IS NULL) <> (to_contact IS NULL))
+);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS admin_audits;
+ALTER TABLE users DROP COLUMN IF EXISTS tokens_valid_after;
+ALTER TABLE users DROP COLUMN IF EXISTS disabled_at;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
@@ -0,0 +1,15 @@
+ALTER TABLE users ADD COLUMN role varchar NOT NULL DEFAULT 'user' CHECK (role IN ('user', 'admin'));
+ALTER TABLE users ADD COLUMN disabled_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN tokens_valid_after TIMESTAMPTZ;
+
+CREATE TABLE admin_audits (
+    id BIGSERIAL PRIMARY KEY,
+    admin varchar NOT NULL,
+    action varchar NOT NULL,
+    target varchar NOT NULL,
+    details jsonb NOT NULL DEFAULT '{}',
+    client_ip varchar NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON admin_audits (created_at);
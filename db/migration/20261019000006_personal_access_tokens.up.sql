@@ -0,0 +1,14 @@
+CREATE TABLE personal_access_tokens (
+    id BIGSERIAL PRIMARY KEY,
+    username varchar NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+    name varchar NOT NULL,
+    token_hash varchar UNIQUE NOT NULL,
+    prefix varchar NOT NULL,
+    scopes text[] NOT NULL,
+    expired_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON personal_access_tokens (username);
@@ -0,0 +1,18 @@
+CREATE TABLE oauth_states (
+    state varchar PRIMARY KEY,
+    nonce varchar NOT NULL,
+    code_verifier varchar NOT NULL,
+    expired_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE user_identities (
+    issuer varchar NOT NULL,
+    subject varchar NOT NULL,
+    username varchar NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+    email varchar NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (issuer, subject)
+);
+
+CREATE INDEX ON user_identities (username);
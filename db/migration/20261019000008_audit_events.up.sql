@@ -0,0 +1,29 @@
+CREATE TABLE audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    actor varchar NOT NULL,
+    action varchar NOT NULL,
+    entity_type varchar NOT NULL,
+    entity_id bigint NOT NULL,
+    before jsonb,
+    after jsonb,
+    ip varchar NOT NULL,
+    user_agent varchar NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON audit_events (entity_type, entity_id);
+
+-- audit events are append-only, rows can never be changed or removed
+CREATE FUNCTION reject_audit_event_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_append_only
+BEFORE UPDATE OR DELETE ON audit_events
+FOR EACH ROW EXECUTE FUNCTION reject_audit_event_change();
+
+CREATE TRIGGER audit_events_no_truncate
+BEFORE TRUNCATE ON audit_events
+FOR EACH STATEMENT EXECUTE FUNCTION reject_audit_event_change();
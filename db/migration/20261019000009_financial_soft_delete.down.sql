@@ -0,0 +1,3 @@
+DELETE FROM financials WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE financials DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,3 @@
+ALTER TABLE financials ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE INDEX ON financials (deleted_at) WHERE deleted_at IS NOT NULL;
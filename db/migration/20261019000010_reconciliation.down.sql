@@ -0,0 +1,4 @@
+ALTER TABLE financials DROP COLUMN IF EXISTS reconciliation_id;
+ALTER TABLE financials DROP COLUMN IF EXISTS reconcile_status;
+
+DROP TABLE IF EXISTS reconciliations;
@@ -0,0 +1,17 @@
+CREATE TABLE reconciliations (
+    id BIGSERIAL PRIMARY KEY,
+    user_id varchar NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+    statement_date date NOT NULL,
+    statement_balance bigint NOT NULL,
+    locked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- a user works on one statement at a time
+CREATE UNIQUE INDEX ON reconciliations (user_id) WHERE locked_at IS NULL;
+
+ALTER TABLE financials ADD COLUMN reconcile_status varchar NOT NULL DEFAULT 'uncleared'
+    CHECK (reconcile_status IN ('uncleared', 'cleared', 'reconciled'));
+ALTER TABLE financials ADD COLUMN reconciliation_id BIGINT REFERENCES reconciliations(id) ON DELETE SET NULL;
+
+CREATE INDEX ON financials (reconciliation_id);
@@ -0,0 +1,14 @@
+CREATE TABLE period_closings (
+    id BIGSERIAL PRIMARY KEY,
+    user_id varchar NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+    month int NOT NULL CHECK (month BETWEEN 1 AND 12),
+    year int NOT NULL,
+    total_income bigint NOT NULL,
+    total_expense bigint NOT NULL,
+    status varchar NOT NULL,
+    closed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    reopened_at TIMESTAMPTZ
+);
+
+-- a month can be closed and reopened many times, but only closed once at a time
+CREATE UNIQUE INDEX ON period_closings (user_id, year, month) WHERE reopened_at IS NULL;
@@ -0,0 +1,13 @@
+CREATE TABLE anomalies (
+    id BIGSERIAL PRIMARY KEY,
+    user_id varchar NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+    financial_id BIGINT REFERENCES financials(id) ON DELETE CASCADE,
+    type_id BIGINT REFERENCES financial_types(id) ON DELETE CASCADE,
+    kind varchar NOT NULL,
+    reason varchar NOT NULL,
+    -- the same finding is stored once even when both the live check and the batch see it
+    dedupe_key varchar UNIQUE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON anomalies (user_id, created_at);
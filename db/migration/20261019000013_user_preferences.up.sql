@@ -0,0 +1,2 @@
+ALTER TABLE users ADD COLUMN time_zone varchar NOT NULL DEFAULT 'UTC';
+ALTER TABLE users ADD COLUMN locale varchar NOT NULL DEFAULT 'en-US';
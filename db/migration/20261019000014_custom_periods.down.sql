@@ -0,0 +1,4 @@
+DROP FUNCTION IF EXISTS fiscal_year(timestamptz, varchar, integer, integer);
+DROP FUNCTION IF EXISTS period_date(timestamptz, varchar, integer);
+ALTER TABLE users DROP COLUMN IF EXISTS fiscal_year_start_month;
+ALTER TABLE users DROP COLUMN IF EXISTS period_start_day;
@@ -0,0 +1,19 @@
+ALTER TABLE users ADD COLUMN period_start_day integer NOT NULL DEFAULT 1 CHECK (period_start_day BETWEEN 1 AND 28);
+ALTER TABLE users ADD COLUMN fiscal_year_start_month integer NOT NULL DEFAULT 1 CHECK (fiscal_year_start_month BETWEEN 1 AND 12);
+
+-- period_date moves ts back so that its calendar month is the budgeting month holding ts.
+-- A budgeting month starts on start_day and is named after the calendar month it starts in.
+CREATE FUNCTION period_date(ts timestamptz, tz varchar, start_day integer)
+RETURNS timestamp
+LANGUAGE sql STABLE
+AS $$
+    SELECT (ts AT TIME ZONE tz) - make_interval(days => start_day - 1)
+$$;
+
+-- fiscal_year is the year holding ts when years start in start_month, named after the calendar year they start in.
+CREATE FUNCTION fiscal_year(ts timestamptz, tz varchar, start_day integer, start_month integer)
+RETURNS integer
+LANGUAGE sql STABLE
+AS $$
+    SELECT EXTRACT(YEAR FROM period_date(ts, tz, start_day) - make_interval(months => start_month - 1))::integer
+$$;
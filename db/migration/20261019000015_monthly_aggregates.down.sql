@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS financials_monthly_aggregates ON financials;
+DROP FUNCTION IF EXISTS maintain_monthly_aggregates();
+DROP FUNCTION IF EXISTS add_monthly_aggregate(financials, integer);
+DROP TABLE IF EXISTS monthly_aggregates;
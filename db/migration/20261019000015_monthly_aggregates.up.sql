@@ -0,0 +1,95 @@
+-- monthly_aggregates keeps the totals of every user, month and category so summaries do not scan financials.
+-- Months are the budgeting months of the user, see period_date.
+CREATE TABLE monthly_aggregates (
+    user_id varchar NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+    year integer NOT NULL,
+    month integer NOT NULL,
+    type_id bigint NOT NULL,
+    total_income bigint NOT NULL DEFAULT 0,
+    total_expense bigint NOT NULL DEFAULT 0,
+    record_count bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, year, month, type_id)
+);
+
+-- add_monthly_aggregate adds a record to its month, or takes it out again with sign -1.
+-- Deleted records are not counted.
+CREATE FUNCTION add_monthly_aggregate(f financials, sign integer) RETURNS void AS $$
+DECLARE
+    d timestamp;
+BEGIN
+    IF f.deleted_at IS NOT NULL THEN
+        RETURN;
+    END IF;
+
+    -- the share lock makes a rebuild of the user wait for this transaction and the other way round
+    SELECT period_date(f.created_at, u.time_zone, u.period_start_day) INTO d
+    FROM users u
+    WHERE u.username = f.user_id
+    FOR SHARE;
+
+    INSERT INTO monthly_aggregates AS a
+        (user_id, year, month, type_id, total_income, total_expense, record_count)
+    VALUES (
+        f.user_id,
+        EXTRACT(YEAR FROM d),
+        EXTRACT(MONTH FROM d),
+        f.type_id,
+        CASE WHEN f.direction = 'in' THEN sign * f.amount ELSE 0 END,
+        CASE WHEN f.direction = 'out' THEN sign * f.amount ELSE 0 END,
+        sign
+    )
+    ON CONFLICT (user_id, year, month, type_id) DO UPDATE SET
+        total_income = a.total_income + EXCLUDED.total_income,
+        total_expense = a.total_expense + EXCLUDED.total_expense,
+        record_count = a.record_count + EXCLUDED.record_count;
+
+    -- a month without records leaves no row behind
+    IF sign < 0 THEN
+        DELETE FROM monthly_aggregates
+        WHERE user_id = f.user_id
+          AND year = EXTRACT(YEAR FROM d)
+          AND month = EXTRACT(MONTH FROM d)
+          AND type_id = f.type_id
+          AND record_count = 0;
+    END IF;
+END;
+$$ LANGUAGE plpgsql;
+
+-- the aggregates change in the same transaction as the records
+CREATE FUNCTION maintain_monthly_aggregates() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'UPDATE'
+        AND (OLD.user_id, OLD.amount, OLD.direction, OLD.type_id, OLD.created_at, OLD.deleted_at)
+            IS NOT DISTINCT FROM (NEW.user_id, NEW.amount, NEW.direction, NEW.type_id, NEW.created_at, NEW.deleted_at) THEN
+        RETURN NULL;
+    END IF;
+
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM add_monthly_aggregate(OLD, -1);
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        PERFORM add_monthly_aggregate(NEW, 1);
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER financials_monthly_aggregates
+AFTER INSERT OR UPDATE OR DELETE ON financials
+FOR EACH ROW EXECUTE FUNCTION maintain_monthly_aggregates();
+
+INSERT INTO monthly_aggregates (user_id, year, month, type_id, total_income, total_expense, record_count)
+SELECT
+    f.user_id,
+    EXTRACT(YEAR FROM period_date(f.created_at, u.time_zone, u.period_start_day)),
+    EXTRACT(MONTH FROM period_date(f.created_at, u.time_zone, u.period_start_day)),
+    f.type_id,
+    COALESCE(SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END), 0),
+    COALESCE(SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END), 0),
+    COUNT(*)
+FROM financials f
+JOIN users u ON u.username = f.user_id
+WHERE f.deleted_at IS NULL
+GROUP BY 1, 2, 3, 4;
@@ -0,0 +1,3 @@
+ALTER TABLE settlements
+    DROP COLUMN IF EXISTS responded_at,
+    DROP COLUMN IF EXISTS status;
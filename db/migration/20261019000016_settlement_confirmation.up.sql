@@ -0,0 +1,8 @@
+-- a settlement with another registered user is pending until that user confirms it,
+-- their financial record is only written then
+ALTER TABLE settlements
+    ADD COLUMN status varchar NOT NULL DEFAULT 'confirmed' CHECK (status IN ('pending', 'confirmed', 'rejected')),
+    ADD COLUMN responded_at TIMESTAMPTZ;
+
+CREATE INDEX ON settlements (to_user) WHERE status = 'pending';
+CREATE INDEX ON settlements (from_user) WHERE status = 'pending';
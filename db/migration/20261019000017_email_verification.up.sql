@@ -0,0 +1,2 @@
+-- set once the user proves they own the address, accounts are only linked to an identity provider by a verified email
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMPTZ;
@@ -0,0 +1 @@
+ALTER TABLE contact_verifications DROP COLUMN IF EXISTS failed_attempts;
@@ -0,0 +1,2 @@
+-- a verification stops accepting codes after too many wrong ones, so its code cannot be guessed
+ALTER TABLE contact_verifications ADD COLUMN failed_attempts int NOT NULL DEFAULT 0;
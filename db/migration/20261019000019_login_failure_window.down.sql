@@ -0,0 +1,5 @@
+-- the hashes cannot be turned back into tokens, the pending unlock tokens are dropped
+DELETE FROM unlock_tokens;
+ALTER TABLE unlock_tokens RENAME COLUMN token_hash TO token;
+
+DROP TABLE IF EXISTS login_failures;
@@ -0,0 +1,13 @@
+-- every failed login is kept for the sliding window, the failed_count of login_attempts is the number inside it
+CREATE TABLE login_failures (
+    id BIGSERIAL PRIMARY KEY,
+    scope varchar NOT NULL CHECK (scope IN ('username', 'ip')),
+    identifier varchar NOT NULL,
+    failed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON login_failures (scope, identifier, failed_at);
+
+-- unlock tokens are stored as their sha256 hash, like personal access tokens
+ALTER TABLE unlock_tokens RENAME COLUMN token TO token_hash;
+UPDATE unlock_tokens SET token_hash = encode(sha256(convert_to(token_hash, 'UTF8')), 'hex');
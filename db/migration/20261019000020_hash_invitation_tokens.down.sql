@@ -0,0 +1,3 @@
+-- the hashes cannot be turned back into tokens, the pending invitations are dropped
+DELETE FROM household_invitations WHERE accepted_at IS NULL;
+ALTER TABLE household_invitations RENAME COLUMN token_hash TO token;
@@ -0,0 +1,3 @@
+-- household invitation tokens are stored as their sha256 hash, like unlock tokens and personal access tokens
+ALTER TABLE household_invitations RENAME COLUMN token TO token_hash;
+UPDATE household_invitations SET token_hash = encode(sha256(convert_to(token_hash, 'UTF8')), 'hex');
@@ -0,0 +1,26 @@
+-- name: CreatePersonalAccessToken :one
+INSERT INTO personal_access_tokens
+    (username, name, token_hash, prefix, scopes, expired_at)
+VALUES
+    ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetPersonalAccessTokenByHash :one
+SELECT * FROM personal_access_tokens
+WHERE token_hash = $1;
+
+-- name: ListPersonalAccessTokens :many
+SELECT * FROM personal_access_tokens
+WHERE username = $1
+ORDER BY created_at DESC;
+
+-- name: RevokePersonalAccessToken :one
+UPDATE personal_access_tokens
+SET revoked_at = NOW()
+WHERE id = $1 AND username = $2 AND revoked_at IS NULL
+RETURNING *;
+
+-- name: TouchPersonalAccessToken :exec
+UPDATE personal_access_tokens
+SET last_used_at = NOW()
+WHERE id = $1;
@@ -0,0 +1,49 @@
+-- name: SearchUsers :many
+-- pattern is an ILIKE pattern, escape the search term with a backslash.
+SELECT * FROM users
+WHERE username ILIKE @pattern::text
+   OR email ILIKE @pattern::text
+   OR name ILIKE @pattern::text
+ORDER BY username
+LIMIT @page_limit::int OFFSET @page_offset::int;
+
+-- name: SetUserRole :one
+UPDATE users
+SET role = $1, updated_at = NOW()
+WHERE username = $2
+RETURNING *;
+
+-- name: SetUserDisabled :one
+UPDATE users
+SET disabled_at = CASE WHEN @disabled::bool THEN NOW() ELSE NULL END,
+    updated_at = NOW()
+WHERE username = @username::text
+RETURNING *;
+
+-- name: RevokeUserTokens :one
+UPDATE users
+SET tokens_valid_after = NOW()
+WHERE username = $1
+RETURNING *;
+
+-- name: GetSystemStats :one
+SELECT
+  (SELECT COUNT(*) FROM users) AS total_users,
+  (SELECT COUNT(*) FROM users WHERE disabled_at IS NOT NULL) AS disabled_users,
+  (SELECT COUNT(*) FROM users WHERE deleted_at IS NOT NULL) AS deleted_users,
+  (SELECT COUNT(*) FROM financials WHERE deleted_at IS NULL) AS total_financials,
+  (SELECT COUNT(*) FROM financials WHERE deleted_at IS NULL AND created_at > NOW() - INTERVAL '30 days') AS financials_last_30_days,
+  (SELECT COUNT(*) FROM budgets) AS total_budgets,
+  (SELECT COUNT(*) FROM households) AS total_households;
+
+-- name: CreateAdminAudit :one
+INSERT INTO admin_audits
+    (admin, action, target, details, client_ip)
+VALUES
+    ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ListAdminAudits :many
+SELECT * FROM admin_audits
+ORDER BY created_at DESC
+LIMIT $1 OFFSET $2;
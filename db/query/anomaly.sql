@@ -0,0 +1,37 @@
+-- name: CreateAnomaly :one
+INSERT INTO anomalies
+    (user_id, financial_id, type_id, kind, reason, dedupe_key)
+VALUES
+    ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (dedupe_key) DO NOTHING
+RETURNING *;
+
+-- name: ListAnomalies :many
+SELECT a.id, a.financial_id, a.kind, a.reason, a.created_at,
+  f.amount, f.direction, ft.type
+FROM anomalies a
+LEFT JOIN financials f ON (a.financial_id = f.id)
+LEFT JOIN financial_types ft ON (a.type_id = ft.id)
+WHERE a.user_id = $1
+ORDER BY a.created_at DESC, a.id DESC;
+
+-- name: ListTypeFinancialsSince :many
+SELECT * FROM financials
+WHERE user_id = $1 AND type_id = $2 AND created_at >= $3 AND deleted_at IS NULL
+ORDER BY created_at;
+
+-- name: ListFinancialsSince :many
+SELECT * FROM financials
+WHERE created_at >= $1 AND deleted_at IS NULL
+ORDER BY user_id, id;
+
+-- name: TypeMonthlyTotals :many
+SELECT date_trunc('month', created_at, (SELECT time_zone FROM users WHERE username = $1))::timestamptz AS month, SUM(amount)::bigint AS total
+FROM financials
+WHERE user_id = $1 AND type_id = $2 AND created_at >= $3 AND deleted_at IS NULL
+GROUP BY 1
+ORDER BY 1;
+
+-- name: UserAmountsSince :many
+SELECT amount FROM financials
+WHERE user_id = $1 AND direction = $2 AND created_at >= $3 AND deleted_at IS NULL;
@@ -0,0 +1,11 @@
+-- name: CreateAuditEvent :one
+INSERT INTO audit_events
+    (actor, action, entity_type, entity_id, before, after, ip, user_agent)
+VALUES
+    ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: ListAuditEvents :many
+SELECT * FROM audit_events
+WHERE entity_type = $1 AND entity_id = $2
+ORDER BY id;
@@ -8,20 +8,51 @@ RETURNING *;
 -- name: GetBudget :one
 SELECT * FROM budgets
 WHERE month = $1 AND year = $2 
-AND user_id = $3 ;
+AND user_id = $3 AND household_id IS NULL;
 
 -- name: GetBudgetHistory :many
 SELECT * FROM budgets
-WHERE user_id = $1
+WHERE user_id = $1 AND household_id IS NULL
 LIMIT 12;
 
 -- name: GetBudgetHistoryByYear :one
 SELECT * FROM budgets
-WHERE user_id = $1 AND year = $2
+WHERE user_id = $1 AND year = $2 AND household_id IS NULL
 LIMIT 12;
 
 -- name: UpdateBudget :one
 UPDATE budgets
 SET amount = $1
 WHERE id = $2
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: ReassignHouseholdBudgets :many
+-- the household budgets a member set go to the household owner before the member is purged.
+UPDATE budgets b
+SET user_id = h.owner, updated_at = NOW()
+FROM households h
+WHERE b.household_id = h.id AND b.user_id = @username::text AND h.owner <> @username::text
+RETURNING b.*;
+
+-- name: DeleteUserBudgets :many
+-- only personal budgets, household budgets belong to the household and stay.
+DELETE FROM budgets
+WHERE user_id = $1 AND household_id IS NULL
+RETURNING *;
+
+
+-- name: AddNewHouseholdBudget :one
+INSERT INTO budgets
+    (user_id, household_id, month, year, amount)
+VALUES
+    ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetHouseholdBudget :one
+SELECT * FROM budgets
+WHERE household_id = $1 AND month = $2 AND year = $3;
+
+-- name: GetBudgetForUpdate :one
+SELECT * FROM budgets
+WHERE id = $1
+FOR UPDATE;
@@ -1,112 +1,151 @@
 
 -- name: InsertNewFinancial :one
 INSERT INTO financials
-    (user_id, amount, direction, type_id)
+    (user_id, amount, direction, type_id, household_id)
 VALUES 
-    ($1, $2, $3, $4)
+    ($1, $2, $3, $4, $5)
 RETURNING *;
 
 -- name: UpdateFinancial :one
 UPDATE financials
 SET amount = $1, direction = $2, type_id = $3
-WHERE id = $4
+WHERE id = $4 AND deleted_at IS NULL
 RETURNING *;
 
 -- name: DeleteFinancial :one
-DELETE FROM financials 
-WHERE id = $1
+UPDATE financials
+SET deleted_at = NOW(),
+    reconcile_status = 'uncleared',
+    reconciliation_id = NULL
+WHERE id = $1 AND deleted_at IS NULL AND reconcile_status <> 'reconciled'
 RETURNING *;
 
 -- name: GetFinancialById :one
 SELECT f.id, f.amount, f.direction, ft.type, f.created_at
 FROM financials f
 LEFT JOIN financial_types ft ON (f.type_id = ft.id) 
-WHERE f.id = $1;
+WHERE f.id = $1 AND f.deleted_at IS NULL;
 
 -- name: GetFinancialOwner :one
 SELECT user_id FROM financials
 WHERE id = $1;
 
+-- name: GetFinancialAccess :one
+SELECT user_id, household_id FROM financials
+WHERE id = $1;
+
 -- name: MyFinancial :many
 SELECT f.id, f.amount, f.direction, ft.type, f.created_at
 FROM financials f
 LEFT JOIN financial_types ft ON (f.type_id = ft.id) 
-WHERE f.user_id = $1;
+WHERE f.user_id = $1 AND f.deleted_at IS NULL;
 
 -- name: SummaryFinancialByMonth :one
 SELECT 
-  SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END) AS total_income,
-  SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END) AS total_expense,
+  COALESCE(SUM(a.total_income), 0)::bigint AS total_income,
+  COALESCE(SUM(a.total_expense), 0)::bigint AS total_expense,
   CASE
-    WHEN SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END) > SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END) THEN 'in'
-    WHEN SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END) < SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END) THEN 'out'
+    WHEN SUM(a.total_income) > SUM(a.total_expense) THEN 'in'
+    WHEN SUM(a.total_income) < SUM(a.total_expense) THEN 'out'
     ELSE 'equal'
   END AS status
-FROM financials f
-WHERE f.user_id = @user_id::text
-  AND EXTRACT(MONTH FROM f.created_at) = @month::int
-  AND EXTRACT(YEAR FROM f.created_at) = @year::int;
+FROM monthly_aggregates a
+WHERE a.user_id = @user_id::text
+  AND a.month = @month::int
+  AND a.year = @year::int;
 
 -- name: SummaryFinancialByYear :one
 SELECT 
-  SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END) AS total_income,
-  SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END) AS total_expense,
+  COALESCE(SUM(a.total_income), 0)::bigint AS total_income,
+  COALESCE(SUM(a.total_expense), 0)::bigint AS total_expense,
   CASE
-    WHEN SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END) > SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END) THEN 'in'
-    WHEN SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END) < SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END) THEN 'out'
+    WHEN SUM(a.total_income) > SUM(a.total_expense) THEN 'in'
+    WHEN SUM(a.total_income) < SUM(a.total_expense) THEN 'out'
     ELSE 'equal'
   END AS status
-FROM financials f
-WHERE f.user_id = @user_id::text
-  AND EXTRACT(YEAR FROM f.created_at) = @year::int;
+FROM monthly_aggregates a
+JOIN users u ON u.username = a.user_id
+WHERE a.user_id = @user_id::text
+  AND (a.year - CASE WHEN a.month < u.fiscal_year_start_month THEN 1 ELSE 0 END) = @year::int;
 
 
 -- name: SummaryByTypeMonth :many
 SELECT 
   ft.type,
-  SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END) AS total_income,
-  SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END) AS total_expense,
+  SUM(a.total_income) AS total_income,
+  SUM(a.total_expense) AS total_expense,
   CASE
-    WHEN SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END) > SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END) THEN 'in'
-    WHEN SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END) < SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END) THEN 'out'
+    WHEN SUM(a.total_income) > SUM(a.total_expense) THEN 'in'
+    WHEN SUM(a.total_income) < SUM(a.total_expense) THEN 'out'
     ELSE 'equal'
   END AS status
-FROM financials f
-JOIN financial_types ft ON ft.id = f.type_id
-WHERE f.user_id = @user_id::text
-  AND EXTRACT(MONTH FROM f.created_at) = @month::int
-  AND EXTRACT(YEAR FROM f.created_at) = @year::int
+FROM monthly_aggregates a
+JOIN financial_types ft ON ft.id = a.type_id
+WHERE a.user_id = @user_id::text
+  AND a.month = @month::int
+  AND a.year = @year::int
 GROUP BY ft.type
 ORDER BY ft.type;
 
 -- name: SummaryByTypeYear :many
 SELECT 
   ft.type,
-  SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END) AS total_income,
-  SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END) AS total_expense,
+  SUM(a.total_income) AS total_income,
+  SUM(a.total_expense) AS total_expense,
   CASE
-    WHEN SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END) > SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END) THEN 'in'
-    WHEN SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END) < SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END) THEN 'out'
+    WHEN SUM(a.total_income) > SUM(a.total_expense) THEN 'in'
+    WHEN SUM(a.total_income) < SUM(a.total_expense) THEN 'out'
     ELSE 'equal'
   END AS status
-FROM financials f
-JOIN financial_types ft ON ft.id = f.type_id
-WHERE f.user_id = @user_id::text
-  AND EXTRACT(YEAR FROM f.created_at) = @year::int
+FROM monthly_aggregates a
+JOIN financial_types ft ON ft.id = a.type_id
+JOIN users u ON u.username = a.user_id
+WHERE a.user_id = @user_id::text
+  AND (a.year - CASE WHEN a.month < u.fiscal_year_start_month THEN 1 ELSE 0 END) = @year::int
 GROUP BY ft.type
 ORDER BY ft.type;
 
 -- name: SummaryFinancialEachYear :many
 SELECT 
-    EXTRACT(YEAR FROM f.created_at)::INT AS year,
-    COALESCE(SUM(CASE WHEN f.direction = 'in' THEN f.amount END), 0) AS in_amount,
-    COALESCE(SUM(CASE WHEN f.direction = 'out' THEN f.amount END), 0) AS out_amount,
+    (a.year - CASE WHEN a.month < u.fiscal_year_start_month THEN 1 ELSE 0 END)::INT AS year,
+    COALESCE(SUM(a.total_income), 0) AS in_amount,
+    COALESCE(SUM(a.total_expense), 0) AS out_amount,
     CASE
-        WHEN COALESCE(SUM(CASE WHEN f.direction = 'in' THEN f.amount END), 0) > COALESCE(SUM(CASE WHEN f.direction = 'out' THEN f.amount END), 0) THEN 'in'
-        WHEN COALESCE(SUM(CASE WHEN f.direction = 'in' THEN f.amount END), 0) < COALESCE(SUM(CASE WHEN f.direction = 'out' THEN f.amount END), 0) THEN 'out'
+        WHEN COALESCE(SUM(a.total_income), 0) > COALESCE(SUM(a.total_expense), 0) THEN 'in'
+        WHEN COALESCE(SUM(a.total_income), 0) < COALESCE(SUM(a.total_expense), 0) THEN 'out'
         ELSE 'equal'
     END AS status
+FROM monthly_aggregates a
+JOIN users u ON u.username = a.user_id
+WHERE a.user_id = @user_id::text
+GROUP BY 1
+ORDER BY 1;
+
+-- name: DeleteUserFinancials :many
+DELETE FROM financials
+WHERE user_id = $1
+RETURNING *;
+
+-- name: GetFinancialForUpdate :one
+SELECT * FROM financials
+WHERE id = $1
+FOR UPDATE;
+
+-- name: RestoreFinancial :one
+UPDATE financials
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: ListDeletedFinancials :many
+SELECT f.id, f.amount, f.direction, ft.type, f.created_at, f.deleted_at
 FROM financials f
-WHERE f.user_id = @user_id::text
-GROUP BY year
-ORDER BY year;
+LEFT JOIN financial_types ft ON (f.type_id = ft.id)
+WHERE f.user_id = $1 AND f.deleted_at IS NOT NULL
+ORDER BY f.deleted_at DESC;
+
+-- name: PurgeDeletedFinancials :many
+-- reconciled financials are kept, they are part of a finished statement.
+DELETE FROM financials
+WHERE deleted_at < $1 AND reconcile_status <> 'reconciled'
+RETURNING *;
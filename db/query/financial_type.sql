@@ -1,3 +1,28 @@
 -- name: GetFinancialByName :one
 SELECT * FROM financial_types
-WHERE type = $1;
\ No newline at end of file
+WHERE type = $1;
+
+-- name: GetFinancialTypeForUpdate :one
+SELECT * FROM financial_types
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListFinancialTypes :many
+SELECT * FROM financial_types
+ORDER BY id;
+
+-- name: CreateFinancialType :one
+INSERT INTO financial_types (type)
+VALUES ($1)
+RETURNING *;
+
+-- name: UpdateFinancialType :one
+UPDATE financial_types
+SET type = $1
+WHERE id = $2
+RETURNING *;
+
+-- name: DeleteFinancialType :one
+DELETE FROM financial_types
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,82 @@
+-- name: CreateHousehold :one
+INSERT INTO households
+    (name, owner)
+VALUES
+    ($1, $2)
+RETURNING *;
+
+-- name: GetHousehold :one
+SELECT * FROM households
+WHERE id = $1;
+
+-- name: ListMyHouseholds :many
+SELECT h.id, h.name, h.owner, m.role, h.created_at
+FROM households h
+JOIN household_members m ON m.household_id = h.id
+WHERE m.username = $1
+ORDER BY h.id;
+
+-- name: AddHouseholdMember :one
+INSERT INTO household_members
+    (household_id, username, role)
+VALUES
+    ($1, $2, $3)
+RETURNING *;
+
+-- name: GetHouseholdMember :one
+SELECT * FROM household_members
+WHERE household_id = $1 AND username = $2;
+
+-- name: ListHouseholdMembers :many
+SELECT m.username, u.name, m.role, m.joined_at
+FROM household_members m
+JOIN users u ON u.username = m.username
+WHERE m.household_id = $1
+ORDER BY m.joined_at;
+
+-- name: UpdateHouseholdMemberRole :one
+UPDATE household_members
+SET role = $1
+WHERE household_id = $2 AND username = $3
+RETURNING *;
+
+-- name: RemoveHouseholdMember :exec
+DELETE FROM household_members
+WHERE household_id = $1 AND username = $2;
+
+-- name: CreateHouseholdInvitation :one
+INSERT INTO household_invitations
+    (household_id, email, role, token_hash, invited_by, expired_at)
+VALUES
+    ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetHouseholdInvitation :one
+SELECT * FROM household_invitations
+WHERE token_hash = $1;
+
+-- name: AcceptHouseholdInvitation :exec
+UPDATE household_invitations
+SET accepted_at = NOW()
+WHERE id = $1;
+
+-- name: HouseholdFinancials :many
+SELECT f.id, f.user_id, f.amount, f.direction, ft.type, f.created_at
+FROM financials f
+LEFT JOIN financial_types ft ON (f.type_id = ft.id)
+WHERE f.household_id = $1 AND f.deleted_at IS NULL
+ORDER BY f.created_at DESC;
+
+-- name: SummaryHouseholdByMember :many
+SELECT
+  f.user_id,
+  SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END)::bigint AS total_income,
+  SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END)::bigint AS total_expense
+FROM financials f
+JOIN users u ON u.username = f.user_id
+WHERE f.household_id = @household_id::bigint
+  AND f.deleted_at IS NULL
+  AND EXTRACT(MONTH FROM period_date(f.created_at, u.time_zone, u.period_start_day)) = @month::int
+  AND EXTRACT(YEAR FROM period_date(f.created_at, u.time_zone, u.period_start_day)) = @year::int
+GROUP BY f.user_id
+ORDER BY f.user_id;
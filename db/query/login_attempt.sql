@@ -0,0 +1,67 @@
+-- name: GetLoginAttempt :one
+SELECT * FROM login_attempts
+WHERE scope = $1 AND identifier = $2;
+
+-- name: LockLoginAttempt :one
+-- LockLoginAttempt creates the row of a key when it is missing and locks it until the end of the transaction.
+INSERT INTO login_attempts (scope, identifier)
+VALUES ($1, $2)
+ON CONFLICT (scope, identifier) DO UPDATE
+SET scope = EXCLUDED.scope
+RETURNING *;
+
+-- name: RecordFailedLogin :one
+-- failed_count is the number of failures since window_start, the new failure included.
+-- The key then waits waits[failed_count] microseconds, the last wait once failed_count is past the end of waits.
+WITH failure AS (
+    INSERT INTO login_failures (scope, identifier)
+    VALUES (@scope, @identifier)
+)
+UPDATE login_attempts a
+SET failed_count = c.failed_count,
+    last_failed_at = NOW(),
+    locked_until = GREATEST(
+        a.locked_until,
+        NOW() + (@waits::bigint[])[LEAST(c.failed_count, cardinality(@waits::bigint[]))] * INTERVAL '1 microsecond'
+    )
+FROM (
+    SELECT 1 + COUNT(*)::int AS failed_count FROM login_failures
+    WHERE scope = @scope AND identifier = @identifier AND failed_at > @window_start::timestamptz
+) c
+WHERE a.scope = @scope AND a.identifier = @identifier
+RETURNING a.*;
+
+-- name: ResetLoginAttempts :exec
+WITH failures AS (
+    DELETE FROM login_failures
+    WHERE scope = $1 AND identifier = $2
+)
+DELETE FROM login_attempts
+WHERE scope = $1 AND identifier = $2;
+
+-- name: DeleteLoginFailuresBefore :exec
+DELETE FROM login_failures
+WHERE failed_at < $1;
+
+-- name: CreateLockoutAudit :one
+INSERT INTO lockout_audits
+    (scope, identifier, failed_count, client_ip, locked_until)
+VALUES
+    ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: CreateUnlockToken :one
+INSERT INTO unlock_tokens
+    (token_hash, username, expired_at)
+VALUES
+    ($1, $2, $3)
+RETURNING *;
+
+-- name: GetUnlockToken :one
+SELECT * FROM unlock_tokens
+WHERE token_hash = $1;
+
+-- name: UseUnlockToken :exec
+UPDATE unlock_tokens
+SET used_at = NOW()
+WHERE token_hash = $1;
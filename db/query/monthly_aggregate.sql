@@ -0,0 +1,23 @@
+-- name: LockUser :exec
+SELECT username FROM users
+WHERE username = $1
+FOR UPDATE;
+
+-- name: DeleteMonthlyAggregates :exec
+DELETE FROM monthly_aggregates
+WHERE user_id = $1;
+
+-- name: RebuildMonthlyAggregates :exec
+INSERT INTO monthly_aggregates (user_id, year, month, type_id, total_income, total_expense, record_count)
+SELECT
+  f.user_id,
+  EXTRACT(YEAR FROM period_date(f.created_at, u.time_zone, u.period_start_day)),
+  EXTRACT(MONTH FROM period_date(f.created_at, u.time_zone, u.period_start_day)),
+  f.type_id,
+  COALESCE(SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END), 0),
+  COALESCE(SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END), 0),
+  COUNT(*)
+FROM financials f
+JOIN users u ON u.username = f.user_id
+WHERE f.user_id = $1 AND f.deleted_at IS NULL
+GROUP BY 1, 2, 3, 4;
@@ -0,0 +1,26 @@
+-- name: CreateOAuthState :one
+INSERT INTO oauth_states
+    (state, nonce, code_verifier, expired_at)
+VALUES
+    ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: ConsumeOAuthState :one
+DELETE FROM oauth_states
+WHERE state = $1
+RETURNING *;
+
+-- name: DeleteExpiredOAuthStates :exec
+DELETE FROM oauth_states
+WHERE expired_at < NOW();
+
+-- name: GetUserIdentity :one
+SELECT * FROM user_identities
+WHERE issuer = $1 AND subject = $2;
+
+-- name: CreateUserIdentity :one
+INSERT INTO user_identities
+    (issuer, subject, username, email)
+VALUES
+    ($1, $2, $3, $4)
+RETURNING *;
@@ -0,0 +1,40 @@
+-- name: CreatePeriodClosing :one
+INSERT INTO period_closings
+    (user_id, month, year, total_income, total_expense, status)
+VALUES
+    ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: IsPeriodClosed :one
+SELECT EXISTS (
+    SELECT 1 FROM period_closings
+    WHERE user_id = $1 AND month = $2 AND year = $3 AND reopened_at IS NULL
+) AS closed;
+
+-- name: IsPeriodClosedAt :one
+SELECT EXISTS (
+    SELECT 1 FROM period_closings pc
+    JOIN users u ON u.username = pc.user_id
+    WHERE pc.user_id = @user_id::text
+      AND pc.month = EXTRACT(MONTH FROM period_date(@at::timestamptz, u.time_zone, u.period_start_day))
+      AND pc.year = EXTRACT(YEAR FROM period_date(@at::timestamptz, u.time_zone, u.period_start_day))
+      AND pc.reopened_at IS NULL
+) AS closed;
+
+-- name: ReopenPeriod :one
+UPDATE period_closings
+SET reopened_at = NOW()
+WHERE user_id = $1 AND month = $2 AND year = $3 AND reopened_at IS NULL
+RETURNING *;
+
+-- name: ListPeriodClosings :many
+-- ListPeriodClosings lists the closed months with their totals as they are now, read from the aggregates.
+SELECT pc.*,
+  COALESCE(SUM(a.total_income), 0)::bigint AS current_income,
+  COALESCE(SUM(a.total_expense), 0)::bigint AS current_expense
+FROM period_closings pc
+LEFT JOIN monthly_aggregates a
+  ON a.user_id = pc.user_id AND a.month = pc.month AND a.year = pc.year
+WHERE pc.user_id = $1 AND pc.reopened_at IS NULL
+GROUP BY pc.id
+ORDER BY pc.year DESC, pc.month DESC;
@@ -0,0 +1,79 @@
+-- name: CreateReconciliation :one
+INSERT INTO reconciliations
+    (user_id, statement_date, statement_balance)
+VALUES
+    ($1, $2, $3)
+RETURNING *;
+
+-- name: GetReconciliation :one
+SELECT * FROM reconciliations
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetReconciliationForUpdate :one
+SELECT * FROM reconciliations
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListReconciliations :many
+SELECT * FROM reconciliations
+WHERE user_id = $1
+ORDER BY statement_date DESC, id DESC;
+
+-- name: DeleteReconciliation :one
+DELETE FROM reconciliations
+WHERE id = $1 AND locked_at IS NULL
+RETURNING *;
+
+-- name: LockReconciliation :one
+UPDATE reconciliations
+SET locked_at = NOW()
+WHERE id = $1 AND locked_at IS NULL
+RETURNING *;
+
+-- name: ReconciliationTotals :one
+SELECT
+  COALESCE(SUM(amount) FILTER (WHERE reconcile_status = 'reconciled'), 0)::bigint AS reconciled_balance,
+  COALESCE(SUM(amount) FILTER (WHERE reconcile_status = 'cleared' AND reconciliation_id = @reconciliation_id::bigint), 0)::bigint AS cleared_balance,
+  COUNT(*) FILTER (WHERE reconcile_status = 'cleared' AND reconciliation_id = @reconciliation_id::bigint) AS cleared_count
+FROM financials
+WHERE user_id = @user_id::text AND deleted_at IS NULL;
+
+-- name: ListReconciliationCandidates :many
+SELECT f.id, f.amount, f.direction, ft.type, f.created_at, f.reconcile_status
+FROM financials f
+LEFT JOIN financial_types ft ON (f.type_id = ft.id)
+WHERE f.user_id = @user_id::text
+  AND f.deleted_at IS NULL
+  AND f.created_at < @created_before::timestamptz
+  AND (f.reconcile_status = 'uncleared'
+    OR (f.reconcile_status = 'cleared' AND f.reconciliation_id = @reconciliation_id::bigint))
+ORDER BY f.created_at;
+
+-- name: ClearFinancials :many
+UPDATE financials
+SET reconcile_status = 'cleared', reconciliation_id = @reconciliation_id::bigint
+WHERE id = ANY(@ids::bigint[])
+  AND user_id = @user_id::text
+  AND deleted_at IS NULL
+  AND reconcile_status = 'uncleared'
+  AND created_at < @created_before::timestamptz
+RETURNING id;
+
+-- name: UnclearFinancials :many
+UPDATE financials
+SET reconcile_status = 'uncleared', reconciliation_id = NULL
+WHERE id = ANY(@ids::bigint[])
+  AND reconciliation_id = @reconciliation_id::bigint
+  AND deleted_at IS NULL
+  AND reconcile_status = 'cleared'
+RETURNING id;
+
+-- name: ResetClearedFinancials :exec
+UPDATE financials
+SET reconcile_status = 'uncleared', reconciliation_id = NULL
+WHERE reconciliation_id = $1 AND reconcile_status = 'cleared';
+
+-- name: MarkFinancialsReconciled :exec
+UPDATE financials
+SET reconcile_status = 'reconciled'
+WHERE reconciliation_id = $1 AND reconcile_status = 'cleared' AND deleted_at IS NULL;
@@ -0,0 +1,55 @@
+-- name: LedgerBalance :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS balance
+FROM financials
+WHERE user_id = $1 AND deleted_at IS NULL;
+
+-- name: FirstFinancialAt :one
+SELECT COALESCE(MIN(created_at), NOW())::timestamptz AS first_at
+FROM financials
+WHERE user_id = $1 AND deleted_at IS NULL;
+
+-- name: SummaryByTypeBetween :many
+SELECT
+  COALESCE(ft.type, 'Other')::text AS type,
+  COALESCE(SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END), 0)::bigint AS total_income,
+  COALESCE(SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END), 0)::bigint AS total_expense
+FROM financials f
+LEFT JOIN financial_types ft ON (f.type_id = ft.id)
+WHERE f.user_id = @user_id::text
+  AND f.deleted_at IS NULL
+  AND f.created_at >= @from_time::timestamptz
+  AND f.created_at < @to_time::timestamptz
+GROUP BY 1
+ORDER BY 1;
+
+-- name: MonthlySummaryByTypeBetween :many
+-- month is the budgeting month of the user as YYYY-MM, see period_date.
+SELECT
+  COALESCE(ft.type, 'Other')::text AS type,
+  to_char(period_date(f.created_at, u.time_zone, u.period_start_day), 'YYYY-MM')::text AS month,
+  COALESCE(SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END), 0)::bigint AS total_income,
+  COALESCE(SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END), 0)::bigint AS total_expense
+FROM financials f
+LEFT JOIN financial_types ft ON (f.type_id = ft.id)
+JOIN users u ON u.username = f.user_id
+WHERE f.user_id = @user_id::text
+  AND f.deleted_at IS NULL
+  AND f.created_at >= @from_time::timestamptz
+  AND f.created_at < @to_time::timestamptz
+GROUP BY 1, 2
+ORDER BY 1, 2;
+
+-- name: DailySummaryBetween :many
+SELECT
+  date_trunc('day', f.created_at, @time_zone::text)::timestamptz AS day,
+  (CASE WHEN @group_by::text = 'type' THEN COALESCE(ft.type, 'Other') ELSE '' END)::text AS group_key,
+  COALESCE(SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END), 0)::bigint AS total_income,
+  COALESCE(SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END), 0)::bigint AS total_expense
+FROM financials f
+LEFT JOIN financial_types ft ON (f.type_id = ft.id)
+WHERE f.user_id = @user_id::text
+  AND f.deleted_at IS NULL
+  AND f.created_at >= @from_time::timestamptz
+  AND f.created_at < @to_time::timestamptz
+GROUP BY 1, 2
+ORDER BY 1, 2;
@@ -0,0 +1,114 @@
+-- name: CreateContact :one
+INSERT INTO contacts
+    (owner, name, email)
+VALUES
+    ($1, $2, $3)
+RETURNING *;
+
+-- name: GetContact :one
+SELECT * FROM contacts
+WHERE id = $1 AND owner = $2;
+
+-- name: ListContacts :many
+SELECT * FROM contacts
+WHERE owner = $1
+ORDER BY name;
+
+-- name: ListContactsByIds :many
+SELECT * FROM contacts
+WHERE id = ANY(@ids::bigint[]);
+
+-- name: CreateSharedExpense :one
+INSERT INTO shared_expenses
+    (created_by, description, amount, paid_by_user, paid_by_contact, split_method)
+VALUES
+    ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: AddSharedExpenseShare :one
+INSERT INTO shared_expense_shares
+    (expense_id, username, contact_id, amount)
+VALUES
+    ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: ListSharedExpensesForUser :many
+SELECT * FROM shared_expenses e
+WHERE e.created_by = @username::text
+   OR e.paid_by_user = @username::text
+   OR EXISTS (
+     SELECT 1 FROM shared_expense_shares s
+     WHERE s.expense_id = e.id AND s.username = @username::text
+   )
+ORDER BY e.created_at DESC;
+
+-- name: ListSharedExpenseShares :many
+SELECT * FROM shared_expense_shares
+WHERE expense_id = ANY(@expense_ids::bigint[])
+ORDER BY id;
+
+-- name: CreateSettlement :one
+INSERT INTO settlements
+    (created_by, from_user, from_contact, to_user, to_contact, amount, from_financial_id, to_financial_id, status)
+VALUES
+    ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: GetSettlementForUpdate :one
+SELECT * FROM settlements
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ReassignSharedExpenses :exec
+-- ReassignSharedExpenses hands the shared expenses a user created to another registered user on them, the payer first,
+-- so purging the user doesn't delete them from everyone's balances. Expenses nobody else is registered on go with the user.
+UPDATE shared_expenses e
+SET created_by = COALESCE(
+    NULLIF(e.paid_by_user, @username::text),
+    (
+        SELECT s.username FROM shared_expense_shares s
+        WHERE s.expense_id = e.id AND s.username <> @username::text
+        ORDER BY s.id
+        LIMIT 1
+    )
+)
+WHERE e.created_by = @username::text
+  AND (
+    e.paid_by_user <> @username::text
+    OR EXISTS (
+        SELECT 1 FROM shared_expense_shares s
+        WHERE s.expense_id = e.id AND s.username <> @username::text
+    )
+  );
+
+-- name: RespondToSettlement :one
+UPDATE settlements
+SET status = @status::text,
+    responded_at = NOW(),
+    from_financial_id = COALESCE(sqlc.narg(from_financial_id), from_financial_id),
+    to_financial_id = COALESCE(sqlc.narg(to_financial_id), to_financial_id)
+WHERE id = @id
+RETURNING *;
+
+-- name: ListSettlementsForUser :many
+SELECT * FROM settlements
+WHERE created_by = @username::text
+   OR from_user = @username::text
+   OR to_user = @username::text
+ORDER BY created_at DESC;
+
+-- name: UsersAreConnected :one
+-- Two users are connected when they share a household, or when other keeps the verified email of username as a contact.
+-- Only connected users can name each other in a settlement or a shared expense.
+SELECT (
+  EXISTS (
+    SELECT 1 FROM household_members a
+    JOIN household_members b ON b.household_id = a.household_id
+    WHERE a.username = @username::text AND b.username = @other::text
+  )
+  OR EXISTS (
+    SELECT 1 FROM contacts c
+    JOIN users u ON u.username = @username::text
+    WHERE c.owner = @other::text AND lower(c.email) = lower(u.email) AND u.email_verified_at IS NOT NULL
+  )
+)::boolean AS connected;
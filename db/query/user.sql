@@ -16,4 +16,96 @@ FROM users where username = $1;
 -- name: UpdatePassword :exec
 UPDATE users
 SET password = $1
-WHERE username = $2;
\ No newline at end of file
+WHERE username = $2;
+
+-- name: GetUserByEmail :one
+SELECT *
+FROM users where email = $1;
+
+-- name: UpdateUserName :one
+UPDATE users
+SET name = $1, updated_at = NOW()
+WHERE username = $2
+RETURNING *;
+
+-- name: UpdateUserEmail :one
+-- Only called once the address is verified.
+UPDATE users
+SET email = $1, email_verified_at = NOW(), updated_at = NOW()
+WHERE username = $2
+RETURNING *;
+
+-- name: UpdateUserPhone :one
+UPDATE users
+SET phone = $1, updated_at = NOW()
+WHERE username = $2
+RETURNING *;
+
+-- name: ScheduleUserDeletion :one
+UPDATE users
+SET deleted_at = NOW()
+WHERE username = $1
+RETURNING *;
+
+-- name: CancelUserDeletion :exec
+UPDATE users
+SET deleted_at = NULL
+WHERE username = $1;
+
+-- name: ListUsersToPurge :many
+SELECT username FROM users
+WHERE deleted_at IS NOT NULL AND deleted_at < $1;
+
+-- name: DeleteUser :exec
+DELETE FROM users
+WHERE username = $1;
+
+-- name: CreateContactVerification :one
+-- the new code replaces the pending codes of the field, they expire now.
+WITH replaced AS (
+    UPDATE contact_verifications
+    SET expired_at = NOW()
+    WHERE username = $1 AND field = $2 AND verified_at IS NULL AND expired_at > NOW()
+)
+INSERT INTO contact_verifications
+    (username, field, new_value, code, expired_at)
+VALUES
+    ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: CountContactVerificationsSince :one
+SELECT COUNT(*) FROM contact_verifications
+WHERE username = $1 AND created_at > $2;
+
+-- name: GetContactVerification :one
+SELECT * FROM contact_verifications
+WHERE username = $1 AND code = $2 AND failed_attempts < @max_attempts::int
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: RecordContactVerificationFailure :exec
+UPDATE contact_verifications
+SET failed_attempts = failed_attempts + 1
+WHERE username = $1 AND verified_at IS NULL AND expired_at > NOW();
+
+-- name: MarkContactVerified :exec
+UPDATE contact_verifications
+SET verified_at = NOW()
+WHERE id = $1;
+
+-- name: IsTimeZone :one
+-- IsTimeZone reports whether Postgres knows the time zone, Go and Postgres ship their own zone databases.
+SELECT EXISTS (
+    SELECT 1 FROM pg_timezone_names
+    WHERE name = $1
+) AS known;
+
+-- name: UpdateUserPreferences :one
+UPDATE users
+SET time_zone = $1, locale = $2, period_start_day = $3, fiscal_year_start_month = $4, updated_at = NOW()
+WHERE username = $5
+RETURNING *;
+
+-- name: ListUsernames :many
+SELECT username FROM users
+ORDER BY username;
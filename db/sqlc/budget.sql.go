@@ -16,7 +16,7 @@ INSERT INTO budgets
     (user_id, month, year, amount)
 VALUES
     ($1, $2, $3, $4)
-RETURNING id, user_id, month, year, amount, created_at, updated_at
+RETURNING id, user_id, month, year, amount, created_at, updated_at, household_id
 `
 
 type AddNewBudgetParams struct {
@@ -42,14 +42,89 @@ func (q *Queries) AddNewBudget(ctx context.Context, arg AddNewBudgetParams) (Bud
 		&i.Amount,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.HouseholdID,
 	)
 	return i, err
 }
 
+const addNewHouseholdBudget = `-- name: AddNewHouseholdBudget :one
+INSERT INTO budgets
+    (user_id, household_id, month, year, amount)
+VALUES
+    ($1, $2, $3, $4, $5)
+RETURNING id, user_id, month, year, amount, created_at, updated_at, household_id
+`
+
+type AddNewHouseholdBudgetParams struct {
+	UserID      string         `json:"user_id"`
+	HouseholdID pgtype.Int8    `json:"household_id"`
+	Month       int32          `json:"month"`
+	Year        int32          `json:"year"`
+	Amount      pgtype.Numeric `json:"amount"`
+}
+
+func (q *Queries) AddNewHouseholdBudget(ctx context.Context, arg AddNewHouseholdBudgetParams) (Budget, error) {
+	row := q.db.QueryRow(ctx, addNewHouseholdBudget,
+		arg.UserID,
+		arg.HouseholdID,
+		arg.Month,
+		arg.Year,
+		arg.Amount,
+	)
+	var i Budget
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Month,
+		&i.Year,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.HouseholdID,
+	)
+	return i, err
+}
+
+const deleteUserBudgets = `-- name: DeleteUserBudgets :many
+DELETE FROM budgets
+WHERE user_id = $1 AND household_id IS NULL
+RETURNING id, user_id, month, year, amount, created_at, updated_at, household_id
+`
+
+// only personal budgets, household budgets belong to the household and stay.
+func (q *Queries) DeleteUserBudgets(ctx context.Context, userID string) ([]Budget, error) {
+	rows, err := q.db.Query(ctx, deleteUserBudgets, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Budget{}
+	for rows.Next() {
+		var i Budget
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Month,
+			&i.Year,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.HouseholdID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getBudget = `-- name: GetBudget :one
-SELECT id, user_id, month, year, amount, created_at, updated_at FROM budgets
+SELECT id, user_id, month, year, amount, created_at, updated_at, household_id FROM budgets
 WHERE month = $1 AND year = $2 
-AND user_id = $3
+AND user_id = $3 AND household_id IS NULL
 `
 
 type GetBudgetParams struct {
@@ -69,13 +144,36 @@ func (q *Queries) GetBudget(ctx context.Context, arg GetBudgetParams) (Budget, e
 		&i.Amount,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.HouseholdID,
+	)
+	return i, err
+}
+
+const getBudgetForUpdate = `-- name: GetBudgetForUpdate :one
+SELECT id, user_id, month, year, amount, created_at, updated_at, household_id FROM budgets
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetBudgetForUpdate(ctx context.Context, id int32) (Budget, error) {
+	row := q.db.QueryRow(ctx, getBudgetForUpdate, id)
+	var i Budget
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Month,
+		&i.Year,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.HouseholdID,
 	)
 	return i, err
 }
 
 const getBudgetHistory = `-- name: GetBudgetHistory :many
-SELECT id, user_id, month, year, amount, created_at, updated_at FROM budgets
-WHERE user_id = $1
+SELECT id, user_id, month, year, amount, created_at, updated_at, household_id FROM budgets
+WHERE user_id = $1 AND household_id IS NULL
 LIMIT 12
 `
 
@@ -96,6 +194,7 @@ func (q *Queries) GetBudgetHistory(ctx context.Context, userID string) ([]Budget
 			&i.Amount,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.HouseholdID,
 		); err != nil {
 			return nil, err
 		}
@@ -108,8 +207,8 @@ func (q *Queries) GetBudgetHistory(ctx context.Context, userID string) ([]Budget
 }
 
 const getBudgetHistoryByYear = `-- name: GetBudgetHistoryByYear :one
-SELECT id, user_id, month, year, amount, created_at, updated_at FROM budgets
-WHERE user_id = $1 AND year = $2
+SELECT id, user_id, month, year, amount, created_at, updated_at, household_id FROM budgets
+WHERE user_id = $1 AND year = $2 AND household_id IS NULL
 LIMIT 12
 `
 
@@ -129,15 +228,81 @@ func (q *Queries) GetBudgetHistoryByYear(ctx context.Context, arg GetBudgetHisto
 		&i.Amount,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.HouseholdID,
+	)
+	return i, err
+}
+
+const getHouseholdBudget = `-- name: GetHouseholdBudget :one
+SELECT id, user_id, month, year, amount, created_at, updated_at, household_id FROM budgets
+WHERE household_id = $1 AND month = $2 AND year = $3
+`
+
+type GetHouseholdBudgetParams struct {
+	HouseholdID pgtype.Int8 `json:"household_id"`
+	Month       int32       `json:"month"`
+	Year        int32       `json:"year"`
+}
+
+func (q *Queries) GetHouseholdBudget(ctx context.Context, arg GetHouseholdBudgetParams) (Budget, error) {
+	row := q.db.QueryRow(ctx, getHouseholdBudget, arg.HouseholdID, arg.Month, arg.Year)
+	var i Budget
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Month,
+		&i.Year,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.HouseholdID,
 	)
 	return i, err
 }
 
+const reassignHouseholdBudgets = `-- name: ReassignHouseholdBudgets :many
+UPDATE budgets b
+SET user_id = h.owner, updated_at = NOW()
+FROM households h
+WHERE b.household_id = h.id AND b.user_id = $1::text AND h.owner <> $1::text
+RETURNING b.id, b.user_id, b.month, b.year, b.amount, b.created_at, b.updated_at, b.household_id
+`
+
+// the household budgets a member set go to the household owner before the member is purged.
+func (q *Queries) ReassignHouseholdBudgets(ctx context.Context, username string) ([]Budget, error) {
+	rows, err := q.db.Query(ctx, reassignHouseholdBudgets, username)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Budget{}
+	for rows.Next() {
+		var i Budget
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Month,
+			&i.Year,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.HouseholdID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateBudget = `-- name: UpdateBudget :one
 UPDATE budgets
 SET amount = $1
 WHERE id = $2
-RETURNING id, user_id, month, year, amount, created_at, updated_at
+RETURNING id, user_id, month, year, amount, created_at, updated_at, household_id
 `
 
 type UpdateBudgetParams struct {
@@ -156,6 +321,7 @@ func (q *Queries) UpdateBudget(ctx context.Context, arg UpdateBudgetParams) (Bud
 		&i.Amount,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.HouseholdID,
 	)
 	return i, err
 }
@@ -13,9 +13,12 @@ import (
 )
 
 const deleteFinancial = `-- name: DeleteFinancial :one
-DELETE FROM financials 
-WHERE id = $1
-RETURNING id, user_id, amount, direction, type_id, created_at
+UPDATE financials
+SET deleted_at = NOW(),
+    reconcile_status = 'uncleared',
+    reconciliation_id = NULL
+WHERE id = $1 AND deleted_at IS NULL AND reconcile_status <> 'reconciled'
+RETURNING id, user_id, amount, direction, type_id, created_at, household_id, deleted_at, reconcile_status, reconciliation_id
 `
 
 func (q *Queries) DeleteFinancial(ctx context.Context, id int64) (Financial, error) {
@@ -28,15 +31,73 @@ func (q *Queries) DeleteFinancial(ctx context.Context, id int64) (Financial, err
 		&i.Direction,
 		&i.TypeID,
 		&i.CreatedAt,
+		&i.HouseholdID,
+		&i.DeletedAt,
+		&i.ReconcileStatus,
+		&i.ReconciliationID,
 	)
 	return i, err
 }
 
+const deleteUserFinancials = `-- name: DeleteUserFinancials :many
+DELETE FROM financials
+WHERE user_id = $1
+RETURNING id, user_id, amount, direction, type_id, created_at, household_id, deleted_at, reconcile_status, reconciliation_id
+`
+
+func (q *Queries) DeleteUserFinancials(ctx context.Context, userID string) ([]Financial, error) {
+	rows, err := q.db.Query(ctx, deleteUserFinancials, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Financial{}
+	for rows.Next() {
+		var i Financial
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Amount,
+			&i.Direction,
+			&i.TypeID,
+			&i.CreatedAt,
+			&i.HouseholdID,
+			&i.DeletedAt,
+			&i.ReconcileStatus,
+			&i.ReconciliationID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFinancialAccess = `-- name: GetFinancialAccess :one
+SELECT user_id, household_id FROM financials
+WHERE id = $1
+`
+
+type GetFinancialAccessRow struct {
+	UserID      string      `json:"user_id"`
+	HouseholdID pgtype.Int8 `json:"household_id"`
+}
+
+func (q *Queries) GetFinancialAccess(ctx context.Context, id int64) (GetFinancialAccessRow, error) {
+	row := q.db.QueryRow(ctx, getFinancialAccess, id)
+	var i GetFinancialAccessRow
+	err := row.Scan(&i.UserID, &i.HouseholdID)
+	return i, err
+}
+
 const getFinancialById = `-- name: GetFinancialById :one
 SELECT f.id, f.amount, f.direction, ft.type, f.created_at
 FROM financials f
 LEFT JOIN financial_types ft ON (f.type_id = ft.id) 
-WHERE f.id = $1
+WHERE f.id = $1 AND f.deleted_at IS NULL
 `
 
 type GetFinancialByIdRow struct {
@@ -60,6 +121,30 @@ func (q *Queries) GetFinancialById(ctx context.Context, id int64) (GetFinancialB
 	return i, err
 }
 
+const getFinancialForUpdate = `-- name: GetFinancialForUpdate :one
+SELECT id, user_id, amount, direction, type_id, created_at, household_id, deleted_at, reconcile_status, reconciliation_id FROM financials
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetFinancialForUpdate(ctx context.Context, id int64) (Financial, error) {
+	row := q.db.QueryRow(ctx, getFinancialForUpdate, id)
+	var i Financial
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Amount,
+		&i.Direction,
+		&i.TypeID,
+		&i.CreatedAt,
+		&i.HouseholdID,
+		&i.DeletedAt,
+		&i.ReconcileStatus,
+		&i.ReconciliationID,
+	)
+	return i, err
+}
+
 const getFinancialOwner = `-- name: GetFinancialOwner :one
 SELECT user_id FROM financials
 WHERE id = $1
@@ -74,17 +159,18 @@ func (q *Queries) GetFinancialOwner(ctx context.Context, id int64) (string, erro
 
 const insertNewFinancial = `-- name: InsertNewFinancial :one
 INSERT INTO financials
-    (user_id, amount, direction, type_id)
+    (user_id, amount, direction, type_id, household_id)
 VALUES 
-    ($1, $2, $3, $4)
-RETURNING id, user_id, amount, direction, type_id, created_at
+    ($1, $2, $3, $4, $5)
+RETURNING id, user_id, amount, direction, type_id, created_at, household_id, deleted_at, reconcile_status, reconciliation_id
 `
 
 type InsertNewFinancialParams struct {
-	UserID    string `json:"user_id"`
-	Amount    int64  `json:"amount"`
-	Direction string `json:"direction"`
-	TypeID    int64  `json:"type_id"`
+	UserID      string      `json:"user_id"`
+	Amount      int64       `json:"amount"`
+	Direction   string      `json:"direction"`
+	TypeID      int64       `json:"type_id"`
+	HouseholdID pgtype.Int8 `json:"household_id"`
 }
 
 func (q *Queries) InsertNewFinancial(ctx context.Context, arg InsertNewFinancialParams) (Financial, error) {
@@ -93,6 +179,7 @@ func (q *Queries) InsertNewFinancial(ctx context.Context, arg InsertNewFinancial
 		arg.Amount,
 		arg.Direction,
 		arg.TypeID,
+		arg.HouseholdID,
 	)
 	var i Financial
 	err := row.Scan(
@@ -102,15 +189,63 @@ func (q *Queries) InsertNewFinancial(ctx context.Context, arg InsertNewFinancial
 		&i.Direction,
 		&i.TypeID,
 		&i.CreatedAt,
+		&i.HouseholdID,
+		&i.DeletedAt,
+		&i.ReconcileStatus,
+		&i.ReconciliationID,
 	)
 	return i, err
 }
 
+const listDeletedFinancials = `-- name: ListDeletedFinancials :many
+SELECT f.id, f.amount, f.direction, ft.type, f.created_at, f.deleted_at
+FROM financials f
+LEFT JOIN financial_types ft ON (f.type_id = ft.id)
+WHERE f.user_id = $1 AND f.deleted_at IS NOT NULL
+ORDER BY f.deleted_at DESC
+`
+
+type ListDeletedFinancialsRow struct {
+	ID        int64              `json:"id"`
+	Amount    int64              `json:"amount"`
+	Direction string             `json:"direction"`
+	Type      pgtype.Text        `json:"type"`
+	CreatedAt time.Time          `json:"created_at"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+}
+
+func (q *Queries) ListDeletedFinancials(ctx context.Context, userID string) ([]ListDeletedFinancialsRow, error) {
+	rows, err := q.db.Query(ctx, listDeletedFinancials, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListDeletedFinancialsRow{}
+	for rows.Next() {
+		var i ListDeletedFinancialsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Amount,
+			&i.Direction,
+			&i.Type,
+			&i.CreatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const myFinancial = `-- name: MyFinancial :many
 SELECT f.id, f.amount, f.direction, ft.type, f.created_at
 FROM financials f
 LEFT JOIN financial_types ft ON (f.type_id = ft.id) 
-WHERE f.user_id = $1
+WHERE f.user_id = $1 AND f.deleted_at IS NULL
 `
 
 type MyFinancialRow struct {
@@ -147,21 +282,84 @@ func (q *Queries) MyFinancial(ctx context.Context, userID string) ([]MyFinancial
 	return items, nil
 }
 
+const purgeDeletedFinancials = `-- name: PurgeDeletedFinancials :many
+DELETE FROM financials
+WHERE deleted_at < $1 AND reconcile_status <> 'reconciled'
+RETURNING id, user_id, amount, direction, type_id, created_at, household_id, deleted_at, reconcile_status, reconciliation_id
+`
+
+// reconciled financials are kept, they are part of a finished statement.
+func (q *Queries) PurgeDeletedFinancials(ctx context.Context, deletedAt pgtype.Timestamptz) ([]Financial, error) {
+	rows, err := q.db.Query(ctx, purgeDeletedFinancials, deletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Financial{}
+	for rows.Next() {
+		var i Financial
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Amount,
+			&i.Direction,
+			&i.TypeID,
+			&i.CreatedAt,
+			&i.HouseholdID,
+			&i.DeletedAt,
+			&i.ReconcileStatus,
+			&i.ReconciliationID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreFinancial = `-- name: RestoreFinancial :one
+UPDATE financials
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING id, user_id, amount, direction, type_id, created_at, household_id, deleted_at, reconcile_status, reconciliation_id
+`
+
+func (q *Queries) RestoreFinancial(ctx context.Context, id int64) (Financial, error) {
+	row := q.db.QueryRow(ctx, restoreFinancial, id)
+	var i Financial
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Amount,
+		&i.Direction,
+		&i.TypeID,
+		&i.CreatedAt,
+		&i.HouseholdID,
+		&i.DeletedAt,
+		&i.ReconcileStatus,
+		&i.ReconciliationID,
+	)
+	return i, err
+}
+
 const summaryByTypeMonth = `-- name: SummaryByTypeMonth :many
 SELECT 
   ft.type,
-  SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END) AS total_income,
-  SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END) AS total_expense,
+  SUM(a.total_income) AS total_income,
+  SUM(a.total_expense) AS total_expense,
   CASE
-    WHEN SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END) > SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END) THEN 'in'
-    WHEN SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END) < SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END) THEN 'out'
+    WHEN SUM(a.total_income) > SUM(a.total_expense) THEN 'in'
+    WHEN SUM(a.total_income) < SUM(a.total_expense) THEN 'out'
     ELSE 'equal'
   END AS status
-FROM financials f
-JOIN financial_types ft ON ft.id = f.type_id
-WHERE f.user_id = $1::text
-  AND EXTRACT(MONTH FROM f.created_at) = $2::int
-  AND EXTRACT(YEAR FROM f.created_at) = $3::int
+FROM monthly_aggregates a
+JOIN financial_types ft ON ft.id = a.type_id
+WHERE a.user_id = $1::text
+  AND a.month = $2::int
+  AND a.year = $3::int
 GROUP BY ft.type
 ORDER BY ft.type
 `
@@ -207,17 +405,18 @@ func (q *Queries) SummaryByTypeMonth(ctx context.Context, arg SummaryByTypeMonth
 const summaryByTypeYear = `-- name: SummaryByTypeYear :many
 SELECT 
   ft.type,
-  SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END) AS total_income,
-  SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END) AS total_expense,
+  SUM(a.total_income) AS total_income,
+  SUM(a.total_expense) AS total_expense,
   CASE
-    WHEN SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END) > SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END) THEN 'in'
-    WHEN SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END) < SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END) THEN 'out'
+    WHEN SUM(a.total_income) > SUM(a.total_expense) THEN 'in'
+    WHEN SUM(a.total_income) < SUM(a.total_expense) THEN 'out'
     ELSE 'equal'
   END AS status
-FROM financials f
-JOIN financial_types ft ON ft.id = f.type_id
-WHERE f.user_id = $1::text
-  AND EXTRACT(YEAR FROM f.created_at) = $2::int
+FROM monthly_aggregates a
+JOIN financial_types ft ON ft.id = a.type_id
+JOIN users u ON u.username = a.user_id
+WHERE a.user_id = $1::text
+  AND (a.year - CASE WHEN a.month < u.fiscal_year_start_month THEN 1 ELSE 0 END) = $2::int
 GROUP BY ft.type
 ORDER BY ft.type
 `
@@ -261,17 +460,17 @@ func (q *Queries) SummaryByTypeYear(ctx context.Context, arg SummaryByTypeYearPa
 
 const summaryFinancialByMonth = `-- name: SummaryFinancialByMonth :one
 SELECT 
-  SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END) AS total_income,
-  SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END) AS total_expense,
+  COALESCE(SUM(a.total_income), 0)::bigint AS total_income,
+  COALESCE(SUM(a.total_expense), 0)::bigint AS total_expense,
   CASE
-    WHEN SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END) > SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END) THEN 'in'
-    WHEN SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END) < SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END) THEN 'out'
+    WHEN SUM(a.total_income) > SUM(a.total_expense) THEN 'in'
+    WHEN SUM(a.total_income) < SUM(a.total_expense) THEN 'out'
     ELSE 'equal'
   END AS status
-FROM financials f
-WHERE f.user_id = $1::text
-  AND EXTRACT(MONTH FROM f.created_at) = $2::int
-  AND EXTRACT(YEAR FROM f.created_at) = $3::int
+FROM monthly_aggregates a
+WHERE a.user_id = $1::text
+  AND a.month = $2::int
+  AND a.year = $3::int
 `
 
 type SummaryFinancialByMonthParams struct {
@@ -295,16 +494,17 @@ func (q *Queries) SummaryFinancialByMonth(ctx context.Context, arg SummaryFinanc
 
 const summaryFinancialByYear = `-- name: SummaryFinancialByYear :one
 SELECT 
-  SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END) AS total_income,
-  SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END) AS total_expense,
+  COALESCE(SUM(a.total_income), 0)::bigint AS total_income,
+  COALESCE(SUM(a.total_expense), 0)::bigint AS total_expense,
   CASE
-    WHEN SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END) > SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END) THEN 'in'
-    WHEN SUM(CASE WHEN f.direction = 'in' THEN f.amount ELSE 0 END) < SUM(CASE WHEN f.direction = 'out' THEN f.amount ELSE 0 END) THEN 'out'
+    WHEN SUM(a.total_income) > SUM(a.total_expense) THEN 'in'
+    WHEN SUM(a.total_income) < SUM(a.total_expense) THEN 'out'
     ELSE 'equal'
   END AS status
-FROM financials f
-WHERE f.user_id = $1::text
-  AND EXTRACT(YEAR FROM f.created_at) = $2::int
+FROM monthly_aggregates a
+JOIN users u ON u.username = a.user_id
+WHERE a.user_id = $1::text
+  AND (a.year - CASE WHEN a.month < u.fiscal_year_start_month THEN 1 ELSE 0 END) = $2::int
 `
 
 type SummaryFinancialByYearParams struct {
@@ -327,18 +527,19 @@ func (q *Queries) SummaryFinancialByYear(ctx context.Context, arg SummaryFinanci
 
 const summaryFinancialEachYear = `-- name: SummaryFinancialEachYear :many
 SELECT 
-    EXTRACT(YEAR FROM f.created_at)::INT AS year,
-    COALESCE(SUM(CASE WHEN f.direction = 'in' THEN f.amount END), 0) AS in_amount,
-    COALESCE(SUM(CASE WHEN f.direction = 'out' THEN f.amount END), 0) AS out_amount,
+    (a.year - CASE WHEN a.month < u.fiscal_year_start_month THEN 1 ELSE 0 END)::INT AS year,
+    COALESCE(SUM(a.total_income), 0) AS in_amount,
+    COALESCE(SUM(a.total_expense), 0) AS out_amount,
     CASE
-        WHEN COALESCE(SUM(CASE WHEN f.direction = 'in' THEN f.amount END), 0) > COALESCE(SUM(CASE WHEN f.direction = 'out' THEN f.amount END), 0) THEN 'in'
-        WHEN COALESCE(SUM(CASE WHEN f.direction = 'in' THEN f.amount END), 0) < COALESCE(SUM(CASE WHEN f.direction = 'out' THEN f.amount END), 0) THEN 'out'
+        WHEN COALESCE(SUM(a.total_income), 0) > COALESCE(SUM(a.total_expense), 0) THEN 'in'
+        WHEN COALESCE(SUM(a.total_income), 0) < COALESCE(SUM(a.total_expense), 0) THEN 'out'
         ELSE 'equal'
     END AS status
-FROM financials f
-WHERE f.user_id = $1::text
-GROUP BY year
-ORDER BY year
+FROM monthly_aggregates a
+JOIN users u ON u.username = a.user_id
+WHERE a.user_id = $1::text
+GROUP BY 1
+ORDER BY 1
 `
 
 type SummaryFinancialEachYearRow struct {
@@ -376,8 +577,8 @@ func (q *Queries) SummaryFinancialEachYear(ctx context.Context, userID string) (
 const updateFinancial = `-- name: UpdateFinancial :one
 UPDATE financials
 SET amount = $1, direction = $2, type_id = $3
-WHERE id = $4
-RETURNING id, user_id, amount, direction, type_id, created_at
+WHERE id = $4 AND deleted_at IS NULL
+RETURNING id, user_id, amount, direction, type_id, created_at, household_id, deleted_at, reconcile_status, reconciliation_id
 `
 
 type UpdateFinancialParams struct {
@@ -402,6 +603,10 @@ func (q *Queries) UpdateFinancial(ctx context.Context, arg UpdateFinancialParams
 		&i.Direction,
 		&i.TypeID,
 		&i.CreatedAt,
+		&i.HouseholdID,
+		&i.DeletedAt,
+		&i.ReconcileStatus,
+		&i.ReconciliationID,
 	)
 	return i, err
 }
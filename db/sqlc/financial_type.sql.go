@@ -9,6 +9,32 @@ import (
 	"context"
 )
 
+const createFinancialType = `-- name: CreateFinancialType :one
+INSERT INTO financial_types (type)
+VALUES ($1)
+RETURNING id, type
+`
+
+func (q *Queries) CreateFinancialType(ctx context.Context, type_ string) (FinancialType, error) {
+	row := q.db.QueryRow(ctx, createFinancialType, type_)
+	var i FinancialType
+	err := row.Scan(&i.ID, &i.Type)
+	return i, err
+}
+
+const deleteFinancialType = `-- name: DeleteFinancialType :one
+DELETE FROM financial_types
+WHERE id = $1
+RETURNING id, type
+`
+
+func (q *Queries) DeleteFinancialType(ctx context.Context, id int64) (FinancialType, error) {
+	row := q.db.QueryRow(ctx, deleteFinancialType, id)
+	var i FinancialType
+	err := row.Scan(&i.ID, &i.Type)
+	return i, err
+}
+
 const getFinancialByName = `-- name: GetFinancialByName :one
 SELECT id, type FROM financial_types
 WHERE type = $1
@@ -20,3 +46,63 @@ func (q *Queries) GetFinancialByName(ctx context.Context, type_ string) (Financi
 	err := row.Scan(&i.ID, &i.Type)
 	return i, err
 }
+
+const getFinancialTypeForUpdate = `-- name: GetFinancialTypeForUpdate :one
+SELECT id, type FROM financial_types
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetFinancialTypeForUpdate(ctx context.Context, id int64) (FinancialType, error) {
+	row := q.db.QueryRow(ctx, getFinancialTypeForUpdate, id)
+	var i FinancialType
+	err := row.Scan(&i.ID, &i.Type)
+	return i, err
+}
+
+const listFinancialTypes = `-- name: ListFinancialTypes :many
+SELECT id, type FROM financial_types
+ORDER BY id
+`
+
+func (q *Queries) ListFinancialTypes(ctx context.Context) ([]FinancialType, error) {
+	rows, err := q.db.Query(ctx, listFinancialTypes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FinancialType{}
+	for rows.Next() {
+		var i FinancialType
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateFinancialType = `-- name: UpdateFinancialType :one
+UPDATE financial_types
+SET type = $1
+WHERE id = $2
+RETURNING id, type
+`
+
+type UpdateFinancialTypeParams struct {
+	Type string `json:"type"`
+	ID   int64  `json:"id"`
+}
+
+func (q *Queries) UpdateFinancialType(ctx context.Context, arg UpdateFinancialTypeParams) (FinancialType, error) {
+	row := q.db.QueryRow(ctx, updateFinancialType, arg.Type, arg.ID)
+	var i FinancialType
+	err := row.Scan(&i.ID, &i.Type)
+	return i, err
+}
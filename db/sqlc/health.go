@@ -0,0 +1,27 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SchemaVersion is the latest migration in db/migration, bump it with every new migration.
+// The server is not ready while the database is behind it.
+const SchemaVersion int64 = 20261019000020
+
+// Ping checks that a connection to the database can be made and used.
+func (store *SQLStore) Ping(ctx context.Context) error {
+	return store.db.Ping(ctx)
+}
+
+// MigrationVersion reads the version golang-migrate recorded, dirty means a migration failed halfway.
+func (store *SQLStore) MigrationVersion(ctx context.Context) (version int64, dirty bool, err error) {
+	err = store.db.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	return
+}
+
+// PoolStat is a snapshot of the connection pool.
+func (store *SQLStore) PoolStat() *pgxpool.Stat {
+	return store.db.Stat()
+}
@@ -10,23 +10,82 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AdminAudit struct {
+	ID        int64     `json:"id"`
+	Admin     string    `json:"admin"`
+	Action    string    `json:"action"`
+	Target    string    `json:"target"`
+	Details   []byte    `json:"details"`
+	ClientIp  string    `json:"client_ip"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Anomaly struct {
+	ID          int64       `json:"id"`
+	UserID      string      `json:"user_id"`
+	FinancialID pgtype.Int8 `json:"financial_id"`
+	TypeID      pgtype.Int8 `json:"type_id"`
+	Kind        string      `json:"kind"`
+	Reason      string      `json:"reason"`
+	DedupeKey   string      `json:"dedupe_key"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+type AuditEvent struct {
+	ID         int64     `json:"id"`
+	Actor      string    `json:"actor"`
+	Action     string    `json:"action"`
+	EntityType string    `json:"entity_type"`
+	EntityID   int64     `json:"entity_id"`
+	Before     []byte    `json:"before"`
+	After      []byte    `json:"after"`
+	Ip         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
 type Budget struct {
-	ID        int32          `json:"id"`
-	UserID    string         `json:"user_id"`
-	Month     int32          `json:"month"`
-	Year      int32          `json:"year"`
-	Amount    pgtype.Numeric `json:"amount"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
+	ID          int32          `json:"id"`
+	UserID      string         `json:"user_id"`
+	Month       int32          `json:"month"`
+	Year        int32          `json:"year"`
+	Amount      pgtype.Numeric `json:"amount"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	HouseholdID pgtype.Int8    `json:"household_id"`
+}
+
+type Contact struct {
+	ID        int64       `json:"id"`
+	Owner     string      `json:"owner"`
+	Name      string      `json:"name"`
+	Email     pgtype.Text `json:"email"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+type ContactVerification struct {
+	ID             int64              `json:"id"`
+	Username       string             `json:"username"`
+	Field          string             `json:"field"`
+	NewValue       string             `json:"new_value"`
+	Code           string             `json:"code"`
+	ExpiredAt      time.Time          `json:"expired_at"`
+	VerifiedAt     pgtype.Timestamptz `json:"verified_at"`
+	CreatedAt      time.Time          `json:"created_at"`
+	FailedAttempts int32              `json:"failed_attempts"`
 }
 
 type Financial struct {
-	ID        int64     `json:"id"`
-	UserID    string    `json:"user_id"`
-	Amount    int64     `json:"amount"`
-	Direction string    `json:"direction"`
-	TypeID    int64     `json:"type_id"`
-	CreatedAt time.Time `json:"created_at"`
+	ID               int64              `json:"id"`
+	UserID           string             `json:"user_id"`
+	Amount           int64              `json:"amount"`
+	Direction        string             `json:"direction"`
+	TypeID           int64              `json:"type_id"`
+	CreatedAt        time.Time          `json:"created_at"`
+	HouseholdID      pgtype.Int8        `json:"household_id"`
+	DeletedAt        pgtype.Timestamptz `json:"deleted_at"`
+	ReconcileStatus  string             `json:"reconcile_status"`
+	ReconciliationID pgtype.Int8        `json:"reconciliation_id"`
 }
 
 type FinancialType struct {
@@ -34,12 +93,174 @@ type FinancialType struct {
 	Type string `json:"type"`
 }
 
+type Household struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Owner     string    `json:"owner"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type HouseholdInvitation struct {
+	ID          int64              `json:"id"`
+	HouseholdID int64              `json:"household_id"`
+	Email       string             `json:"email"`
+	Role        string             `json:"role"`
+	TokenHash   string             `json:"token_hash"`
+	InvitedBy   string             `json:"invited_by"`
+	ExpiredAt   time.Time          `json:"expired_at"`
+	AcceptedAt  pgtype.Timestamptz `json:"accepted_at"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+type HouseholdMember struct {
+	HouseholdID int64     `json:"household_id"`
+	Username    string    `json:"username"`
+	Role        string    `json:"role"`
+	JoinedAt    time.Time `json:"joined_at"`
+}
+
+type LockoutAudit struct {
+	ID          int64     `json:"id"`
+	Scope       string    `json:"scope"`
+	Identifier  string    `json:"identifier"`
+	FailedCount int32     `json:"failed_count"`
+	ClientIp    string    `json:"client_ip"`
+	LockedUntil time.Time `json:"locked_until"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type LoginAttempt struct {
+	Scope        string    `json:"scope"`
+	Identifier   string    `json:"identifier"`
+	FailedCount  int32     `json:"failed_count"`
+	LastFailedAt time.Time `json:"last_failed_at"`
+	LockedUntil  time.Time `json:"locked_until"`
+}
+
+type LoginFailure struct {
+	ID         int64     `json:"id"`
+	Scope      string    `json:"scope"`
+	Identifier string    `json:"identifier"`
+	FailedAt   time.Time `json:"failed_at"`
+}
+
+type MonthlyAggregate struct {
+	UserID       string `json:"user_id"`
+	Year         int32  `json:"year"`
+	Month        int32  `json:"month"`
+	TypeID       int64  `json:"type_id"`
+	TotalIncome  int64  `json:"total_income"`
+	TotalExpense int64  `json:"total_expense"`
+	RecordCount  int64  `json:"record_count"`
+}
+
+type OauthState struct {
+	State        string    `json:"state"`
+	Nonce        string    `json:"nonce"`
+	CodeVerifier string    `json:"code_verifier"`
+	ExpiredAt    time.Time `json:"expired_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type PeriodClosing struct {
+	ID           int64              `json:"id"`
+	UserID       string             `json:"user_id"`
+	Month        int32              `json:"month"`
+	Year         int32              `json:"year"`
+	TotalIncome  int64              `json:"total_income"`
+	TotalExpense int64              `json:"total_expense"`
+	Status       string             `json:"status"`
+	ClosedAt     time.Time          `json:"closed_at"`
+	ReopenedAt   pgtype.Timestamptz `json:"reopened_at"`
+}
+
+type PersonalAccessToken struct {
+	ID         int64              `json:"id"`
+	Username   string             `json:"username"`
+	Name       string             `json:"name"`
+	TokenHash  string             `json:"token_hash"`
+	Prefix     string             `json:"prefix"`
+	Scopes     []string           `json:"scopes"`
+	ExpiredAt  pgtype.Timestamptz `json:"expired_at"`
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
+	RevokedAt  pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+type Reconciliation struct {
+	ID               int64              `json:"id"`
+	UserID           string             `json:"user_id"`
+	StatementDate    pgtype.Date        `json:"statement_date"`
+	StatementBalance int64              `json:"statement_balance"`
+	LockedAt         pgtype.Timestamptz `json:"locked_at"`
+	CreatedAt        time.Time          `json:"created_at"`
+}
+
+type Settlement struct {
+	ID              int64              `json:"id"`
+	CreatedBy       string             `json:"created_by"`
+	FromUser        pgtype.Text        `json:"from_user"`
+	FromContact     pgtype.Int8        `json:"from_contact"`
+	ToUser          pgtype.Text        `json:"to_user"`
+	ToContact       pgtype.Int8        `json:"to_contact"`
+	Amount          int64              `json:"amount"`
+	FromFinancialID pgtype.Int8        `json:"from_financial_id"`
+	ToFinancialID   pgtype.Int8        `json:"to_financial_id"`
+	CreatedAt       time.Time          `json:"created_at"`
+	Status          string             `json:"status"`
+	RespondedAt     pgtype.Timestamptz `json:"responded_at"`
+}
+
+type SharedExpense struct {
+	ID            int64       `json:"id"`
+	CreatedBy     string      `json:"created_by"`
+	Description   string      `json:"description"`
+	Amount        int64       `json:"amount"`
+	PaidByUser    pgtype.Text `json:"paid_by_user"`
+	PaidByContact pgtype.Int8 `json:"paid_by_contact"`
+	SplitMethod   string      `json:"split_method"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+type SharedExpenseShare struct {
+	ID        int64       `json:"id"`
+	ExpenseID int64       `json:"expense_id"`
+	Username  pgtype.Text `json:"username"`
+	ContactID pgtype.Int8 `json:"contact_id"`
+	Amount    int64       `json:"amount"`
+}
+
+type UnlockToken struct {
+	TokenHash string             `json:"token_hash"`
+	Username  string             `json:"username"`
+	ExpiredAt time.Time          `json:"expired_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
 type User struct {
+	Username             string             `json:"username"`
+	Name                 string             `json:"name"`
+	Email                string             `json:"email"`
+	Phone                string             `json:"phone"`
+	Password             string             `json:"password"`
+	CreatedAt            time.Time          `json:"created_at"`
+	UpdatedAt            time.Time          `json:"updated_at"`
+	DeletedAt            pgtype.Timestamptz `json:"deleted_at"`
+	Role                 string             `json:"role"`
+	DisabledAt           pgtype.Timestamptz `json:"disabled_at"`
+	TokensValidAfter     pgtype.Timestamptz `json:"tokens_valid_after"`
+	TimeZone             string             `json:"time_zone"`
+	Locale               string             `json:"locale"`
+	PeriodStartDay       int32              `json:"period_start_day"`
+	FiscalYearStartMonth int32              `json:"fiscal_year_start_month"`
+	EmailVerifiedAt      pgtype.Timestamptz `json:"email_verified_at"`
+}
+
+type UserIdentity struct {
+	Issuer    string    `json:"issuer"`
+	Subject   string    `json:"subject"`
 	Username  string    `json:"username"`
-	Name      string    `json:"name"`
 	Email     string    `json:"email"`
-	Phone     string    `json:"phone"`
-	Password  string    `json:"password"`
 	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -6,30 +6,168 @@ package db
 
 import (
 	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
+	AcceptHouseholdInvitation(ctx context.Context, id int64) error
+	AddHouseholdMember(ctx context.Context, arg AddHouseholdMemberParams) (HouseholdMember, error)
 	AddNewBudget(ctx context.Context, arg AddNewBudgetParams) (Budget, error)
+	AddNewHouseholdBudget(ctx context.Context, arg AddNewHouseholdBudgetParams) (Budget, error)
+	AddSharedExpenseShare(ctx context.Context, arg AddSharedExpenseShareParams) (SharedExpenseShare, error)
+	CancelUserDeletion(ctx context.Context, username string) error
+	ClearFinancials(ctx context.Context, arg ClearFinancialsParams) ([]int64, error)
+	ConsumeOAuthState(ctx context.Context, state string) (OauthState, error)
+	CountContactVerificationsSince(ctx context.Context, arg CountContactVerificationsSinceParams) (int64, error)
+	CreateAdminAudit(ctx context.Context, arg CreateAdminAuditParams) (AdminAudit, error)
+	CreateAnomaly(ctx context.Context, arg CreateAnomalyParams) (Anomaly, error)
+	CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) (AuditEvent, error)
+	CreateContact(ctx context.Context, arg CreateContactParams) (Contact, error)
+	// the new code replaces the pending codes of the field, they expire now.
+	CreateContactVerification(ctx context.Context, arg CreateContactVerificationParams) (ContactVerification, error)
+	CreateFinancialType(ctx context.Context, type_ string) (FinancialType, error)
+	CreateHousehold(ctx context.Context, arg CreateHouseholdParams) (Household, error)
+	CreateHouseholdInvitation(ctx context.Context, arg CreateHouseholdInvitationParams) (HouseholdInvitation, error)
+	CreateLockoutAudit(ctx context.Context, arg CreateLockoutAuditParams) (LockoutAudit, error)
+	CreateOAuthState(ctx context.Context, arg CreateOAuthStateParams) (OauthState, error)
+	CreatePeriodClosing(ctx context.Context, arg CreatePeriodClosingParams) (PeriodClosing, error)
+	CreatePersonalAccessToken(ctx context.Context, arg CreatePersonalAccessTokenParams) (PersonalAccessToken, error)
+	CreateReconciliation(ctx context.Context, arg CreateReconciliationParams) (Reconciliation, error)
+	CreateSettlement(ctx context.Context, arg CreateSettlementParams) (Settlement, error)
+	CreateSharedExpense(ctx context.Context, arg CreateSharedExpenseParams) (SharedExpense, error)
+	CreateUnlockToken(ctx context.Context, arg CreateUnlockTokenParams) (UnlockToken, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) (UserIdentity, error)
+	DailySummaryBetween(ctx context.Context, arg DailySummaryBetweenParams) ([]DailySummaryBetweenRow, error)
+	DeleteExpiredOAuthStates(ctx context.Context) error
 	DeleteFinancial(ctx context.Context, id int64) (Financial, error)
+	DeleteFinancialType(ctx context.Context, id int64) (FinancialType, error)
+	DeleteLoginFailuresBefore(ctx context.Context, failedAt time.Time) error
+	DeleteMonthlyAggregates(ctx context.Context, userID string) error
+	DeleteReconciliation(ctx context.Context, id int64) (Reconciliation, error)
+	DeleteUser(ctx context.Context, username string) error
+	// only personal budgets, household budgets belong to the household and stay.
+	DeleteUserBudgets(ctx context.Context, userID string) ([]Budget, error)
+	DeleteUserFinancials(ctx context.Context, userID string) ([]Financial, error)
+	FirstFinancialAt(ctx context.Context, userID string) (time.Time, error)
 	GetBudget(ctx context.Context, arg GetBudgetParams) (Budget, error)
+	GetBudgetForUpdate(ctx context.Context, id int32) (Budget, error)
 	GetBudgetHistory(ctx context.Context, userID string) ([]Budget, error)
 	GetBudgetHistoryByYear(ctx context.Context, arg GetBudgetHistoryByYearParams) (Budget, error)
+	GetContact(ctx context.Context, arg GetContactParams) (Contact, error)
+	GetContactVerification(ctx context.Context, arg GetContactVerificationParams) (ContactVerification, error)
+	GetFinancialAccess(ctx context.Context, id int64) (GetFinancialAccessRow, error)
 	GetFinancialById(ctx context.Context, id int64) (GetFinancialByIdRow, error)
 	GetFinancialByName(ctx context.Context, type_ string) (FinancialType, error)
+	GetFinancialForUpdate(ctx context.Context, id int64) (Financial, error)
+	GetFinancialTypeForUpdate(ctx context.Context, id int64) (FinancialType, error)
 	GetFinancialOwner(ctx context.Context, id int64) (string, error)
+	GetHousehold(ctx context.Context, id int64) (Household, error)
+	GetHouseholdBudget(ctx context.Context, arg GetHouseholdBudgetParams) (Budget, error)
+	GetHouseholdInvitation(ctx context.Context, tokenHash string) (HouseholdInvitation, error)
+	GetHouseholdMember(ctx context.Context, arg GetHouseholdMemberParams) (HouseholdMember, error)
+	GetLoginAttempt(ctx context.Context, arg GetLoginAttemptParams) (LoginAttempt, error)
+	GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (PersonalAccessToken, error)
+	GetReconciliation(ctx context.Context, arg GetReconciliationParams) (Reconciliation, error)
+	GetReconciliationForUpdate(ctx context.Context, id int64) (Reconciliation, error)
+	GetSettlementForUpdate(ctx context.Context, id int64) (Settlement, error)
+	GetSystemStats(ctx context.Context) (GetSystemStatsRow, error)
+	GetUnlockToken(ctx context.Context, token string) (UnlockToken, error)
 	GetUser(ctx context.Context, username string) (User, error)
+	GetUserByEmail(ctx context.Context, email string) (User, error)
+	GetUserIdentity(ctx context.Context, arg GetUserIdentityParams) (UserIdentity, error)
+	HouseholdFinancials(ctx context.Context, householdID pgtype.Int8) ([]HouseholdFinancialsRow, error)
 	InsertNewFinancial(ctx context.Context, arg InsertNewFinancialParams) (Financial, error)
+	IsPeriodClosed(ctx context.Context, arg IsPeriodClosedParams) (bool, error)
+	IsPeriodClosedAt(ctx context.Context, arg IsPeriodClosedAtParams) (bool, error)
+	// IsTimeZone reports whether Postgres knows the time zone, Go and Postgres ship their own zone databases.
+	IsTimeZone(ctx context.Context, name string) (bool, error)
+	LedgerBalance(ctx context.Context, userID string) (int64, error)
+	ListAdminAudits(ctx context.Context, arg ListAdminAuditsParams) ([]AdminAudit, error)
+	ListAnomalies(ctx context.Context, userID string) ([]ListAnomaliesRow, error)
+	ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]AuditEvent, error)
+	ListContacts(ctx context.Context, owner string) ([]Contact, error)
+	ListContactsByIds(ctx context.Context, ids []int64) ([]Contact, error)
+	ListDeletedFinancials(ctx context.Context, userID string) ([]ListDeletedFinancialsRow, error)
+	ListFinancialTypes(ctx context.Context) ([]FinancialType, error)
+	ListFinancialsSince(ctx context.Context, createdAt time.Time) ([]Financial, error)
+	ListHouseholdMembers(ctx context.Context, householdID int64) ([]ListHouseholdMembersRow, error)
+	ListMyHouseholds(ctx context.Context, username string) ([]ListMyHouseholdsRow, error)
+	// ListPeriodClosings lists the closed months with their totals as they are now, read from the aggregates.
+	ListPeriodClosings(ctx context.Context, userID string) ([]ListPeriodClosingsRow, error)
+	ListPersonalAccessTokens(ctx context.Context, username string) ([]PersonalAccessToken, error)
+	ListReconciliationCandidates(ctx context.Context, arg ListReconciliationCandidatesParams) ([]ListReconciliationCandidatesRow, error)
+	ListReconciliations(ctx context.Context, userID string) ([]Reconciliation, error)
+	ListSettlementsForUser(ctx context.Context, username string) ([]Settlement, error)
+	ListSharedExpenseShares(ctx context.Context, expenseIds []int64) ([]SharedExpenseShare, error)
+	ListSharedExpensesForUser(ctx context.Context, username string) ([]SharedExpense, error)
+	ListTypeFinancialsSince(ctx context.Context, arg ListTypeFinancialsSinceParams) ([]Financial, error)
+	ListUsernames(ctx context.Context) ([]string, error)
+	ListUsersToPurge(ctx context.Context, deletedAt pgtype.Timestamptz) ([]string, error)
+	// LockLoginAttempt creates the row of a key when it is missing and locks it until the end of the transaction.
+	LockLoginAttempt(ctx context.Context, arg LockLoginAttemptParams) (LoginAttempt, error)
+	LockReconciliation(ctx context.Context, id int64) (Reconciliation, error)
+	LockUser(ctx context.Context, username string) error
 	LoginUser(ctx context.Context, username string) (LoginUserRow, error)
+	MarkContactVerified(ctx context.Context, id int64) error
+	MarkFinancialsReconciled(ctx context.Context, reconciliationID pgtype.Int8) error
+	// month is the budgeting month of the user as YYYY-MM, see period_date.
+	MonthlySummaryByTypeBetween(ctx context.Context, arg MonthlySummaryByTypeBetweenParams) ([]MonthlySummaryByTypeBetweenRow, error)
 	MyFinancial(ctx context.Context, userID string) ([]MyFinancialRow, error)
+	// reconciled financials are kept, they are part of a finished statement.
+	PurgeDeletedFinancials(ctx context.Context, deletedAt pgtype.Timestamptz) ([]Financial, error)
+	// the household budgets a member set go to the household owner before the member is purged.
+	ReassignHouseholdBudgets(ctx context.Context, username string) ([]Budget, error)
+	// ReassignSharedExpenses hands the shared expenses a user created to another registered user on them, the payer first,
+	// so purging the user doesn't delete them from everyone's balances. Expenses nobody else is registered on go with the user.
+	ReassignSharedExpenses(ctx context.Context, username string) error
+	RebuildMonthlyAggregates(ctx context.Context, userID string) error
+	ReconciliationTotals(ctx context.Context, arg ReconciliationTotalsParams) (ReconciliationTotalsRow, error)
+	RecordContactVerificationFailure(ctx context.Context, username string) error
+	// failed_count is the number of failures since window_start, the new failure included.
+	// failed_count is the number of failures since window_start, the new failure included.
+	// The key then waits waits[failed_count] microseconds, the last wait once failed_count is past the end of waits.
+	RecordFailedLogin(ctx context.Context, arg RecordFailedLoginParams) (LoginAttempt, error)
+	RemoveHouseholdMember(ctx context.Context, arg RemoveHouseholdMemberParams) error
+	ReopenPeriod(ctx context.Context, arg ReopenPeriodParams) (PeriodClosing, error)
+	ResetClearedFinancials(ctx context.Context, reconciliationID pgtype.Int8) error
+	ResetLoginAttempts(ctx context.Context, arg ResetLoginAttemptsParams) error
+	RespondToSettlement(ctx context.Context, arg RespondToSettlementParams) (Settlement, error)
+	RestoreFinancial(ctx context.Context, id int64) (Financial, error)
+	RevokePersonalAccessToken(ctx context.Context, arg RevokePersonalAccessTokenParams) (PersonalAccessToken, error)
+	RevokeUserTokens(ctx context.Context, username string) (User, error)
+	ScheduleUserDeletion(ctx context.Context, username string) (User, error)
+	// pattern is an ILIKE pattern, escape the search term with a backslash.
+	SearchUsers(ctx context.Context, arg SearchUsersParams) ([]User, error)
+	SetUserDisabled(ctx context.Context, arg SetUserDisabledParams) (User, error)
+	SetUserRole(ctx context.Context, arg SetUserRoleParams) (User, error)
+	SummaryByTypeBetween(ctx context.Context, arg SummaryByTypeBetweenParams) ([]SummaryByTypeBetweenRow, error)
 	SummaryByTypeMonth(ctx context.Context, arg SummaryByTypeMonthParams) ([]SummaryByTypeMonthRow, error)
 	SummaryByTypeYear(ctx context.Context, arg SummaryByTypeYearParams) ([]SummaryByTypeYearRow, error)
 	SummaryFinancialByMonth(ctx context.Context, arg SummaryFinancialByMonthParams) (SummaryFinancialByMonthRow, error)
 	SummaryFinancialByYear(ctx context.Context, arg SummaryFinancialByYearParams) (SummaryFinancialByYearRow, error)
 	SummaryFinancialEachYear(ctx context.Context, userID string) ([]SummaryFinancialEachYearRow, error)
+	SummaryHouseholdByMember(ctx context.Context, arg SummaryHouseholdByMemberParams) ([]SummaryHouseholdByMemberRow, error)
+	TouchPersonalAccessToken(ctx context.Context, id int64) error
+	TypeMonthlyTotals(ctx context.Context, arg TypeMonthlyTotalsParams) ([]TypeMonthlyTotalsRow, error)
+	UnclearFinancials(ctx context.Context, arg UnclearFinancialsParams) ([]int64, error)
 	UpdateBudget(ctx context.Context, arg UpdateBudgetParams) (Budget, error)
 	UpdateFinancial(ctx context.Context, arg UpdateFinancialParams) (Financial, error)
+	UpdateFinancialType(ctx context.Context, arg UpdateFinancialTypeParams) (FinancialType, error)
+	UpdateHouseholdMemberRole(ctx context.Context, arg UpdateHouseholdMemberRoleParams) (HouseholdMember, error)
 	UpdatePassword(ctx context.Context, arg UpdatePasswordParams) error
+	// Only called once the address is verified.
+	UpdateUserEmail(ctx context.Context, arg UpdateUserEmailParams) (User, error)
+	UpdateUserName(ctx context.Context, arg UpdateUserNameParams) (User, error)
+	UpdateUserPhone(ctx context.Context, arg UpdateUserPhoneParams) (User, error)
+	UpdateUserPreferences(ctx context.Context, arg UpdateUserPreferencesParams) (User, error)
+	UseUnlockToken(ctx context.Context, token string) error
+	UserAmountsSince(ctx context.Context, arg UserAmountsSinceParams) ([]int64, error)
+	// Two users are connected when they share a household, or when other keeps the verified email of username as a contact.
+	// Only connected users can name each other in a settlement or a shared expense.
+	UsersAreConnected(ctx context.Context, arg UsersAreConnectedParams) (bool, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -2,29 +2,104 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type Store interface {
 	Querier
+	PurgeUserTx(ctx context.Context, username string) error
+	CreateHouseholdTx(ctx context.Context, arg CreateHouseholdParams) (Household, error)
+	AcceptHouseholdInvitationTx(ctx context.Context, invitation HouseholdInvitation, username string) (HouseholdMember, error)
+	CreateSharedExpenseTx(ctx context.Context, arg CreateSharedExpenseParams, shares []AddSharedExpenseShareParams) (SharedExpenseTxResult, error)
+	CreateSettlementTx(ctx context.Context, arg CreateSettlementTxParams) (Settlement, error)
+	RespondToSettlementTx(ctx context.Context, arg RespondToSettlementTxParams) (Settlement, error)
+	InsertFinancialTx(ctx context.Context, arg InsertNewFinancialParams, audit AuditInfo) (Financial, error)
+	UpdateFinancialTx(ctx context.Context, arg UpdateFinancialTxParams) (Financial, error)
+	DeleteFinancialTx(ctx context.Context, arg DeleteFinancialTxParams) (Financial, error)
+	RestoreFinancialTx(ctx context.Context, id int64, audit AuditInfo) (Financial, error)
+	PurgeFinancialsTx(ctx context.Context, deletedBefore time.Time) ([]Financial, error)
+	AddBudgetTx(ctx context.Context, arg AddNewBudgetParams, audit AuditInfo) (Budget, error)
+	AddHouseholdBudgetTx(ctx context.Context, arg AddNewHouseholdBudgetParams, audit AuditInfo) (Budget, error)
+	UpdateBudgetTx(ctx context.Context, arg UpdateBudgetParams, audit AuditInfo) (Budget, error)
+	CancelReconciliationTx(ctx context.Context, id int64) error
+	FinishReconciliationTx(ctx context.Context, id int64, audit AuditInfo) (Reconciliation, error)
+	ClosePeriodTx(ctx context.Context, arg ClosePeriodTxParams) (PeriodClosing, error)
+	ReopenPeriodTx(ctx context.Context, arg ReopenPeriodParams, audit AuditInfo) (PeriodClosing, error)
+	RebuildMonthlyAggregatesTx(ctx context.Context, username string) error
+	UpdateProfileTx(ctx context.Context, arg UpdateProfileTxParams) (User, error)
+	CreateContactVerificationTx(ctx context.Context, arg CreateContactVerificationTxParams) (ContactVerification, error)
+	InsertFinancialWithUsageTx(ctx context.Context, arg InsertFinancialWithUsageTxParams) (InsertFinancialWithUsageTxResult, error)
+	AdminTx(ctx context.Context, audit AdminAuditInfo, fn func(q *Queries) (details any, err error)) error
+	UpdateFinancialTypeTx(ctx context.Context, arg UpdateFinancialTypeParams, audit AdminAuditInfo) (FinancialType, error)
+	DeleteFinancialTypeTx(ctx context.Context, id int64, audit AdminAuditInfo) (FinancialType, error)
+	RecordFailedLoginTx(ctx context.Context, arg RecordFailedLoginParams) (RecordFailedLoginTxResult, error)
+	Ping(ctx context.Context) error
+	MigrationVersion(ctx context.Context) (version int64, dirty bool, err error)
+	PoolStat() *pgxpool.Stat
 }
 
 type SQLStore struct {
 	*Queries
-	db *pgx.Conn
+	db *pgxpool.Pool
 }
 
-func NewStore(db *pgx.Conn) Store{
+func NewStore(db *pgxpool.Pool) Store{
 	return &SQLStore{
 		db: db,
 		Queries: New(db),
 	}
 }
 
+const (
+	// txMaxAttempts is how often a transaction runs before a serialization failure is given up on.
+	txMaxAttempts = 5
+	txRetryDelay  = 10 * time.Millisecond
+)
+
 func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) error{
+	return store.execTxIso(ctx, pgx.ReadCommitted, fn)
+}
+
+// execTxIso runs fn in a transaction with the given isolation level.
+// When Postgres aborts it with a serialization failure or a deadlock, fn runs again in a new transaction,
+// so fn must not keep anything but its results outside of it.
+func (store *SQLStore) execTxIso(ctx context.Context, iso pgx.TxIsoLevel, fn func(*Queries) error) error{
+	var err error
+	for attempt := 1; attempt <= txMaxAttempts; attempt++ {
+		err = store.runTx(ctx, iso, fn)
+		if err == nil || !isRetryable(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * txRetryDelay):
+		}
+	}
+
+	return fmt.Errorf("tx gave up after %d attempts: %w", txMaxAttempts, err)
+}
+
+// isRetryable reports whether a transaction failed only because of concurrent transactions.
+func isRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	// serialization_failure and deadlock_detected
+	return pgErr.Code == "40001" || pgErr.Code == "40P01"
+}
+
+func (store *SQLStore) runTx(ctx context.Context, iso pgx.TxIsoLevel, fn func(*Queries) error) error{
 	tx, err := store.db.BeginTx(ctx, pgx.TxOptions{
-		IsoLevel: pgx.ReadCommitted,
+		IsoLevel: iso,
 	})
 
 	if err != nil{
@@ -35,7 +110,7 @@ func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) erro
 	err = fn(q)
 	if err != nil{
 		if rbErr := tx.Rollback(ctx) ; rbErr != nil{
-			return fmt.Errorf("tx err: %v, rb err: %v", err, rbErr)
+			return fmt.Errorf("tx err: %w, rb err: %v", err, rbErr)
 		}
 
 		return err
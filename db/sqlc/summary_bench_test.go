@@ -0,0 +1,107 @@
+package db
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// benchUser owns the records of the summary benchmarks, it only lives in a rolled back transaction.
+const benchUser = "bench"
+
+// seedSummaryBench loads 1M records over five years for benchUser, like db/bench/summary.sql.
+// The trigger fills monthly_aggregates on the way.
+const seedSummaryBench = `
+INSERT INTO users (username, name, email, phone, password)
+VALUES ('bench', 'Bench', 'bench@example.com', '0000000000', 'x');
+
+INSERT INTO financial_types (type) VALUES ('bench') ON CONFLICT DO NOTHING;
+
+INSERT INTO financials (user_id, amount, direction, type_id, created_at)
+SELECT
+  'bench',
+  CASE WHEN i % 5 = 0 THEN 1000 + i % 9000 ELSE -(10 + i % 2000) END,
+  CASE WHEN i % 5 = 0 THEN 'in' ELSE 'out' END,
+  t.ids[1 + i % array_length(t.ids, 1)],
+  NOW() - make_interval(secs => i * 157)
+FROM generate_series(1, 1000000) AS i,
+  (SELECT array_agg(id ORDER BY id) AS ids FROM financial_types) AS t;
+
+ANALYZE financials;
+ANALYZE monthly_aggregates;
+`
+
+// summaryBenchQueries seeds a migrated test database named by TEST_DB_SOURCE inside a transaction
+// that is rolled back when the benchmark ends. Without TEST_DB_SOURCE the benchmark is skipped.
+func summaryBenchQueries(b *testing.B) *Queries {
+	b.Helper()
+
+	source := os.Getenv("TEST_DB_SOURCE")
+	if source == "" {
+		b.Skip("TEST_DB_SOURCE is not set")
+	}
+
+	ctx := context.Background()
+	conn, err := pgx.Connect(ctx, source)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { conn.Close(ctx) })
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { tx.Rollback(ctx) })
+
+	if _, err := tx.Exec(ctx, seedSummaryBench); err != nil {
+		b.Fatal(err)
+	}
+
+	return New(tx)
+}
+
+func BenchmarkSummaryFinancialByMonth(b *testing.B) {
+	q := summaryBenchQueries(b)
+	ctx := context.Background()
+	now := time.Now()
+
+	for b.Loop() {
+		if _, err := q.SummaryFinancialByMonth(ctx, SummaryFinancialByMonthParams{
+			UserID: benchUser,
+			Month:  int32(now.Month()),
+			Year:   int32(now.Year()),
+		}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSummaryFinancialByYear(b *testing.B) {
+	q := summaryBenchQueries(b)
+	ctx := context.Background()
+	now := time.Now()
+
+	for b.Loop() {
+		if _, err := q.SummaryFinancialByYear(ctx, SummaryFinancialByYearParams{
+			UserID: benchUser,
+			Year:   int32(now.Year()),
+		}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSummaryFinancialEachYear(b *testing.B) {
+	q := summaryBenchQueries(b)
+	ctx := context.Background()
+
+	for b.Loop() {
+		if _, err := q.SummaryFinancialEachYear(ctx, benchUser); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// FallbackFinancialType is the type of records whose type is unknown, and of settlements.
+const FallbackFinancialType = "Other"
+
+var ErrFallbackFinancialType = errors.New("the fallback financial type cannot be changed")
+
+// AdminAuditInfo describes an admin action, the details are what the action returns.
+type AdminAuditInfo struct {
+	Admin    string
+	Action   string
+	Target   string
+	ClientIP string
+}
+
+// AdminTx runs an admin action and records it in admin_audits in the same transaction.
+// fn returns the details of the audit, when the audit cannot be written the action is rolled back.
+func (store *SQLStore) AdminTx(ctx context.Context, audit AdminAuditInfo, fn func(q *Queries) (details any, err error)) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		details, err := fn(q)
+		if err != nil {
+			return err
+		}
+
+		return recordAdminAudit(ctx, q, audit, details)
+	})
+}
+
+func recordAdminAudit(ctx context.Context, q *Queries, audit AdminAuditInfo, details any) error {
+	data, err := json.Marshal(details)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CreateAdminAudit(ctx, CreateAdminAuditParams{
+		Admin:    audit.Admin,
+		Action:   audit.Action,
+		Target:   audit.Target,
+		Details:  data,
+		ClientIp: audit.ClientIP,
+	})
+	return err
+}
+
+// checkNotFallback locks the financial type and fails with ErrFallbackFinancialType when it is the fallback type.
+func checkNotFallback(ctx context.Context, q *Queries, id int64) error {
+	financialType, err := q.GetFinancialTypeForUpdate(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if financialType.Type == FallbackFinancialType {
+		return ErrFallbackFinancialType
+	}
+
+	return nil
+}
+
+// UpdateFinancialTypeTx renames a financial type, the fallback type keeps its name.
+func (store *SQLStore) UpdateFinancialTypeTx(ctx context.Context, arg UpdateFinancialTypeParams, audit AdminAuditInfo) (FinancialType, error) {
+	var financialType FinancialType
+
+	err := store.AdminTx(ctx, audit, func(q *Queries) (any, error) {
+		if err := checkNotFallback(ctx, q, arg.ID); err != nil {
+			return nil, err
+		}
+
+		var err error
+		financialType, err = q.UpdateFinancialType(ctx, arg)
+		return financialType, err
+	})
+
+	return financialType, err
+}
+
+// DeleteFinancialTypeTx deletes a financial type, the fallback type cannot be deleted.
+func (store *SQLStore) DeleteFinancialTypeTx(ctx context.Context, id int64, audit AdminAuditInfo) (FinancialType, error) {
+	var financialType FinancialType
+
+	err := store.AdminTx(ctx, audit, func(q *Queries) (any, error) {
+		if err := checkNotFallback(ctx, q, id); err != nil {
+			return nil, err
+		}
+
+		var err error
+		financialType, err = q.DeleteFinancialType(ctx, id)
+		return financialType, err
+	})
+
+	return financialType, err
+}
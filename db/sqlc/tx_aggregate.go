@@ -0,0 +1,27 @@
+package db
+
+import (
+	"context"
+)
+
+// resetMonthlyAggregates recomputes the monthly aggregates of a user from the records.
+// The user row must be locked so records written meanwhile wait for the rebuild.
+func resetMonthlyAggregates(ctx context.Context, q *Queries, username string) error {
+	if err := q.DeleteMonthlyAggregates(ctx, username); err != nil {
+		return err
+	}
+
+	return q.RebuildMonthlyAggregates(ctx, username)
+}
+
+// RebuildMonthlyAggregatesTx repairs the monthly aggregates of a user.
+// They are kept up to date by a trigger on financials, this is for data loaded around it.
+func (store *SQLStore) RebuildMonthlyAggregatesTx(ctx context.Context, username string) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		if err := q.LockUser(ctx, username); err != nil {
+			return err
+		}
+
+		return resetMonthlyAggregates(ctx, q, username)
+	})
+}
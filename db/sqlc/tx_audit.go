@@ -0,0 +1,276 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionRestore = "restore"
+	AuditActionPurge   = "purge"
+	AuditActionClose   = "close"
+	AuditActionReopen  = "reopen"
+
+	// AuditActorSystem is the actor of changes made by background jobs.
+	AuditActorSystem = "system"
+
+	AuditEntityFinancial      = "financial"
+	AuditEntityBudget         = "budget"
+	AuditEntityReconciliation = "reconciliation"
+	AuditEntityPeriod         = "period"
+)
+
+// AuditInfo describes who made a change and from where.
+type AuditInfo struct {
+	Actor     string
+	IP        string
+	UserAgent string
+}
+
+// recordAudit appends an audit event, before or after is nil when the entity did not exist on that side of the change.
+func recordAudit(ctx context.Context, q *Queries, audit AuditInfo, action string, entityType string, entityID int64, before any, after any) error {
+	arg := CreateAuditEventParams{
+		Actor:      audit.Actor,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Ip:         audit.IP,
+		UserAgent:  audit.UserAgent,
+	}
+
+	var err error
+	if before != nil {
+		if arg.Before, err = json.Marshal(before); err != nil {
+			return err
+		}
+	}
+
+	if after != nil {
+		if arg.After, err = json.Marshal(after); err != nil {
+			return err
+		}
+	}
+
+	_, err = q.CreateAuditEvent(ctx, arg)
+	return err
+}
+
+// InsertFinancialTx inserts a financial and audits it in the same transaction.
+func (store *SQLStore) InsertFinancialTx(ctx context.Context, arg InsertNewFinancialParams, audit AuditInfo) (Financial, error) {
+	var financial Financial
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		financial, err = insertFinancial(ctx, q, arg, audit)
+		return err
+	})
+
+	return financial, err
+}
+
+func insertFinancial(ctx context.Context, q *Queries, arg InsertNewFinancialParams, audit AuditInfo) (Financial, error) {
+	if err := checkPeriodOpenAt(ctx, q, arg.UserID, time.Now()); err != nil {
+		return Financial{}, err
+	}
+
+	financial, err := q.InsertNewFinancial(ctx, arg)
+	if err != nil {
+		return Financial{}, err
+	}
+
+	return financial, recordAudit(ctx, q, audit, AuditActionCreate, AuditEntityFinancial, financial.ID, nil, financial)
+}
+
+type UpdateFinancialTxParams struct {
+	Financial UpdateFinancialParams
+	// AllowReconciled lets the user change a record that was matched against a bank statement.
+	AllowReconciled bool
+	Audit           AuditInfo
+}
+
+// UpdateFinancialTx updates a financial and audits the old and new values in the same transaction.
+func (store *SQLStore) UpdateFinancialTx(ctx context.Context, arg UpdateFinancialTxParams) (Financial, error) {
+	var financial Financial
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		before, err := q.GetFinancialForUpdate(ctx, arg.Financial.ID)
+		if err != nil {
+			return err
+		}
+
+		if before.ReconcileStatus == ReconcileStatusReconciled && !arg.AllowReconciled {
+			return ErrFinancialReconciled
+		}
+
+		if err := checkPeriodOpenAt(ctx, q, before.UserID, before.CreatedAt); err != nil {
+			return err
+		}
+
+		financial, err = q.UpdateFinancial(ctx, arg.Financial)
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, q, arg.Audit, AuditActionUpdate, AuditEntityFinancial, financial.ID, before, financial)
+	})
+
+	return financial, err
+}
+
+type DeleteFinancialTxParams struct {
+	ID    int64
+	Audit AuditInfo
+}
+
+// DeleteFinancialTx moves a financial to the trash and audits it in the same transaction.
+// A reconciled financial is never deleted, it is part of a finished statement.
+func (store *SQLStore) DeleteFinancialTx(ctx context.Context, arg DeleteFinancialTxParams) (Financial, error) {
+	var financial Financial
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		before, err := q.GetFinancialForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		if before.ReconcileStatus == ReconcileStatusReconciled {
+			return ErrFinancialReconciled
+		}
+
+		if err := checkPeriodOpenAt(ctx, q, before.UserID, before.CreatedAt); err != nil {
+			return err
+		}
+
+		financial, err = q.DeleteFinancial(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, q, arg.Audit, AuditActionDelete, AuditEntityFinancial, financial.ID, before, financial)
+	})
+
+	return financial, err
+}
+
+// RestoreFinancialTx takes a financial out of the trash and audits it in the same transaction.
+func (store *SQLStore) RestoreFinancialTx(ctx context.Context, id int64, audit AuditInfo) (Financial, error) {
+	var financial Financial
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		before, err := q.GetFinancialForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := checkPeriodOpenAt(ctx, q, before.UserID, before.CreatedAt); err != nil {
+			return err
+		}
+
+		financial, err = q.RestoreFinancial(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, q, audit, AuditActionRestore, AuditEntityFinancial, financial.ID, before, financial)
+	})
+
+	return financial, err
+}
+
+// PurgeFinancialsTx hard deletes the financials that were moved to the trash before deletedBefore.
+func (store *SQLStore) PurgeFinancialsTx(ctx context.Context, deletedBefore time.Time) ([]Financial, error) {
+	var financials []Financial
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		financials, err = q.PurgeDeletedFinancials(ctx, pgtype.Timestamptz{Time: deletedBefore, Valid: true})
+		if err != nil {
+			return err
+		}
+
+		audit := AuditInfo{Actor: AuditActorSystem}
+		for _, financial := range financials {
+			if err := recordAudit(ctx, q, audit, AuditActionPurge, AuditEntityFinancial, financial.ID, financial, nil); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return financials, err
+}
+
+// AddBudgetTx adds a personal budget and audits it in the same transaction.
+func (store *SQLStore) AddBudgetTx(ctx context.Context, arg AddNewBudgetParams, audit AuditInfo) (Budget, error) {
+	var budget Budget
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		if err := checkPeriodOpen(ctx, q, arg.UserID, arg.Month, arg.Year); err != nil {
+			return err
+		}
+
+		var err error
+		budget, err = q.AddNewBudget(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, q, audit, AuditActionCreate, AuditEntityBudget, int64(budget.ID), nil, budget)
+	})
+
+	return budget, err
+}
+
+// AddHouseholdBudgetTx adds a household budget and audits it in the same transaction.
+func (store *SQLStore) AddHouseholdBudgetTx(ctx context.Context, arg AddNewHouseholdBudgetParams, audit AuditInfo) (Budget, error) {
+	var budget Budget
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		if err := checkPeriodOpen(ctx, q, arg.UserID, arg.Month, arg.Year); err != nil {
+			return err
+		}
+
+		var err error
+		budget, err = q.AddNewHouseholdBudget(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, q, audit, AuditActionCreate, AuditEntityBudget, int64(budget.ID), nil, budget)
+	})
+
+	return budget, err
+}
+
+// UpdateBudgetTx updates a budget and audits the old and new values in the same transaction.
+func (store *SQLStore) UpdateBudgetTx(ctx context.Context, arg UpdateBudgetParams, audit AuditInfo) (Budget, error) {
+	var budget Budget
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		before, err := q.GetBudgetForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := checkPeriodOpen(ctx, q, before.UserID, before.Month, before.Year); err != nil {
+			return err
+		}
+
+		budget, err = q.UpdateBudget(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, q, audit, AuditActionUpdate, AuditEntityBudget, int64(budget.ID), before, budget)
+	})
+
+	return budget, err
+}
@@ -0,0 +1,59 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type InsertFinancialWithUsageTxParams struct {
+	Financial InsertNewFinancialParams
+	// Month and Year name the budgeting month to report the usage of.
+	Month int32
+	Year  int32
+	Audit AuditInfo
+}
+
+type InsertFinancialWithUsageTxResult struct {
+	Financial Financial
+	// HasBudget is false when the user set no budget for the month, Budget is empty then.
+	HasBudget bool
+	Budget    Budget
+	Summary   SummaryFinancialByMonthRow
+}
+
+// InsertFinancialWithUsageTx inserts a financial and reads the budget and totals of the month it lands in.
+// It runs serializable so the usage counts the new record and no record added at the same time is left out,
+// the transaction is retried when Postgres cannot serialize it.
+func (store *SQLStore) InsertFinancialWithUsageTx(ctx context.Context, arg InsertFinancialWithUsageTxParams) (InsertFinancialWithUsageTxResult, error) {
+	var result InsertFinancialWithUsageTxResult
+
+	err := store.execTxIso(ctx, pgx.Serializable, func(q *Queries) error {
+		result = InsertFinancialWithUsageTxResult{}
+
+		var err error
+		result.Financial, err = insertFinancial(ctx, q, arg.Financial, arg.Audit)
+		if err != nil {
+			return err
+		}
+
+		result.Budget, err = q.GetBudget(ctx, GetBudgetParams{
+			Month:  arg.Month,
+			Year:   arg.Year,
+			UserID: arg.Financial.UserID,
+		})
+		if err != nil && err != pgx.ErrNoRows {
+			return err
+		}
+		result.HasBudget = err == nil
+
+		result.Summary, err = q.SummaryFinancialByMonth(ctx, SummaryFinancialByMonthParams{
+			UserID: arg.Financial.UserID,
+			Month:  arg.Month,
+			Year:   arg.Year,
+		})
+		return err
+	})
+
+	return result, err
+}
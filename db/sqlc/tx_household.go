@@ -0,0 +1,48 @@
+package db
+
+import "context"
+
+// CreateHouseholdTx creates a household and adds its creator as the owner member.
+func (store *SQLStore) CreateHouseholdTx(ctx context.Context, arg CreateHouseholdParams) (Household, error) {
+	var household Household
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		household, err = q.CreateHousehold(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.AddHouseholdMember(ctx, AddHouseholdMemberParams{
+			HouseholdID: household.ID,
+			Username:    arg.Owner,
+			Role:        "owner",
+		})
+		return err
+	})
+
+	return household, err
+}
+
+// AcceptHouseholdInvitationTx adds the user to the invited household and marks the invitation as used.
+func (store *SQLStore) AcceptHouseholdInvitationTx(ctx context.Context, invitation HouseholdInvitation, username string) (HouseholdMember, error) {
+	var member HouseholdMember
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		member, err = q.AddHouseholdMember(ctx, AddHouseholdMemberParams{
+			HouseholdID: invitation.HouseholdID,
+			Username:    username,
+			Role:        invitation.Role,
+		})
+		if err != nil {
+			return err
+		}
+
+		return q.AcceptHouseholdInvitation(ctx, invitation.ID)
+	})
+
+	return member, err
+}
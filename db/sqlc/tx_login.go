@@ -0,0 +1,37 @@
+package db
+
+import "context"
+
+// RecordFailedLoginTxResult is a key after a failed login.
+// WasLocked tells the key was already locked when the attempt failed, e.g. by a parallel attempt.
+type RecordFailedLoginTxResult struct {
+	Attempt   LoginAttempt
+	WasLocked bool
+}
+
+// RecordFailedLoginTx counts a failed login against a key and pushes back its next allowed attempt.
+// The row of the key is locked first, so parallel failures are counted one after the other.
+func (store *SQLStore) RecordFailedLoginTx(ctx context.Context, arg RecordFailedLoginParams) (RecordFailedLoginTxResult, error) {
+	var result RecordFailedLoginTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		before, err := q.LockLoginAttempt(ctx, LockLoginAttemptParams{
+			Scope:      arg.Scope,
+			Identifier: arg.Identifier,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Attempt, err = q.RecordFailedLogin(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		// both statements see the same NOW(), the start of the transaction
+		result.WasLocked = before.LockedUntil.After(result.Attempt.LastFailedAt)
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrPeriodClosed = errors.New("period is closed")
+
+// checkPeriodOpen fails with ErrPeriodClosed when the user has closed the month.
+// It locks the user first, like ClosePeriodTx and ReopenPeriodTx, so the month stays open
+// or closed until the transaction that writes into it ends.
+func checkPeriodOpen(ctx context.Context, q *Queries, userID string, month int32, year int32) error {
+	if err := q.LockUser(ctx, userID); err != nil {
+		return err
+	}
+
+	closed, err := q.IsPeriodClosed(ctx, IsPeriodClosedParams{
+		UserID: userID,
+		Month:  month,
+		Year:   year,
+	})
+	if err != nil {
+		return err
+	}
+
+	if closed {
+		return ErrPeriodClosed
+	}
+
+	return nil
+}
+
+// checkPeriodOpenAt is checkPeriodOpen for the month holding t in the time zone of the user.
+func checkPeriodOpenAt(ctx context.Context, q *Queries, userID string, t time.Time) error {
+	if err := q.LockUser(ctx, userID); err != nil {
+		return err
+	}
+
+	closed, err := q.IsPeriodClosedAt(ctx, IsPeriodClosedAtParams{
+		UserID: userID,
+		At:     t,
+	})
+	if err != nil {
+		return err
+	}
+
+	if closed {
+		return ErrPeriodClosed
+	}
+
+	return nil
+}
+
+type ClosePeriodTxParams struct {
+	UserID string
+	Month  int32
+	Year   int32
+	Audit  AuditInfo
+}
+
+// ClosePeriodTx closes a month and keeps a snapshot of its totals to compare with later.
+// The user is locked first, records being written into the month are in the snapshot or wait for the closing.
+func (store *SQLStore) ClosePeriodTx(ctx context.Context, arg ClosePeriodTxParams) (PeriodClosing, error) {
+	var closing PeriodClosing
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		if err := q.LockUser(ctx, arg.UserID); err != nil {
+			return err
+		}
+
+		summary, err := q.SummaryFinancialByMonth(ctx, SummaryFinancialByMonthParams{
+			UserID: arg.UserID,
+			Month:  arg.Month,
+			Year:   arg.Year,
+		})
+		if err != nil {
+			return err
+		}
+
+		closing, err = q.CreatePeriodClosing(ctx, CreatePeriodClosingParams{
+			UserID:       arg.UserID,
+			Month:        arg.Month,
+			Year:         arg.Year,
+			TotalIncome:  summary.TotalIncome,
+			TotalExpense: summary.TotalExpense,
+			Status:       summary.Status,
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, q, arg.Audit, AuditActionClose, AuditEntityPeriod, closing.ID, nil, closing)
+	})
+
+	return closing, err
+}
+
+// ReopenPeriodTx reopens a closed month, the closing stays as history.
+func (store *SQLStore) ReopenPeriodTx(ctx context.Context, arg ReopenPeriodParams, audit AuditInfo) (PeriodClosing, error) {
+	var closing PeriodClosing
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		if err := q.LockUser(ctx, arg.UserID); err != nil {
+			return err
+		}
+
+		var err error
+		closing, err = q.ReopenPeriod(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, q, audit, AuditActionReopen, AuditEntityPeriod, closing.ID, nil, closing)
+	})
+
+	return closing, err
+}
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrUnknownTimeZone      = errors.New("unknown time zone")
+	ErrTooManyVerifications = errors.New("too many verification codes requested")
+)
+
+type UpdateProfileTxParams struct {
+	Username string
+	// Name is kept when it is empty.
+	Name string
+	// Preferences are kept when they are nil.
+	Preferences *UpdateUserPreferencesParams
+}
+
+// UpdateProfileTx changes the name and the preferences of a user, all of them or none.
+// A new time zone or period start day moves records to other months, so the aggregates are rebuilt with it.
+// The time zone must be known to Postgres, the months are computed there; ErrUnknownTimeZone otherwise.
+func (store *SQLStore) UpdateProfileTx(ctx context.Context, arg UpdateProfileTxParams) (User, error) {
+	var user User
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		user, err = q.GetUser(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		if arg.Name != "" {
+			user, err = q.UpdateUserName(ctx, UpdateUserNameParams{
+				Name:     arg.Name,
+				Username: arg.Username,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if arg.Preferences == nil {
+			return nil
+		}
+
+		known, err := q.IsTimeZone(ctx, arg.Preferences.TimeZone)
+		if err != nil {
+			return err
+		}
+		if !known {
+			return ErrUnknownTimeZone
+		}
+
+		before := user
+		user, err = q.UpdateUserPreferences(ctx, *arg.Preferences)
+		if err != nil {
+			return err
+		}
+
+		if user.TimeZone == before.TimeZone && user.PeriodStartDay == before.PeriodStartDay {
+			return nil
+		}
+
+		return resetMonthlyAggregates(ctx, q, user.Username)
+	})
+
+	return user, err
+}
+
+type CreateContactVerificationTxParams struct {
+	Verification CreateContactVerificationParams
+	// MaxSends is how many codes a user gets since SendWindowStart, fields together.
+	MaxSends        int64
+	SendWindowStart time.Time
+}
+
+// CreateContactVerificationTx creates a verification code unless the user asked for too many lately,
+// ErrTooManyVerifications then. The new code replaces the pending ones of the field, so asking for
+// a new code never gives more attempts than the codes allowed in the window.
+func (store *SQLStore) CreateContactVerificationTx(ctx context.Context, arg CreateContactVerificationTxParams) (ContactVerification, error) {
+	var verification ContactVerification
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		if err := q.LockUser(ctx, arg.Verification.Username); err != nil {
+			return err
+		}
+
+		sent, err := q.CountContactVerificationsSince(ctx, CountContactVerificationsSinceParams{
+			Username:  arg.Verification.Username,
+			CreatedAt: arg.SendWindowStart,
+		})
+		if err != nil {
+			return err
+		}
+
+		if sent >= arg.MaxSends {
+			return ErrTooManyVerifications
+		}
+
+		verification, err = q.CreateContactVerification(ctx, arg.Verification)
+		return err
+	})
+
+	return verification, err
+}
@@ -0,0 +1,63 @@
+package db
+
+import "context"
+
+// PurgeUserTx hard deletes a user together with every record they own.
+// Everything is removed in one transaction so a failure never leaves half a user behind,
+// and every removed record gets a purge audit event in the same transaction.
+func (store *SQLStore) PurgeUserTx(ctx context.Context, username string) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		audit := AuditInfo{Actor: AuditActorSystem}
+
+		financials, err := q.DeleteUserFinancials(ctx, username)
+		if err != nil {
+			return err
+		}
+
+		for _, financial := range financials {
+			if err := recordAudit(ctx, q, audit, AuditActionPurge, AuditEntityFinancial, financial.ID, financial, nil); err != nil {
+				return err
+			}
+		}
+
+		// the budgets of a household the user only belongs to stay with the household,
+		// the households the user owns are deleted together with the user
+		reassigned, err := q.ReassignHouseholdBudgets(ctx, username)
+		if err != nil {
+			return err
+		}
+
+		for _, budget := range reassigned {
+			before := budget
+			before.UserID = username
+			if err := recordAudit(ctx, q, audit, AuditActionUpdate, AuditEntityBudget, int64(budget.ID), before, budget); err != nil {
+				return err
+			}
+		}
+
+		// shared expenses cascade with their creator, the other users on them keep them
+		if err := q.ReassignSharedExpenses(ctx, username); err != nil {
+			return err
+		}
+
+		budgets, err := q.DeleteUserBudgets(ctx, username)
+		if err != nil {
+			return err
+		}
+
+		for _, budget := range budgets {
+			if err := recordAudit(ctx, q, audit, AuditActionPurge, AuditEntityBudget, int64(budget.ID), budget, nil); err != nil {
+				return err
+			}
+		}
+
+		if err := q.ResetLoginAttempts(ctx, ResetLoginAttemptsParams{
+			Scope:      "username",
+			Identifier: username,
+		}); err != nil {
+			return err
+		}
+
+		return q.DeleteUser(ctx, username)
+	})
+}
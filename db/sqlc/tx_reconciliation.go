@@ -0,0 +1,88 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	ReconcileStatusUncleared  = "uncleared"
+	ReconcileStatusCleared    = "cleared"
+	ReconcileStatusReconciled = "reconciled"
+)
+
+var (
+	ErrFinancialReconciled      = errors.New("financial is reconciled")
+	ErrReconciliationLocked     = errors.New("reconciliation is locked")
+	ErrReconciliationUnbalanced = errors.New("reconciliation is not balanced")
+)
+
+// ReconciliationDifference is the part of the statement balance that the cleared records do not explain yet.
+func ReconciliationDifference(reconciliation Reconciliation, totals ReconciliationTotalsRow) int64 {
+	return reconciliation.StatementBalance - totals.ReconciledBalance - totals.ClearedBalance
+}
+
+// CancelReconciliationTx drops an unfinished reconciliation and unticks its cleared records.
+func (store *SQLStore) CancelReconciliationTx(ctx context.Context, id int64) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		reconciliation, err := q.GetReconciliationForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if reconciliation.LockedAt.Valid {
+			return ErrReconciliationLocked
+		}
+
+		if err := q.ResetClearedFinancials(ctx, pgtype.Int8{Int64: id, Valid: true}); err != nil {
+			return err
+		}
+
+		_, err = q.DeleteReconciliation(ctx, id)
+		return err
+	})
+}
+
+// FinishReconciliationTx marks the cleared records as reconciled and locks the reconciliation.
+// It fails with ErrReconciliationUnbalanced while the difference is not zero.
+func (store *SQLStore) FinishReconciliationTx(ctx context.Context, id int64, audit AuditInfo) (Reconciliation, error) {
+	var reconciliation Reconciliation
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		before, err := q.GetReconciliationForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if before.LockedAt.Valid {
+			return ErrReconciliationLocked
+		}
+
+		totals, err := q.ReconciliationTotals(ctx, ReconciliationTotalsParams{
+			ReconciliationID: id,
+			UserID:           before.UserID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if ReconciliationDifference(before, totals) != 0 {
+			return ErrReconciliationUnbalanced
+		}
+
+		if err := q.MarkFinancialsReconciled(ctx, pgtype.Int8{Int64: id, Valid: true}); err != nil {
+			return err
+		}
+
+		reconciliation, err = q.LockReconciliation(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, q, audit, AuditActionUpdate, AuditEntityReconciliation, reconciliation.ID, before, reconciliation)
+	})
+
+	return reconciliation, err
+}
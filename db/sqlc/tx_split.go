@@ -0,0 +1,169 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type SharedExpenseTxResult struct {
+	Expense SharedExpense        `json:"expense"`
+	Shares  []SharedExpenseShare `json:"shares"`
+}
+
+// CreateSharedExpenseTx stores a shared expense together with the share of every participant.
+func (store *SQLStore) CreateSharedExpenseTx(ctx context.Context, arg CreateSharedExpenseParams, shares []AddSharedExpenseShareParams) (SharedExpenseTxResult, error) {
+	var result SharedExpenseTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Expense, err = q.CreateSharedExpense(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		for _, share := range shares {
+			share.ExpenseID = result.Expense.ID
+
+			created, err := q.AddSharedExpenseShare(ctx, share)
+			if err != nil {
+				return err
+			}
+
+			result.Shares = append(result.Shares, created)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+const (
+	SettlementStatusPending   = "pending"
+	SettlementStatusConfirmed = "confirmed"
+	SettlementStatusRejected  = "rejected"
+)
+
+// ErrSettlementNotPending is returned when answering a settlement that was already confirmed or rejected.
+var ErrSettlementNotPending = errors.New("settlement is not pending")
+
+type CreateSettlementTxParams struct {
+	Settlement CreateSettlementParams
+	// Caller is the user recording the settlement, one of its sides.
+	Caller string
+	TypeID int64
+	Audit  AuditInfo
+}
+
+// CreateSettlementTx records a settle-up payment and the financial record of the caller in the same transaction.
+// When the other side is a registered user the settlement is pending, their record is only written once they confirm it.
+func (store *SQLStore) CreateSettlementTx(ctx context.Context, arg CreateSettlementTxParams) (Settlement, error) {
+	var settlement Settlement
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		params := arg.Settlement
+		params.Status = SettlementStatusConfirmed
+
+		switch arg.Caller {
+		case params.FromUser.String:
+			financial, err := insertSettlementFinancial(ctx, q, arg.Caller, -params.Amount, "out", arg.TypeID, arg.Audit)
+			if err != nil {
+				return err
+			}
+			params.FromFinancialID = pgtype.Int8{Int64: financial.ID, Valid: true}
+
+			if params.ToUser.Valid {
+				params.Status = SettlementStatusPending
+			}
+		case params.ToUser.String:
+			financial, err := insertSettlementFinancial(ctx, q, arg.Caller, params.Amount, "in", arg.TypeID, arg.Audit)
+			if err != nil {
+				return err
+			}
+			params.ToFinancialID = pgtype.Int8{Int64: financial.ID, Valid: true}
+
+			if params.FromUser.Valid {
+				params.Status = SettlementStatusPending
+			}
+		default:
+			return fmt.Errorf("caller %s is not a side of the settlement", arg.Caller)
+		}
+
+		var err error
+		settlement, err = q.CreateSettlement(ctx, params)
+		return err
+	})
+
+	return settlement, err
+}
+
+type RespondToSettlementTxParams struct {
+	ID int64
+	// Username must be the side of the settlement that did not record it.
+	Username string
+	Confirm  bool
+	TypeID   int64
+	Audit    AuditInfo
+}
+
+// RespondToSettlementTx confirms or rejects a pending settlement. Confirming writes the financial record
+// of the responding user, rejecting leaves the record of the other side for them to delete.
+// It returns pgx.ErrNoRows when the settlement does not exist or is not waiting for the user.
+func (store *SQLStore) RespondToSettlementTx(ctx context.Context, arg RespondToSettlementTxParams) (Settlement, error) {
+	var settlement Settlement
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		current, err := q.GetSettlementForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		pendingFrom := current.FromUser.Valid && current.FromUser.String == arg.Username && !current.FromFinancialID.Valid
+		pendingTo := current.ToUser.Valid && current.ToUser.String == arg.Username && !current.ToFinancialID.Valid
+		if current.CreatedBy == arg.Username || (!pendingFrom && !pendingTo) {
+			return pgx.ErrNoRows
+		}
+
+		if current.Status != SettlementStatusPending {
+			return ErrSettlementNotPending
+		}
+
+		params := RespondToSettlementParams{ID: current.ID, Status: SettlementStatusRejected}
+		if arg.Confirm {
+			params.Status = SettlementStatusConfirmed
+
+			if pendingFrom {
+				financial, err := insertSettlementFinancial(ctx, q, arg.Username, -current.Amount, "out", arg.TypeID, arg.Audit)
+				if err != nil {
+					return err
+				}
+				params.FromFinancialID = pgtype.Int8{Int64: financial.ID, Valid: true}
+			} else {
+				financial, err := insertSettlementFinancial(ctx, q, arg.Username, current.Amount, "in", arg.TypeID, arg.Audit)
+				if err != nil {
+					return err
+				}
+				params.ToFinancialID = pgtype.Int8{Int64: financial.ID, Valid: true}
+			}
+		}
+
+		settlement, err = q.RespondToSettlement(ctx, params)
+		return err
+	})
+
+	return settlement, err
+}
+
+func insertSettlementFinancial(ctx context.Context, q *Queries, username string, amount int64, direction string, typeID int64, audit AuditInfo) (Financial, error) {
+	return insertFinancial(ctx, q, InsertNewFinancialParams{
+		UserID:    username,
+		Amount:    amount,
+		Direction: direction,
+		TypeID:    typeID,
+	}, audit)
+}
@@ -7,14 +7,90 @@ package db
 
 import (
 	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const cancelUserDeletion = `-- name: CancelUserDeletion :exec
+UPDATE users
+SET deleted_at = NULL
+WHERE username = $1
+`
+
+func (q *Queries) CancelUserDeletion(ctx context.Context, username string) error {
+	_, err := q.db.Exec(ctx, cancelUserDeletion, username)
+	return err
+}
+
+const countContactVerificationsSince = `-- name: CountContactVerificationsSince :one
+SELECT COUNT(*) FROM contact_verifications
+WHERE username = $1 AND created_at > $2
+`
+
+type CountContactVerificationsSinceParams struct {
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (q *Queries) CountContactVerificationsSince(ctx context.Context, arg CountContactVerificationsSinceParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countContactVerificationsSince, arg.Username, arg.CreatedAt)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createContactVerification = `-- name: CreateContactVerification :one
+WITH replaced AS (
+    UPDATE contact_verifications
+    SET expired_at = NOW()
+    WHERE username = $1 AND field = $2 AND verified_at IS NULL AND expired_at > NOW()
+)
+INSERT INTO contact_verifications
+    (username, field, new_value, code, expired_at)
+VALUES
+    ($1, $2, $3, $4, $5)
+RETURNING id, username, field, new_value, code, expired_at, verified_at, created_at, failed_attempts
+`
+
+type CreateContactVerificationParams struct {
+	Username  string    `json:"username"`
+	Field     string    `json:"field"`
+	NewValue  string    `json:"new_value"`
+	Code      string    `json:"code"`
+	ExpiredAt time.Time `json:"expired_at"`
+}
+
+// the new code replaces the pending codes of the field, they expire now.
+func (q *Queries) CreateContactVerification(ctx context.Context, arg CreateContactVerificationParams) (ContactVerification, error) {
+	row := q.db.QueryRow(ctx, createContactVerification,
+		arg.Username,
+		arg.Field,
+		arg.NewValue,
+		arg.Code,
+		arg.ExpiredAt,
+	)
+	var i ContactVerification
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Field,
+		&i.NewValue,
+		&i.Code,
+		&i.ExpiredAt,
+		&i.VerifiedAt,
+		&i.CreatedAt,
+		&i.FailedAttempts,
+	)
+	return i, err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users(
     username, name, email, phone, password
 ) VALUES(
     $1, $2, $3, $4, $5
-) RETURNING username, name, email, phone, password, created_at, updated_at
+) RETURNING username, name, email, phone, password, created_at, updated_at, deleted_at, role, disabled_at, tokens_valid_after, time_zone, locale, period_start_day, fiscal_year_start_month, email_verified_at
 `
 
 type CreateUserParams struct {
@@ -42,12 +118,61 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Password,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Role,
+		&i.DisabledAt,
+		&i.TokensValidAfter,
+		&i.TimeZone,
+		&i.Locale,
+		&i.PeriodStartDay,
+		&i.FiscalYearStartMonth,
+		&i.EmailVerifiedAt,
+	)
+	return i, err
+}
+
+const deleteUser = `-- name: DeleteUser :exec
+DELETE FROM users
+WHERE username = $1
+`
+
+func (q *Queries) DeleteUser(ctx context.Context, username string) error {
+	_, err := q.db.Exec(ctx, deleteUser, username)
+	return err
+}
+
+const getContactVerification = `-- name: GetContactVerification :one
+SELECT id, username, field, new_value, code, expired_at, verified_at, created_at, failed_attempts FROM contact_verifications
+WHERE username = $1 AND code = $2 AND failed_attempts < $3::int
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+type GetContactVerificationParams struct {
+	Username    string `json:"username"`
+	Code        string `json:"code"`
+	MaxAttempts int32  `json:"max_attempts"`
+}
+
+func (q *Queries) GetContactVerification(ctx context.Context, arg GetContactVerificationParams) (ContactVerification, error) {
+	row := q.db.QueryRow(ctx, getContactVerification, arg.Username, arg.Code, arg.MaxAttempts)
+	var i ContactVerification
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Field,
+		&i.NewValue,
+		&i.Code,
+		&i.ExpiredAt,
+		&i.VerifiedAt,
+		&i.CreatedAt,
+		&i.FailedAttempts,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT username, name, email, phone, password, created_at, updated_at
+SELECT username, name, email, phone, password, created_at, updated_at, deleted_at, role, disabled_at, tokens_valid_after, time_zone, locale, period_start_day, fiscal_year_start_month, email_verified_at
 FROM users where username = $1
 `
 
@@ -62,10 +187,98 @@ func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
 		&i.Password,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Role,
+		&i.DisabledAt,
+		&i.TokensValidAfter,
+		&i.TimeZone,
+		&i.Locale,
+		&i.PeriodStartDay,
+		&i.FiscalYearStartMonth,
+		&i.EmailVerifiedAt,
+	)
+	return i, err
+}
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT username, name, email, phone, password, created_at, updated_at, deleted_at, role, disabled_at, tokens_valid_after, time_zone, locale, period_start_day, fiscal_year_start_month, email_verified_at
+FROM users where email = $1
+`
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByEmail, email)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.Name,
+		&i.Email,
+		&i.Phone,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Role,
+		&i.DisabledAt,
+		&i.TokensValidAfter,
+		&i.TimeZone,
+		&i.Locale,
+		&i.PeriodStartDay,
+		&i.FiscalYearStartMonth,
+		&i.EmailVerifiedAt,
 	)
 	return i, err
 }
 
+const listUsernames = `-- name: ListUsernames :many
+SELECT username FROM users
+ORDER BY username
+`
+
+func (q *Queries) ListUsernames(ctx context.Context) ([]string, error) {
+	rows, err := q.db.Query(ctx, listUsernames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var username string
+		if err := rows.Scan(&username); err != nil {
+			return nil, err
+		}
+		items = append(items, username)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersToPurge = `-- name: ListUsersToPurge :many
+SELECT username FROM users
+WHERE deleted_at IS NOT NULL AND deleted_at < $1
+`
+
+func (q *Queries) ListUsersToPurge(ctx context.Context, deletedAt pgtype.Timestamptz) ([]string, error) {
+	rows, err := q.db.Query(ctx, listUsersToPurge, deletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var username string
+		if err := rows.Scan(&username); err != nil {
+			return nil, err
+		}
+		items = append(items, username)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const loginUser = `-- name: LoginUser :one
 SELECT username, password
 FROM users where username = $1
@@ -83,6 +296,59 @@ func (q *Queries) LoginUser(ctx context.Context, username string) (LoginUserRow,
 	return i, err
 }
 
+const markContactVerified = `-- name: MarkContactVerified :exec
+UPDATE contact_verifications
+SET verified_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) MarkContactVerified(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, markContactVerified, id)
+	return err
+}
+
+const recordContactVerificationFailure = `-- name: RecordContactVerificationFailure :exec
+UPDATE contact_verifications
+SET failed_attempts = failed_attempts + 1
+WHERE username = $1 AND verified_at IS NULL AND expired_at > NOW()
+`
+
+func (q *Queries) RecordContactVerificationFailure(ctx context.Context, username string) error {
+	_, err := q.db.Exec(ctx, recordContactVerificationFailure, username)
+	return err
+}
+
+const scheduleUserDeletion = `-- name: ScheduleUserDeletion :one
+UPDATE users
+SET deleted_at = NOW()
+WHERE username = $1
+RETURNING username, name, email, phone, password, created_at, updated_at, deleted_at, role, disabled_at, tokens_valid_after, time_zone, locale, period_start_day, fiscal_year_start_month, email_verified_at
+`
+
+func (q *Queries) ScheduleUserDeletion(ctx context.Context, username string) (User, error) {
+	row := q.db.QueryRow(ctx, scheduleUserDeletion, username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.Name,
+		&i.Email,
+		&i.Phone,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Role,
+		&i.DisabledAt,
+		&i.TokensValidAfter,
+		&i.TimeZone,
+		&i.Locale,
+		&i.PeriodStartDay,
+		&i.FiscalYearStartMonth,
+		&i.EmailVerifiedAt,
+	)
+	return i, err
+}
+
 const updatePassword = `-- name: UpdatePassword :exec
 UPDATE users
 SET password = $1
@@ -98,3 +364,172 @@ func (q *Queries) UpdatePassword(ctx context.Context, arg UpdatePasswordParams)
 	_, err := q.db.Exec(ctx, updatePassword, arg.Password, arg.Username)
 	return err
 }
+
+const updateUserEmail = `-- name: UpdateUserEmail :one
+UPDATE users
+SET email = $1, email_verified_at = NOW(), updated_at = NOW()
+WHERE username = $2
+RETURNING username, name, email, phone, password, created_at, updated_at, deleted_at, role, disabled_at, tokens_valid_after, time_zone, locale, period_start_day, fiscal_year_start_month, email_verified_at
+`
+
+type UpdateUserEmailParams struct {
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
+
+// Only called once the address is verified.
+func (q *Queries) UpdateUserEmail(ctx context.Context, arg UpdateUserEmailParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserEmail, arg.Email, arg.Username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.Name,
+		&i.Email,
+		&i.Phone,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Role,
+		&i.DisabledAt,
+		&i.TokensValidAfter,
+		&i.TimeZone,
+		&i.Locale,
+		&i.PeriodStartDay,
+		&i.FiscalYearStartMonth,
+		&i.EmailVerifiedAt,
+	)
+	return i, err
+}
+
+const updateUserName = `-- name: UpdateUserName :one
+UPDATE users
+SET name = $1, updated_at = NOW()
+WHERE username = $2
+RETURNING username, name, email, phone, password, created_at, updated_at, deleted_at, role, disabled_at, tokens_valid_after, time_zone, locale, period_start_day, fiscal_year_start_month, email_verified_at
+`
+
+type UpdateUserNameParams struct {
+	Name     string `json:"name"`
+	Username string `json:"username"`
+}
+
+func (q *Queries) UpdateUserName(ctx context.Context, arg UpdateUserNameParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserName, arg.Name, arg.Username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.Name,
+		&i.Email,
+		&i.Phone,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Role,
+		&i.DisabledAt,
+		&i.TokensValidAfter,
+		&i.TimeZone,
+		&i.Locale,
+		&i.PeriodStartDay,
+		&i.FiscalYearStartMonth,
+		&i.EmailVerifiedAt,
+	)
+	return i, err
+}
+
+const updateUserPhone = `-- name: UpdateUserPhone :one
+UPDATE users
+SET phone = $1, updated_at = NOW()
+WHERE username = $2
+RETURNING username, name, email, phone, password, created_at, updated_at, deleted_at, role, disabled_at, tokens_valid_after, time_zone, locale, period_start_day, fiscal_year_start_month, email_verified_at
+`
+
+type UpdateUserPhoneParams struct {
+	Phone    string `json:"phone"`
+	Username string `json:"username"`
+}
+
+func (q *Queries) UpdateUserPhone(ctx context.Context, arg UpdateUserPhoneParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserPhone, arg.Phone, arg.Username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.Name,
+		&i.Email,
+		&i.Phone,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Role,
+		&i.DisabledAt,
+		&i.TokensValidAfter,
+		&i.TimeZone,
+		&i.Locale,
+		&i.PeriodStartDay,
+		&i.FiscalYearStartMonth,
+		&i.EmailVerifiedAt,
+	)
+	return i, err
+}
+
+const isTimeZone = `-- name: IsTimeZone :one
+SELECT EXISTS (
+    SELECT 1 FROM pg_timezone_names
+    WHERE name = $1
+) AS known
+`
+
+// IsTimeZone reports whether Postgres knows the time zone, Go and Postgres ship their own zone databases.
+func (q *Queries) IsTimeZone(ctx context.Context, name string) (bool, error) {
+	row := q.db.QueryRow(ctx, isTimeZone, name)
+	var known bool
+	err := row.Scan(&known)
+	return known, err
+}
+
+const updateUserPreferences = `-- name: UpdateUserPreferences :one
+UPDATE users
+SET time_zone = $1, locale = $2, period_start_day = $3, fiscal_year_start_month = $4, updated_at = NOW()
+WHERE username = $5
+RETURNING username, name, email, phone, password, created_at, updated_at, deleted_at, role, disabled_at, tokens_valid_after, time_zone, locale, period_start_day, fiscal_year_start_month, email_verified_at
+`
+
+type UpdateUserPreferencesParams struct {
+	TimeZone             string `json:"time_zone"`
+	Locale               string `json:"locale"`
+	PeriodStartDay       int32  `json:"period_start_day"`
+	FiscalYearStartMonth int32  `json:"fiscal_year_start_month"`
+	Username             string `json:"username"`
+}
+
+func (q *Queries) UpdateUserPreferences(ctx context.Context, arg UpdateUserPreferencesParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserPreferences,
+		arg.TimeZone,
+		arg.Locale,
+		arg.PeriodStartDay,
+		arg.FiscalYearStartMonth,
+		arg.Username,
+	)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.Name,
+		&i.Email,
+		&i.Phone,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Role,
+		&i.DisabledAt,
+		&i.TokensValidAfter,
+		&i.TimeZone,
+		&i.Locale,
+		&i.PeriodStartDay,
+		&i.FiscalYearStartMonth,
+		&i.EmailVerifiedAt,
+	)
+	return i, err
+}
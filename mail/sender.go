@@ -0,0 +1,29 @@
+package mail
+
+import "log/slog"
+
+type EmailSender interface {
+	SendEmail(to string, subject string, content string) error
+}
+
+type SMSSender interface {
+	SendSMS(to string, content string) error
+}
+
+// LogSender writes emails and text messages to the server log instead of delivering them.
+// It is used until a real provider is configured.
+type LogSender struct{}
+
+func NewLogSender() *LogSender {
+	return &LogSender{}
+}
+
+func (sender *LogSender) SendEmail(to string, subject string, content string) error {
+	slog.Info("send email", "to", to, "subject", subject, "content", content)
+	return nil
+}
+
+func (sender *LogSender) SendSMS(to string, content string) error {
+	slog.Info("send sms", "to", to, "content", content)
+	return nil
+}
@@ -2,12 +2,17 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
-	"time"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sangketkit01/personal-financial/api"
 	db "github.com/sangketkit01/personal-financial/db/sqlc"
+	"github.com/sangketkit01/personal-financial/telemetry"
 	"github.com/sangketkit01/personal-financial/token"
 	"github.com/sangketkit01/personal-financial/util"
 )
@@ -18,24 +23,167 @@ func main() {
 		log.Fatal("cannot load env",err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5 * time.Second)
-	defer cancel()
+	slog.SetDefault(telemetry.NewLogger(os.Stdout, config.LogFormat, config.LogLevel))
+
+	shutdownTracing, err := telemetry.SetupTracing(context.Background(), telemetry.TracingConfig{
+		ServiceName: config.TracingServiceName,
+		Exporter: config.TracingExporter,
+		Endpoint: config.TracingEndpoint,
+		Insecure: config.TracingInsecure,
+		SampleRatio: config.TracingSampleRatio,
+	})
+	if err != nil{
+		fatal("cannot set up tracing", err)
+	}
+	defer flushTraces(shutdownTracing, config)
 
-	database, err := pgx.Connect(ctx, config.DatabaseSource)
+	pool, err := connectPool(config)
 	if err != nil{
-		log.Fatal("cannot connect to database", err)
+		fatal("cannot connect to database", err)
+	}
+	defer pool.Close()
+
+	store := db.NewStore(pool)
+	if len(os.Args) > 1 && os.Args[1] == "rebuild-aggregates" {
+		rebuildAggregates(store, os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "promote-admin" {
+		promoteAdmin(store, os.Args[2:])
+		return
 	}
 
-	store := db.NewStore(database) 
 	tokenMaker, err := token.NewJWTMaker("12345678901234567890123456789012")
 	if err != nil{
-		log.Fatal("cannot create token maker", err)
+		fatal("cannot create token maker", err)
 	}
 
-	_, err = api.NewServer(config, store, tokenMaker)
+	server, err := api.NewServer(config, store, tokenMaker)
 	if err != nil{
-		log.Fatal("cannot start server")
+		fatal("cannot create server", err)
 	}
-	
-	log.Println("Server start at: ", config.ServerPort)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.Start()
+	}()
+
+	slog.Info("server started", "address", server.Address())
+
+	select {
+	case err := <-serverErr:
+		if err != nil {
+			slog.Error("server stopped", "err", err)
+		}
+	case <-ctx.Done():
+		slog.Info("shutting down")
+	}
+	stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ServerShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("cannot shut down cleanly", "err", err)
+	}
+}
+
+// fatal logs the error and exits, deferred calls do not run.
+func fatal(msg string, err error) {
+	slog.Error(msg, "err", err)
+	os.Exit(1)
+}
+
+// flushTraces exports the spans still buffered before the process exits.
+func flushTraces(shutdown func(context.Context) error, config util.Config) {
+	ctx, cancel := context.WithTimeout(context.Background(), config.ServerShutdownTimeout)
+	defer cancel()
+
+	if err := shutdown(ctx); err != nil {
+		slog.Error("cannot flush traces", "err", err)
+	}
+}
+
+// connectPool opens the connection pool and waits until the database answers.
+func connectPool(config util.Config) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(config.DatabaseSource)
+	if err != nil {
+		return nil, err
+	}
+
+	poolConfig.MaxConns = config.DBMaxConns
+	poolConfig.MinConns = config.DBMinConns
+	poolConfig.MaxConnLifetime = config.DBMaxConnLifetime
+	poolConfig.MaxConnIdleTime = config.DBMaxConnIdleTime
+	poolConfig.HealthCheckPeriod = config.DBHealthCheckPeriod
+	poolConfig.ConnConfig.ConnectTimeout = config.DBConnectTimeout
+	poolConfig.ConnConfig.Tracer = telemetry.QueryTracer{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.DBConnectTimeout)
+	defer cancel()
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	return pool, nil
+}
+
+// rebuildAggregates recomputes the monthly aggregates of the given users, or of every user.
+func rebuildAggregates(store db.Store, usernames []string) {
+	ctx := context.Background()
+
+	if len(usernames) == 0 {
+		var err error
+		usernames, err = store.ListUsernames(ctx)
+		if err != nil {
+			fatal("cannot list users", err)
+		}
+	}
+
+	for _, username := range usernames {
+		if err := store.RebuildMonthlyAggregatesTx(ctx, username); err != nil {
+			fatal("cannot rebuild aggregates of "+username, err)
+		}
+	}
+
+	slog.Info("rebuilt the monthly aggregates", "users", len(usernames))
+}
+
+// promoteAdmin gives users the admin role, it is how the first admin is made since only admins can change roles.
+func promoteAdmin(store db.Store, usernames []string) {
+	ctx := context.Background()
+
+	if len(usernames) == 0 {
+		fatal("cannot promote admin", errors.New("usage: promote-admin <username>..."))
+	}
+
+	for _, username := range usernames {
+		audit := db.AdminAuditInfo{
+			Admin:    db.AuditActorSystem,
+			Action:   "user.set_role",
+			Target:   username,
+			ClientIP: "cli",
+		}
+
+		err := store.AdminTx(ctx, audit, func(q *db.Queries) (any, error) {
+			arg := db.SetUserRoleParams{Role: "admin", Username: username}
+			_, err := q.SetUserRole(ctx, arg)
+			return arg, err
+		})
+		if err != nil {
+			fatal("cannot promote "+username, err)
+		}
+	}
+
+	slog.Info("promoted to admin", "users", usernames)
 }
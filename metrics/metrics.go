@@ -0,0 +1,283 @@
+// Package metrics keeps counters, gauges and histograms and writes them in the Prometheus text format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the upper bounds in seconds used for request latencies.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+type metric interface {
+	write(w io.Writer) error
+}
+
+// Registry holds the metrics of a process, in the order they were registered.
+type Registry struct {
+	mu      sync.Mutex
+	metrics []metric
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) register(m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metrics = append(r.metrics, m)
+}
+
+// Write writes every metric in the Prometheus text exposition format.
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	metrics := append([]metric(nil), r.metrics...)
+	r.mu.Unlock()
+
+	for _, m := range metrics {
+		if err := m.write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type desc struct {
+	name   string
+	help   string
+	labels []string
+}
+
+func (d desc) header(w io.Writer, kind string) error {
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", d.name, escapeHelp(d.help), d.name, kind)
+	return err
+}
+
+// key joins label values so a series can be found in a map, the values are kept with it.
+func (d desc) key(values []string) string {
+	if len(values) != len(d.labels) {
+		panic(fmt.Sprintf("metrics: %s takes %d label values, got %d", d.name, len(d.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+func (d desc) labelPairs(values []string, extra ...string) string {
+	pairs := make([]string, 0, len(values)+1)
+	for i, value := range values {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, d.labels[i], escapeLabel(value)))
+	}
+	pairs = append(pairs, extra...)
+
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// CounterVec is a counter split by labels, it only goes up.
+type CounterVec struct {
+	desc
+	mu     sync.Mutex
+	series map[string]*counterSeries
+}
+
+type counterSeries struct {
+	values []string
+	value  float64
+}
+
+// NewCounterVec registers a counter, without labels it is a single series.
+func (r *Registry) NewCounterVec(name string, help string, labels ...string) *CounterVec {
+	c := &CounterVec{
+		desc:   desc{name: name, help: help, labels: labels},
+		series: map[string]*counterSeries{},
+	}
+	if len(labels) == 0 {
+		// a single series is shown from the start, at zero
+		c.series[""] = &counterSeries{}
+	}
+	r.register(c)
+	return c
+}
+
+func (c *CounterVec) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+func (c *CounterVec) Add(delta float64, values ...string) {
+	if delta < 0 {
+		panic(fmt.Sprintf("metrics: counter %s cannot decrease", c.name))
+	}
+
+	key := c.key(values)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, ok := c.series[key]
+	if !ok {
+		s = &counterSeries{values: append([]string(nil), values...)}
+		c.series[key] = s
+	}
+	s.value += delta
+}
+
+func (c *CounterVec) write(w io.Writer) error {
+	if err := c.header(w, "counter"); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range sortedKeys(c.series) {
+		s := c.series[key]
+		if _, err := fmt.Fprintf(w, "%s%s %s\n", c.name, c.labelPairs(s.values), formatFloat(s.value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HistogramVec counts observations into buckets, split by labels.
+type HistogramVec struct {
+	desc
+	buckets []float64
+	mu      sync.Mutex
+	series  map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	values []string
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewHistogramVec registers a histogram with the given bucket upper bounds, sorted ascending.
+func (r *Registry) NewHistogramVec(name string, help string, buckets []float64, labels ...string) *HistogramVec {
+	h := &HistogramVec{
+		desc:    desc{name: name, help: help, labels: labels},
+		buckets: buckets,
+		series:  map[string]*histogramSeries{},
+	}
+	r.register(h)
+	return h
+}
+
+func (h *HistogramVec) Observe(value float64, values ...string) {
+	key := h.key(values)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{
+			values: append([]string(nil), values...),
+			counts: make([]uint64, len(h.buckets)),
+		}
+		h.series[key] = s
+	}
+
+	for i, bound := range h.buckets {
+		if value <= bound {
+			s.counts[i]++
+		}
+	}
+	s.count++
+	s.sum += value
+}
+
+func (h *HistogramVec) write(w io.Writer) error {
+	if err := h.header(w, "histogram"); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+		for i, bound := range h.buckets {
+			le := fmt.Sprintf(`le="%s"`, formatFloat(bound))
+			if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelPairs(s.values, le), s.counts[i]); err != nil {
+				return err
+			}
+		}
+
+		_, err := fmt.Fprintf(w, "%s_bucket%s %d\n%s_sum%s %s\n%s_count%s %d\n",
+			h.name, h.labelPairs(s.values, `le="+Inf"`), s.count,
+			h.name, h.labelPairs(s.values), formatFloat(s.sum),
+			h.name, h.labelPairs(s.values), s.count,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GaugeFunc is a gauge whose value is read when the metrics are written.
+type GaugeFunc struct {
+	desc
+	value func() float64
+}
+
+func (r *Registry) NewGaugeFunc(name string, help string, value func() float64) *GaugeFunc {
+	g := &GaugeFunc{
+		desc:  desc{name: name, help: help},
+		value: value,
+	}
+	r.register(g)
+	return g
+}
+
+func (g *GaugeFunc) write(w io.Writer) error {
+	if err := g.header(w, "gauge"); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "%s %s\n", g.name, formatFloat(g.value()))
+	return err
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
@@ -0,0 +1,59 @@
+package oidc
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// clockSkew is how far the provider clock may drift from ours.
+const clockSkew = time.Minute
+
+// Audience accepts both the string and the array form of the aud claim.
+type Audience []string
+
+func (aud *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*aud = Audience{single}
+		return nil
+	}
+
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+
+	*aud = many
+	return nil
+}
+
+type Claims struct {
+	Issuer        string   `json:"iss"`
+	Subject       string   `json:"sub"`
+	Audience      Audience `json:"aud"`
+	ExpiresAt     int64    `json:"exp"`
+	IssuedAt      int64    `json:"iat"`
+	Nonce         string   `json:"nonce"`
+	Email         string   `json:"email"`
+	EmailVerified bool     `json:"email_verified"`
+	Name          string   `json:"name"`
+}
+
+func (claims *Claims) Valid() error {
+	now := time.Now()
+
+	if claims.Subject == "" {
+		return fmt.Errorf("id token has no subject")
+	}
+
+	if now.After(time.Unix(claims.ExpiresAt, 0).Add(clockSkew)) {
+		return fmt.Errorf("id token has expired")
+	}
+
+	if now.Add(clockSkew).Before(time.Unix(claims.IssuedAt, 0)) {
+		return fmt.Errorf("id token is issued in the future")
+	}
+
+	return nil
+}
@@ -0,0 +1,268 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Config describes the client registration at the identity provider.
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// Provider talks to an OpenID Connect identity provider using the authorization code flow.
+// The discovery document and signing keys are fetched on first use, so the server
+// can start while the provider is still unreachable.
+type Provider struct {
+	config Config
+	client *http.Client
+
+	mu       sync.Mutex
+	metadata *metadata
+	keys     map[string]*rsa.PublicKey
+}
+
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+func NewProvider(config Config) *Provider {
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+
+	return &Provider{
+		config: config,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (provider *Provider) Issuer() string {
+	return provider.config.Issuer
+}
+
+func (provider *Provider) discover(ctx context.Context) (*metadata, error) {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+
+	if provider.metadata != nil {
+		return provider.metadata, nil
+	}
+
+	wellKnown := strings.TrimSuffix(provider.config.Issuer, "/") + "/.well-known/openid-configuration"
+
+	var md metadata
+	if err := provider.getJSON(ctx, wellKnown, &md); err != nil {
+		return nil, fmt.Errorf("cannot get provider metadata: %w", err)
+	}
+
+	if md.Issuer != provider.config.Issuer {
+		return nil, fmt.Errorf("provider issuer %q does not match %q", md.Issuer, provider.config.Issuer)
+	}
+
+	provider.metadata = &md
+	return provider.metadata, nil
+}
+
+// AuthCodeURL returns the provider login page the user should be redirected to.
+func (provider *Provider) AuthCodeURL(ctx context.Context, state string, nonce string, codeChallenge string) (string, error) {
+	md, err := provider.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", provider.config.ClientID)
+	query.Set("redirect_uri", provider.config.RedirectURL)
+	query.Set("scope", strings.Join(provider.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(md.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return md.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange trades an authorization code for the raw ID token.
+func (provider *Provider) Exchange(ctx context.Context, code string, codeVerifier string) (string, error) {
+	md, err := provider.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", provider.config.RedirectURL)
+	form.Set("client_id", provider.config.ClientID)
+	form.Set("code_verifier", codeVerifier)
+	if provider.config.ClientSecret != "" {
+		form.Set("client_secret", provider.config.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, md.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := provider.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("cannot exchange code: %w", err)
+	}
+	defer res.Body.Close()
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("cannot read token response: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("cannot exchange code: %s %s", body.Error, body.ErrorDescription)
+	}
+
+	if body.IDToken == "" {
+		return "", fmt.Errorf("token response has no id_token")
+	}
+
+	return body.IDToken, nil
+}
+
+// Verify checks the signature and claims of an ID token issued for this client.
+func (provider *Provider) Verify(ctx context.Context, rawIDToken string, nonce string) (*Claims, error) {
+	if _, err := provider.discover(ctx); err != nil {
+		return nil, err
+	}
+
+	claims := &Claims{}
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		return provider.key(ctx, kid)
+	}
+
+	if _, err := jwt.ParseWithClaims(rawIDToken, claims, keyFunc); err != nil {
+		return nil, fmt.Errorf("invalid id token: %w", err)
+	}
+
+	if claims.Issuer != provider.config.Issuer {
+		return nil, fmt.Errorf("invalid id token issuer")
+	}
+
+	if !slices.Contains(claims.Audience, provider.config.ClientID) {
+		return nil, fmt.Errorf("id token is not issued for this client")
+	}
+
+	if claims.Nonce != nonce {
+		return nil, fmt.Errorf("invalid id token nonce")
+	}
+
+	return claims, nil
+}
+
+// key returns the signing key with the given id, the key set is fetched again
+// when the id is unknown because the provider may have rotated its keys.
+func (provider *Provider) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+
+	if key, ok := provider.keys[kid]; ok {
+		return key, nil
+	}
+
+	var set struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := provider.getJSON(ctx, provider.metadata.JWKSURI, &set); err != nil {
+		return nil, fmt.Errorf("cannot get signing keys: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, jwk := range set.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			continue
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			continue
+		}
+
+		keys[jwk.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	provider.keys = keys
+
+	key, ok := keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	return key, nil
+}
+
+func (provider *Provider) getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := provider.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", res.Status)
+	}
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
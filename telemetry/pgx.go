@@ -0,0 +1,53 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// QueryTracer starts a span for every query, named after the sqlc query it runs.
+// Set it as the Tracer of the pgx connection config.
+type QueryTracer struct{}
+
+func (QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	ctx, _ = Tracer().Start(ctx, "db."+queryName(data.SQL),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.statement", data.SQL),
+		),
+	)
+	return ctx
+}
+
+func (QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+
+	// no rows is an answer, not a failure of the query
+	if data.Err != nil && !errors.Is(data.Err, pgx.ErrNoRows) {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+		return
+	}
+
+	span.SetAttributes(attribute.Int64("db.rows_affected", data.CommandTag.RowsAffected()))
+}
+
+// queryName reads the name from the "-- name: X :kind" line sqlc puts first, other statements are "query".
+func queryName(sql string) string {
+	line, _, _ := strings.Cut(sql, "\n")
+	name, ok := strings.CutPrefix(line, "-- name: ")
+	if !ok {
+		return "query"
+	}
+
+	name, _, _ = strings.Cut(name, " ")
+	return name
+}
@@ -0,0 +1,84 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing exporters.
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// TracerName is the instrumentation name of the spans of this module.
+const TracerName = "github.com/sangketkit01/personal-financial"
+
+type TracingConfig struct {
+	ServiceName string
+	// Exporter is ExporterNone, ExporterStdout or ExporterOTLP.
+	Exporter string
+	// Endpoint is the host:port of the OTLP/HTTP collector, Insecure sends to it over plain HTTP.
+	Endpoint    string
+	Insecure    bool
+	SampleRatio float64
+}
+
+// Tracer is the tracer of this module, a no-op until SetupTracing installs a provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// SetupTracing installs the global tracer provider and W3C trace context propagation.
+// The returned func flushes the spans still buffered, it must be called before the process exits.
+func SetupTracing(ctx context.Context, config TracingConfig) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+
+	switch config.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterOTLP:
+		options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(config.Endpoint)}
+		if config.Insecure {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, options...)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", config.Exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", config.ServiceName),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
@@ -1,12 +1,17 @@
 package token
 
 import (
-	"fmt"
+	"errors"
 	"time"
 
 	 "github.com/google/uuid"
 )
 
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrExpiredToken = errors.New("token has expired")
+)
+
 type Payload struct {
 	ID uuid.UUID `json:"uuid"`
 	Username  string    `json:"username"`
@@ -32,7 +37,7 @@ func NewPayload(username string, duration time.Duration) (*Payload, error){
 
 func (payload *Payload) Valid() error {
 	if time.Now().After(payload.ExpiredAt) {
-		return fmt.Errorf("token has expired")
+		return ErrExpiredToken
 	}
 	return nil
 }
@@ -0,0 +1,144 @@
+package util
+
+import (
+	"fmt"
+	"time"
+)
+
+// Summary buckets, the names are the date_trunc units of Postgres.
+const (
+	BucketDay     = "day"
+	BucketWeek    = "week"
+	BucketMonth   = "month"
+	BucketQuarter = "quarter"
+	BucketYear    = "year"
+)
+
+func IsBucket(bucket string) bool {
+	switch bucket {
+	case BucketDay, BucketWeek, BucketMonth, BucketQuarter, BucketYear:
+		return true
+	}
+	return false
+}
+
+// Calendar cuts time into the months and years of a user.
+// A month starts on StartDay and is named after the calendar month it starts in,
+// a fiscal year starts with the month FiscalStartMonth and is named after the calendar year it starts in.
+// The zero value is the plain calendar in the location of the times passed in.
+type Calendar struct {
+	Location         *time.Location
+	StartDay         int
+	FiscalStartMonth int
+}
+
+func (c Calendar) in(t time.Time) time.Time {
+	if c.Location == nil {
+		return t
+	}
+	return t.In(c.Location)
+}
+
+func (c Calendar) startDay() int {
+	if c.StartDay < 1 {
+		return 1
+	}
+	return c.StartDay
+}
+
+func (c Calendar) fiscalStartMonth() time.Month {
+	if c.FiscalStartMonth < 1 {
+		return time.January
+	}
+	return time.Month(c.FiscalStartMonth)
+}
+
+// Period returns the year and month of the month holding t.
+func (c Calendar) Period(t time.Time) (int, time.Month) {
+	shifted := c.in(t).AddDate(0, 0, 1-c.startDay())
+	return shifted.Year(), shifted.Month()
+}
+
+// PeriodStart is the first moment of the month named year and month.
+func (c Calendar) PeriodStart(year int, month time.Month) time.Time {
+	loc := c.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	return time.Date(year, month, c.startDay(), 0, 0, 0, 0, loc)
+}
+
+// FiscalYear returns the fiscal year holding t.
+func (c Calendar) FiscalYear(t time.Time) int {
+	year, month := c.Period(t)
+	if month < c.fiscalStartMonth() {
+		return year - 1
+	}
+	return year
+}
+
+// FiscalYearStart is the first moment of the fiscal year.
+func (c Calendar) FiscalYearStart(year int) time.Time {
+	return c.PeriodStart(year, c.fiscalStartMonth())
+}
+
+// fiscalQuarter returns the quarter, from 1 to 4, of a month in its fiscal year.
+func (c Calendar) fiscalQuarter(month time.Month) int {
+	return (int(month-c.fiscalStartMonth())+12)%12/3 + 1
+}
+
+// BucketStart returns the start of the bucket holding t.
+// Weeks are ISO weeks and start on Monday, months, quarters and years follow the calendar.
+func (c Calendar) BucketStart(t time.Time, bucket string) time.Time {
+	t = c.in(t)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+
+	switch bucket {
+	case BucketWeek:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case BucketMonth:
+		year, month := c.Period(t)
+		return time.Date(year, month, c.startDay(), 0, 0, 0, 0, t.Location())
+	case BucketQuarter:
+		year, month := c.Period(t)
+		offset := (int(month-c.fiscalStartMonth()) + 12) % 3
+		return time.Date(year, month-time.Month(offset), c.startDay(), 0, 0, 0, 0, t.Location())
+	case BucketYear:
+		return time.Date(c.FiscalYear(t), c.fiscalStartMonth(), c.startDay(), 0, 0, 0, 0, t.Location())
+	}
+	return day
+}
+
+// NextBucket returns the start of the bucket after the one starting at start.
+func (c Calendar) NextBucket(start time.Time, bucket string) time.Time {
+	switch bucket {
+	case BucketWeek:
+		return start.AddDate(0, 0, 7)
+	case BucketMonth:
+		return start.AddDate(0, 1, 0)
+	case BucketQuarter:
+		return start.AddDate(0, 3, 0)
+	case BucketYear:
+		return start.AddDate(1, 0, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// BucketLabel names the bucket starting at start, e.g. 2026-10-19, 2026-W43, 2026-10, 2026-Q4 or 2026.
+// Quarters and years are fiscal.
+func (c Calendar) BucketLabel(start time.Time, bucket string) string {
+	switch bucket {
+	case BucketWeek:
+		year, week := start.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case BucketMonth:
+		year, month := c.Period(start)
+		return fmt.Sprintf("%d-%02d", year, month)
+	case BucketQuarter:
+		_, month := c.Period(start)
+		return fmt.Sprintf("%d-Q%d", c.FiscalYear(start), c.fiscalQuarter(month))
+	case BucketYear:
+		return fmt.Sprint(c.FiscalYear(start))
+	}
+	return start.Format("2006-01-02")
+}
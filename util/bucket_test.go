@@ -0,0 +1,142 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// payday starts months on the 25th and fiscal years in April.
+var payday = Calendar{Location: time.UTC, StartDay: 25, FiscalStartMonth: 4}
+
+func TestCalendarPeriod(t *testing.T) {
+	testCases := []struct {
+		name     string
+		calendar Calendar
+		t        time.Time
+		year     int
+		month    time.Month
+	}{
+		{"plain calendar", Calendar{Location: time.UTC}, date(2026, time.October, 19), 2026, time.October},
+		{"before the start day", payday, date(2026, time.October, 24), 2026, time.September},
+		{"on the start day", payday, date(2026, time.October, 25), 2026, time.October},
+		{"across the year", payday, date(2026, time.January, 10), 2025, time.December},
+		{
+			"in the location",
+			Calendar{Location: Location("Asia/Bangkok")},
+			time.Date(2026, time.October, 31, 20, 0, 0, 0, time.UTC),
+			2026, time.November,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			year, month := tc.calendar.Period(tc.t)
+			require.Equal(t, tc.year, year)
+			require.Equal(t, tc.month, month)
+		})
+	}
+}
+
+func TestCalendarFiscalYear(t *testing.T) {
+	testCases := []struct {
+		name     string
+		calendar Calendar
+		t        time.Time
+		year     int
+	}{
+		{"plain calendar", Calendar{Location: time.UTC}, date(2026, time.January, 1), 2026},
+		{"before the fiscal start", payday, date(2026, time.April, 24), 2025},
+		{"on the fiscal start", payday, date(2026, time.April, 25), 2026},
+		{"last month of the fiscal year", payday, date(2026, time.March, 30), 2025},
+		{"december", payday, date(2025, time.December, 31), 2025},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.year, tc.calendar.FiscalYear(tc.t))
+		})
+	}
+
+	require.Equal(t, date(2025, time.April, 25), payday.FiscalYearStart(2025))
+	require.Equal(t, date(2026, time.October, 25), payday.PeriodStart(2026, time.October))
+}
+
+func TestCalendarBucketStart(t *testing.T) {
+	plain := Calendar{Location: time.UTC}
+	wednesday := time.Date(2026, time.October, 21, 15, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		calendar Calendar
+		t        time.Time
+		bucket   string
+		start    time.Time
+	}{
+		{"day", plain, wednesday, BucketDay, date(2026, time.October, 21)},
+		{"week", plain, wednesday, BucketWeek, date(2026, time.October, 19)},
+		{"week from sunday", plain, date(2026, time.October, 25), BucketWeek, date(2026, time.October, 19)},
+		{"month", plain, wednesday, BucketMonth, date(2026, time.October, 1)},
+		{"quarter", plain, wednesday, BucketQuarter, date(2026, time.October, 1)},
+		{"year", plain, wednesday, BucketYear, date(2026, time.January, 1)},
+		{"month from the start day", payday, wednesday, BucketMonth, date(2026, time.September, 25)},
+		{"fiscal quarter", payday, wednesday, BucketQuarter, date(2026, time.July, 25)},
+		{"fiscal year", payday, wednesday, BucketYear, date(2026, time.April, 25)},
+		{"last fiscal quarter", payday, date(2026, time.February, 10), BucketQuarter, date(2026, time.January, 25)},
+		{"fiscal year from january", payday, date(2026, time.February, 10), BucketYear, date(2025, time.April, 25)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.start, tc.calendar.BucketStart(tc.t, tc.bucket))
+		})
+	}
+}
+
+func TestCalendarBucketLabel(t *testing.T) {
+	plain := Calendar{Location: time.UTC}
+
+	testCases := []struct {
+		name     string
+		calendar Calendar
+		start    time.Time
+		bucket   string
+		label    string
+	}{
+		{"day", plain, date(2026, time.October, 19), BucketDay, "2026-10-19"},
+		{"week", plain, date(2026, time.October, 19), BucketWeek, "2026-W43"},
+		{"53rd week", plain, date(2026, time.December, 28), BucketWeek, "2026-W53"},
+		{"week of the next year", plain, date(2024, time.December, 30), BucketWeek, "2025-W01"},
+		{"month", plain, date(2026, time.October, 1), BucketMonth, "2026-10"},
+		{"quarter", plain, date(2026, time.October, 1), BucketQuarter, "2026-Q4"},
+		{"year", plain, date(2026, time.January, 1), BucketYear, "2026"},
+		{"month from the start day", payday, date(2026, time.September, 25), BucketMonth, "2026-09"},
+		{"fiscal quarter", payday, date(2026, time.July, 25), BucketQuarter, "2026-Q2"},
+		{"last fiscal quarter", payday, date(2026, time.January, 25), BucketQuarter, "2025-Q4"},
+		{"fiscal year", payday, date(2025, time.April, 25), BucketYear, "2025"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.label, tc.calendar.BucketLabel(tc.start, tc.bucket))
+		})
+	}
+}
+
+func TestCalendarNextBucket(t *testing.T) {
+	for _, bucket := range []string{BucketDay, BucketWeek, BucketMonth, BucketQuarter, BucketYear} {
+		t.Run(bucket, func(t *testing.T) {
+			start := payday.BucketStart(date(2026, time.February, 10), bucket)
+			next := payday.NextBucket(start, bucket)
+
+			// the next bucket starts right where the last one ends
+			require.Equal(t, next, payday.BucketStart(next, bucket))
+			require.Equal(t, start, payday.BucketStart(next.Add(-time.Nanosecond), bucket))
+		})
+	}
+}
@@ -1,11 +1,63 @@
 package util
 
-import "github.com/spf13/viper"
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
 
 type Config struct {
 	DatabaseDriver string `mapstructure:"DB_DRIVER"`
 	DatabaseSource string `mapstructure:"DB_SOURCE"`
 	ServerPort     string `mapstructure:"SERVER_PORT"`
+
+	ServerReadTimeout     time.Duration `mapstructure:"SERVER_READ_TIMEOUT"`
+	ServerWriteTimeout    time.Duration `mapstructure:"SERVER_WRITE_TIMEOUT"`
+	ServerIdleTimeout     time.Duration `mapstructure:"SERVER_IDLE_TIMEOUT"`
+	ServerShutdownTimeout time.Duration `mapstructure:"SERVER_SHUTDOWN_TIMEOUT"`
+
+	LogLevel  string `mapstructure:"LOG_LEVEL"`
+	LogFormat string `mapstructure:"LOG_FORMAT"`
+
+	TracingExporter    string  `mapstructure:"TRACING_EXPORTER"`
+	TracingEndpoint    string  `mapstructure:"TRACING_ENDPOINT"`
+	TracingInsecure    bool    `mapstructure:"TRACING_INSECURE"`
+	TracingSampleRatio float64 `mapstructure:"TRACING_SAMPLE_RATIO"`
+	TracingServiceName string  `mapstructure:"TRACING_SERVICE_NAME"`
+
+	DBMaxConns          int32         `mapstructure:"DB_MAX_CONNS"`
+	DBMinConns          int32         `mapstructure:"DB_MIN_CONNS"`
+	DBConnectTimeout    time.Duration `mapstructure:"DB_CONNECT_TIMEOUT"`
+	DBMaxConnLifetime   time.Duration `mapstructure:"DB_MAX_CONN_LIFETIME"`
+	DBMaxConnIdleTime   time.Duration `mapstructure:"DB_MAX_CONN_IDLE_TIME"`
+	DBHealthCheckPeriod time.Duration `mapstructure:"DB_HEALTH_CHECK_PERIOD"`
+
+	LoginMaxAttempts     int           `mapstructure:"LOGIN_MAX_ATTEMPTS"`
+	LoginBackoffBase     time.Duration `mapstructure:"LOGIN_BACKOFF_BASE"`
+	LoginLockoutDuration time.Duration `mapstructure:"LOGIN_LOCKOUT_DURATION"`
+	LoginFailureWindow   time.Duration `mapstructure:"LOGIN_FAILURE_WINDOW"`
+	UnlockTokenDuration  time.Duration `mapstructure:"UNLOCK_TOKEN_DURATION"`
+
+	VerificationCodeDuration time.Duration `mapstructure:"VERIFICATION_CODE_DURATION"`
+	VerificationMaxAttempts  int32         `mapstructure:"VERIFICATION_MAX_ATTEMPTS"`
+	VerificationMaxSends     int64         `mapstructure:"VERIFICATION_MAX_SENDS"`
+	VerificationSendWindow   time.Duration `mapstructure:"VERIFICATION_SEND_WINDOW"`
+	AccountDeletionGrace     time.Duration `mapstructure:"ACCOUNT_DELETION_GRACE"`
+	AccountPurgeInterval     time.Duration `mapstructure:"ACCOUNT_PURGE_INTERVAL"`
+
+	HouseholdInvitationDuration time.Duration `mapstructure:"HOUSEHOLD_INVITATION_DURATION"`
+
+	OIDCIssuer        string        `mapstructure:"OIDC_ISSUER"`
+	OIDCClientID      string        `mapstructure:"OIDC_CLIENT_ID"`
+	OIDCClientSecret  string        `mapstructure:"OIDC_CLIENT_SECRET"`
+	OIDCRedirectURL   string        `mapstructure:"OIDC_REDIRECT_URL"`
+	OIDCStateDuration time.Duration `mapstructure:"OIDC_STATE_DURATION"`
+
+	TrashRetentionDays int           `mapstructure:"TRASH_RETENTION_DAYS"`
+	TrashPurgeInterval time.Duration `mapstructure:"TRASH_PURGE_INTERVAL"`
+
+	AnomalyDuplicateWindow time.Duration `mapstructure:"ANOMALY_DUPLICATE_WINDOW"`
+	AnomalyScanInterval    time.Duration `mapstructure:"ANOMALY_SCAN_INTERVAL"`
 }
 
 func LoadEnv(path string) (config Config, err error) {
@@ -13,6 +65,46 @@ func LoadEnv(path string) (config Config, err error) {
 	viper.SetConfigName("app")
 	viper.SetConfigType("env")
 
+	viper.SetDefault("SERVER_PORT", "5315")
+	viper.SetDefault("SERVER_READ_TIMEOUT", 10*time.Second)
+	viper.SetDefault("SERVER_WRITE_TIMEOUT", 30*time.Second)
+	viper.SetDefault("SERVER_IDLE_TIMEOUT", 2*time.Minute)
+	viper.SetDefault("SERVER_SHUTDOWN_TIMEOUT", 30*time.Second)
+	viper.SetDefault("LOG_LEVEL", "info")
+	viper.SetDefault("LOG_FORMAT", "json")
+	viper.SetDefault("TRACING_EXPORTER", "none")
+	viper.SetDefault("TRACING_ENDPOINT", "localhost:4318")
+	viper.SetDefault("TRACING_INSECURE", true)
+	viper.SetDefault("TRACING_SAMPLE_RATIO", 1.0)
+	viper.SetDefault("TRACING_SERVICE_NAME", "personal-financial")
+	viper.SetDefault("DB_MAX_CONNS", 10)
+	viper.SetDefault("DB_MIN_CONNS", 2)
+	viper.SetDefault("DB_CONNECT_TIMEOUT", 5*time.Second)
+	viper.SetDefault("DB_MAX_CONN_LIFETIME", time.Hour)
+	viper.SetDefault("DB_MAX_CONN_IDLE_TIME", 30*time.Minute)
+	viper.SetDefault("DB_HEALTH_CHECK_PERIOD", time.Minute)
+	viper.SetDefault("LOGIN_MAX_ATTEMPTS", 5)
+	viper.SetDefault("LOGIN_BACKOFF_BASE", time.Second)
+	viper.SetDefault("LOGIN_LOCKOUT_DURATION", 15*time.Minute)
+	viper.SetDefault("LOGIN_FAILURE_WINDOW", time.Hour)
+	viper.SetDefault("UNLOCK_TOKEN_DURATION", time.Hour)
+	viper.SetDefault("VERIFICATION_CODE_DURATION", 15*time.Minute)
+	viper.SetDefault("VERIFICATION_MAX_ATTEMPTS", 5)
+	viper.SetDefault("VERIFICATION_MAX_SENDS", 5)
+	viper.SetDefault("VERIFICATION_SEND_WINDOW", time.Hour)
+	viper.SetDefault("ACCOUNT_DELETION_GRACE", 30*24*time.Hour)
+	viper.SetDefault("ACCOUNT_PURGE_INTERVAL", time.Hour)
+	viper.SetDefault("HOUSEHOLD_INVITATION_DURATION", 7*24*time.Hour)
+	viper.SetDefault("OIDC_ISSUER", "")
+	viper.SetDefault("OIDC_CLIENT_ID", "")
+	viper.SetDefault("OIDC_CLIENT_SECRET", "")
+	viper.SetDefault("OIDC_REDIRECT_URL", "")
+	viper.SetDefault("OIDC_STATE_DURATION", 10*time.Minute)
+	viper.SetDefault("TRASH_RETENTION_DAYS", 30)
+	viper.SetDefault("TRASH_PURGE_INTERVAL", 24*time.Hour)
+	viper.SetDefault("ANOMALY_DUPLICATE_WINDOW", 24*time.Hour)
+	viper.SetDefault("ANOMALY_SCAN_INTERVAL", 24*time.Hour)
+
 	viper.AutomaticEnv()
 	err = viper.ReadInConfig()
 	if err != nil {
@@ -0,0 +1,59 @@
+package util
+
+import "time"
+
+// ForecastPoint is one projected day or month. Expense is negative like the stored amounts.
+type ForecastPoint struct {
+	Start    time.Time `json:"start"`
+	Income   int64     `json:"income"`
+	Expense  int64     `json:"expense"`
+	Net      int64     `json:"net"`
+	Balance  int64     `json:"balance"`
+	Negative bool      `json:"negative"`
+}
+
+// MonthStart returns midnight of the first day of the month of t.
+func MonthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// Forecast projects the balance from opening for the given months, starting at the month of start.
+// Every month gets the same monthly income and expense. With daily points a month is spread
+// over its days, rounding is carried so the days always add up to the monthly amounts.
+func Forecast(opening int64, start time.Time, months int, income int64, expense int64, daily bool) []ForecastPoint {
+	var points []ForecastPoint
+	balance := opening
+
+	add := func(start time.Time, income int64, expense int64) {
+		balance += income + expense
+		points = append(points, ForecastPoint{
+			Start:    start,
+			Income:   income,
+			Expense:  expense,
+			Net:      income + expense,
+			Balance:  balance,
+			Negative: balance < 0,
+		})
+	}
+
+	month := MonthStart(start)
+	for i := 0; i < months; i++ {
+		if !daily {
+			add(month, income, expense)
+			month = month.AddDate(0, 1, 0)
+			continue
+		}
+
+		days := int64(month.AddDate(0, 1, -1).Day())
+		for day := int64(1); day <= days; day++ {
+			add(
+				month.AddDate(0, 0, int(day-1)),
+				income*day/days-income*(day-1)/days,
+				expense*day/days-expense*(day-1)/days,
+			)
+		}
+		month = month.AddDate(0, 1, 0)
+	}
+
+	return points
+}
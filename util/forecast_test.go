@@ -0,0 +1,64 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestForecastMonthly(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opening  int64
+		months   int
+		balances []int64
+		negative []bool
+	}{
+		{"stays positive", 1000, 3, []int64{800, 600, 400}, []bool{false, false, false}},
+		{"goes negative", 300, 3, []int64{100, -100, -300}, []bool{false, true, true}},
+		{"no months", 1000, 0, nil, nil},
+	}
+
+	start := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			points := Forecast(tc.opening, start, tc.months, 500, -700, false)
+			require.Len(t, points, len(tc.balances))
+
+			for i, point := range points {
+				require.Equal(t, time.Date(2026, time.October+time.Month(i), 1, 0, 0, 0, 0, time.UTC), point.Start)
+				require.Equal(t, int64(500), point.Income)
+				require.Equal(t, int64(-700), point.Expense)
+				require.Equal(t, int64(-200), point.Net)
+				require.Equal(t, tc.balances[i], point.Balance)
+				require.Equal(t, tc.negative[i], point.Negative)
+			}
+		})
+	}
+}
+
+func TestForecastDaily(t *testing.T) {
+	start := time.Date(2026, time.February, 10, 0, 0, 0, 0, time.UTC)
+	points := Forecast(1000, start, 2, 2800, -100, true)
+
+	// february 2026 has 28 days, march 31
+	require.Len(t, points, 28+31)
+	require.Equal(t, time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC), points[0].Start)
+	require.Equal(t, time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC), points[28].Start)
+
+	for _, month := range [][]ForecastPoint{points[:28], points[28:]} {
+		var income, expense int64
+		for _, point := range month {
+			// the rounding is spread, no day is off by more than one from the others
+			require.InDelta(t, 2800/float64(len(month)), point.Income, 1)
+			require.InDelta(t, -100/float64(len(month)), point.Expense, 1)
+			income += point.Income
+			expense += point.Expense
+		}
+		require.Equal(t, int64(2800), income)
+		require.Equal(t, int64(-100), expense)
+	}
+
+	require.Equal(t, int64(1000+2*2700), points[len(points)-1].Balance)
+}
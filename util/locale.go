@@ -0,0 +1,88 @@
+package util
+
+import (
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+const (
+	DefaultTimeZone = "UTC"
+	DefaultLocale   = "en-US"
+)
+
+// dateLayouts holds the date format of a language, or of a language and region.
+// Anything else falls back to ISO dates.
+var dateLayouts = map[string]string{
+	"en":    "2 Jan 2006",
+	"en-US": "Jan 2, 2006",
+	"th":    "2/1/2006",
+	"de":    "02.01.2006",
+	"fr":    "02/01/2006",
+	"ja":    "2006/01/02",
+}
+
+// Location loads an IANA time zone, falling back to UTC for an empty or unknown name.
+func Location(name string) *time.Location {
+	if name == "" || name == "Local" {
+		return time.UTC
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// IsTimeZone reports whether name is an IANA time zone in the zone database of Go.
+// Postgres ships its own, so anything stored for the database is checked there again.
+func IsTimeZone(name string) bool {
+	if name == "" || name == "Local" {
+		return false
+	}
+
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
+
+// IsLocale reports whether locale is a well-formed BCP 47 tag like th-TH.
+func IsLocale(locale string) bool {
+	_, err := language.Parse(locale)
+	return err == nil
+}
+
+func localeTag(locale string) language.Tag {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return language.MustParse(DefaultLocale)
+	}
+	return tag
+}
+
+// FormatAmount writes amount with the digit grouping of the locale, e.g. 1,234,567 or 1.234.567.
+func FormatAmount(locale string, amount int64) string {
+	return message.NewPrinter(localeTag(locale)).Sprint(number.Decimal(amount))
+}
+
+// FormatDate writes the date of t in the usual order of the locale.
+func FormatDate(locale string, t time.Time) string {
+	tag := localeTag(locale)
+	if layout, ok := dateLayouts[tag.String()]; ok {
+		return t.Format(layout)
+	}
+
+	base, _ := tag.Base()
+	if layout, ok := dateLayouts[base.String()]; ok {
+		return t.Format(layout)
+	}
+
+	return t.Format("2006-01-02")
+}
+
+// FormatDateTime is FormatDate followed by the 24-hour time and the zone abbreviation.
+func FormatDateTime(locale string, t time.Time) string {
+	return FormatDate(locale, t) + " " + t.Format("15:04 MST")
+}
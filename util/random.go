@@ -0,0 +1,32 @@
+package util
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"strings"
+)
+
+// RandomToken returns a hex encoded string built from n random bytes.
+func RandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// RandomDigits returns a string of n random decimal digits, e.g. for one-time codes.
+func RandomDigits(n int) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		d, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(d.String())
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,213 @@
+package util
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+// SplitEqual divides total into n parts that add up to total.
+// The parts differ by at most one, the first parts take the remainder.
+func SplitEqual(total int64, n int) []int64 {
+	if n <= 0 {
+		return nil
+	}
+
+	parts := make([]int64, n)
+	base := total / int64(n)
+	remainder := total % int64(n)
+
+	for i := range parts {
+		parts[i] = base
+		if int64(i) < remainder {
+			parts[i]++
+		}
+	}
+
+	return parts
+}
+
+// SplitByShares divides total proportionally to shares, e.g. shares 1, 2 split 90 into 30, 60.
+// The rounding leftover goes to the first parts so the result always adds up to total.
+func SplitByShares(total int64, shares []int64) ([]int64, error) {
+	if total < 0 {
+		return nil, fmt.Errorf("total cannot be negative")
+	}
+
+	var totalShares int64
+	for _, share := range shares {
+		if share < 0 {
+			return nil, fmt.Errorf("shares cannot be negative")
+		}
+		if share > math.MaxInt64-totalShares {
+			return nil, fmt.Errorf("shares are too large")
+		}
+		totalShares += share
+	}
+
+	if totalShares == 0 {
+		return nil, fmt.Errorf("shares must add up to more than zero")
+	}
+
+	parts := make([]int64, len(shares))
+	var assigned int64
+	for i, share := range shares {
+		// total*share takes 128 bits, the quotient fits again since share <= totalShares
+		hi, lo := bits.Mul64(uint64(total), uint64(share))
+		quotient, _ := bits.Div64(hi, lo, uint64(totalShares))
+		parts[i] = int64(quotient)
+		assigned += parts[i]
+	}
+
+	for i := 0; assigned < total; i = (i + 1) % len(parts) {
+		if shares[i] == 0 {
+			continue
+		}
+		parts[i]++
+		assigned++
+	}
+
+	return parts, nil
+}
+
+type Transfer struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Amount int64  `json:"amount"`
+}
+
+// exactSettleUpLimit is the most people with a balance SettleUp finds the fewest transfers for,
+// the search grows with 2^n.
+const exactSettleUpLimit = 16
+
+// SettleUp suggests the fewest transfers that clear every balance.
+// A positive balance means the person is owed money, a negative one means they owe it.
+//
+// A group of people whose balances add up to zero can settle among themselves with one transfer less than its size,
+// so the fewest transfers is the number of people minus the most groups they split into. Those groups are found
+// exactly for up to exactSettleUpLimit people, above that everyone is one group, which takes at most n-1 transfers.
+// Inside a group the largest debtor pays the largest creditor.
+func SettleUp(balances map[string]int64) []Transfer {
+	keys := make([]string, 0, len(balances))
+	for key, amount := range balances {
+		if amount != 0 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	transfers := []Transfer{}
+	for _, group := range zeroSumGroups(keys, balances) {
+		transfers = append(transfers, settleGroup(group, balances)...)
+	}
+
+	return transfers
+}
+
+// zeroSumGroups splits keys into the most groups whose balances add up to zero.
+func zeroSumGroups(keys []string, balances map[string]int64) [][]string {
+	n := len(keys)
+	if n == 0 {
+		return nil
+	}
+	if n > exactSettleUpLimit {
+		return [][]string{keys}
+	}
+
+	full := 1<<n - 1
+	sums := make([]int64, full+1)
+	// groups[mask] is the most zero-sum groups the people in mask split into, counting a leftover as none
+	groups := make([]int, full+1)
+	for mask := 1; mask <= full; mask++ {
+		low := mask & -mask
+		i := bits.TrailingZeros(uint(low))
+		sums[mask] = sums[mask^low] + balances[keys[i]]
+
+		for rest := mask; rest != 0; rest &= rest - 1 {
+			bit := rest & -rest
+			groups[mask] = max(groups[mask], groups[mask^bit])
+		}
+		if sums[mask] == 0 {
+			groups[mask]++
+		}
+	}
+
+	// take people out one by one along the best path, a group ends whenever what is left adds up to zero again
+	var result [][]string
+	var current []string
+	for mask := full; mask != 0; {
+		want := groups[mask]
+		if sums[mask] == 0 {
+			want--
+		}
+
+		for rest := mask; rest != 0; rest &= rest - 1 {
+			bit := rest & -rest
+			if groups[mask^bit] == want {
+				current = append(current, keys[bits.TrailingZeros(uint(bit))])
+				mask ^= bit
+				break
+			}
+		}
+
+		if sums[mask] == 0 {
+			result = append(result, current)
+			current = nil
+		}
+	}
+
+	return result
+}
+
+// settleGroup clears the balances of a group that adds up to zero, with at most one transfer less than its size.
+func settleGroup(group []string, balances map[string]int64) []Transfer {
+	type entry struct {
+		key    string
+		amount int64
+	}
+
+	var creditors, debtors []entry
+	for _, key := range group {
+		amount := balances[key]
+		if amount > 0 {
+			creditors = append(creditors, entry{key, amount})
+		} else if amount < 0 {
+			debtors = append(debtors, entry{key, -amount})
+		}
+	}
+
+	byAmount := func(entries []entry) func(i, j int) bool {
+		return func(i, j int) bool {
+			if entries[i].amount == entries[j].amount {
+				return entries[i].key < entries[j].key
+			}
+			return entries[i].amount > entries[j].amount
+		}
+	}
+
+	var transfers []Transfer
+	for len(creditors) > 0 && len(debtors) > 0 {
+		sort.Slice(creditors, byAmount(creditors))
+		sort.Slice(debtors, byAmount(debtors))
+
+		amount := min(creditors[0].amount, debtors[0].amount)
+		transfers = append(transfers, Transfer{
+			From:   debtors[0].key,
+			To:     creditors[0].key,
+			Amount: amount,
+		})
+
+		creditors[0].amount -= amount
+		debtors[0].amount -= amount
+
+		if creditors[0].amount == 0 {
+			creditors = creditors[1:]
+		}
+		if debtors[0].amount == 0 {
+			debtors = debtors[1:]
+		}
+	}
+
+	return transfers
+}
@@ -0,0 +1,152 @@
+package util
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitEqual(t *testing.T) {
+	testCases := []struct {
+		total int64
+		n     int
+		parts []int64
+	}{
+		{90, 3, []int64{30, 30, 30}},
+		{100, 3, []int64{34, 33, 33}},
+		{2, 3, []int64{1, 1, 0}},
+		{100, 0, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%d by %d", tc.total, tc.n), func(t *testing.T) {
+			require.Equal(t, tc.parts, SplitEqual(tc.total, tc.n))
+		})
+	}
+}
+
+func TestSplitByShares(t *testing.T) {
+	testCases := []struct {
+		name   string
+		total  int64
+		shares []int64
+		parts  []int64
+		err    bool
+	}{
+		{name: "proportional", total: 90, shares: []int64{1, 2}, parts: []int64{30, 60}},
+		{name: "remainder goes to the first parts", total: 100, shares: []int64{1, 1, 1}, parts: []int64{34, 33, 33}},
+		{name: "remainder skips zero shares", total: 5, shares: []int64{0, 1, 1}, parts: []int64{0, 3, 2}},
+		{name: "large total", total: math.MaxInt64, shares: []int64{1, 1}, parts: []int64{math.MaxInt64/2 + 1, math.MaxInt64 / 2}},
+		{name: "large shares", total: 1000, shares: []int64{math.MaxInt64 / 4, math.MaxInt64 / 4 * 3}, parts: []int64{250, 750}},
+		{name: "negative share", total: 100, shares: []int64{1, -1}, err: true},
+		{name: "no shares", total: 100, shares: []int64{0, 0}, err: true},
+		{name: "shares overflow", total: 100, shares: []int64{math.MaxInt64, 1}, err: true},
+		{name: "negative total", total: -100, shares: []int64{1, 1}, err: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parts, err := SplitByShares(tc.total, tc.shares)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.parts, parts)
+		})
+	}
+}
+
+// requireSettled checks that the transfers clear every balance.
+func requireSettled(t *testing.T, balances map[string]int64, transfers []Transfer) {
+	t.Helper()
+
+	left := map[string]int64{}
+	for key, amount := range balances {
+		left[key] = amount
+	}
+
+	for _, transfer := range transfers {
+		require.Positive(t, transfer.Amount)
+		left[transfer.From] += transfer.Amount
+		left[transfer.To] -= transfer.Amount
+	}
+
+	for key, amount := range left {
+		require.Zero(t, amount, key)
+	}
+}
+
+func TestSettleUp(t *testing.T) {
+	testCases := []struct {
+		name      string
+		balances  map[string]int64
+		transfers []Transfer
+	}{
+		{
+			name:      "nothing owed",
+			balances:  map[string]int64{"alice": 0, "bob": 0},
+			transfers: []Transfer{},
+		},
+		{
+			name:      "one debt",
+			balances:  map[string]int64{"alice": 10, "bob": -10},
+			transfers: []Transfer{{From: "bob", To: "alice", Amount: 10}},
+		},
+		{
+			name:     "one creditor",
+			balances: map[string]int64{"alice": 30, "bob": -20, "carol": -10},
+			transfers: []Transfer{
+				{From: "bob", To: "alice", Amount: 20},
+				{From: "carol", To: "alice", Amount: 10},
+			},
+		},
+		{
+			// the largest debtor paying the largest creditor takes four transfers here
+			name:     "zero-sum groups",
+			balances: map[string]int64{"a": 6, "b": 4, "c": -4, "d": -3, "e": -3},
+			transfers: []Transfer{
+				{From: "c", To: "b", Amount: 4},
+				{From: "d", To: "a", Amount: 3},
+				{From: "e", To: "a", Amount: 3},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			transfers := SettleUp(tc.balances)
+			require.ElementsMatch(t, tc.transfers, transfers)
+			requireSettled(t, tc.balances, transfers)
+		})
+	}
+}
+
+func TestSettleUpAboveExactLimit(t *testing.T) {
+	balances := map[string]int64{}
+	for i := range exactSettleUpLimit + 1 {
+		balances[fmt.Sprintf("creditor-%d", i)] = int64(i + 1)
+		balances[fmt.Sprintf("debtor-%d", i)] = -int64(i + 1)
+	}
+
+	transfers := SettleUp(balances)
+	requireSettled(t, balances, transfers)
+	require.LessOrEqual(t, len(transfers), len(balances)-1)
+}
+
+func TestZeroSumGroups(t *testing.T) {
+	balances := map[string]int64{"a": 5, "b": -5, "c": 2, "d": 1, "e": -3}
+	groups := zeroSumGroups([]string{"a", "b", "c", "d", "e"}, balances)
+
+	require.Len(t, groups, 2)
+	for _, group := range groups {
+		var sum int64
+		for _, key := range group {
+			sum += balances[key]
+		}
+		require.Zero(t, sum)
+	}
+}
@@ -0,0 +1,50 @@
+package util
+
+import (
+	"math"
+	"slices"
+)
+
+// MeanStdDev returns the mean and the population standard deviation of values.
+func MeanStdDev(values []int64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	var sum float64
+	for _, v := range values {
+		sum += float64(v)
+	}
+	mean := sum / float64(len(values))
+
+	var squares float64
+	for _, v := range values {
+		squares += (float64(v) - mean) * (float64(v) - mean)
+	}
+
+	return mean, math.Sqrt(squares / float64(len(values)))
+}
+
+// Percentile returns the nearest-rank percentile of values, p is between 0 and 100.
+func Percentile(values []int64, p float64) int64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+func Abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,55 @@
+package util
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeanStdDev(t *testing.T) {
+	testCases := []struct {
+		name   string
+		values []int64
+		mean   float64
+		stdDev float64
+	}{
+		{"empty", nil, 0, 0},
+		{"single value", []int64{42}, 42, 0},
+		{"population", []int64{2, 4, 4, 4, 5, 5, 7, 9}, 5, 2},
+		{"negative", []int64{-10, -20}, -15, 5},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mean, stdDev := MeanStdDev(tc.values)
+			require.InDelta(t, tc.mean, mean, 1e-9)
+			require.InDelta(t, tc.stdDev, stdDev, 1e-9)
+		})
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := []int64{50, 15, 40, 20, 35}
+
+	testCases := []struct {
+		p     float64
+		value int64
+	}{
+		{0, 15},
+		{30, 20},
+		{40, 20},
+		{50, 35},
+		{90, 50},
+		{100, 50},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprint(tc.p), func(t *testing.T) {
+			require.Equal(t, tc.value, Percentile(values, tc.p))
+		})
+	}
+
+	require.Zero(t, Percentile(nil, 50))
+	require.Equal(t, []int64{50, 15, 40, 20, 35}, values, "the values are not sorted in place")
+}